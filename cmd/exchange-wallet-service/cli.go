@@ -2,20 +2,34 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"exchange-wallet-service/common/cliapp"
 	"exchange-wallet-service/common/opio"
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/fee"
 	flags2 "exchange-wallet-service/flags"
+	"exchange-wallet-service/metrics"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/reserves"
+	"exchange-wallet-service/risk"
 	"exchange-wallet-service/rpcclient"
 	"exchange-wallet-service/rpcclient/chainsunion"
+	"exchange-wallet-service/screening"
 	"exchange-wallet-service/services"
+	"exchange-wallet-service/tlsutil"
+	"exchange-wallet-service/tracing"
 	"exchange-wallet-service/worker"
+	"fmt"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/google/uuid"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"math/big"
+	"os"
 	"time"
 )
 
@@ -57,6 +71,44 @@ func NewCli(GitCommit string, GitData string) *cli.App {
 				Description: "Run rpc scanner wallet chain node",
 				Action:      cliapp.LifecycleCmd(runAllWorker),
 			},
+			{
+				Name:        "reconcile",
+				Flags:       flags,
+				Description: "Reconcile database balances against on-chain balances once",
+				Action:      runReconcile,
+			},
+			{
+				Name: "proof-of-reserves",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{Name: "request-id", Usage: "Business id to export", Required: true},
+					&cli.StringFlag{Name: "block-number", Usage: "Report at the latest balance snapshot at or before this block, defaults to the last synced block"},
+					&cli.StringFlag{Name: "output", Usage: "Output json file, defaults to stdout"},
+				}, flags...),
+				Description: "Generate and store a proof-of-reserves dataset with a merkle tree of user balances",
+				Action:      runProofOfReserves,
+			},
+			{
+				Name: "fee-report",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{Name: "request-id", Usage: "Business id to report, defaults to all businesses"},
+					&cli.Uint64Flag{Name: "start-time", Usage: "Start unix timestamp in seconds"},
+					&cli.Uint64Flag{Name: "end-time", Usage: "End unix timestamp in seconds, 0 for no limit"},
+					&cli.StringFlag{Name: "output", Usage: "Output json file, defaults to stdout"},
+				}, flags...),
+				Description: "Export a fee report summed by business, transaction type, token and day",
+				Action:      runFeeReport,
+			},
+			{
+				Name: "handle-hold",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{Name: "request-id", Usage: "Business id of the held transaction", Required: true},
+					&cli.StringFlag{Name: "tx-type", Usage: "Held transaction type: deposit or withdraw", Required: true},
+					&cli.StringFlag{Name: "transaction-id", Usage: "Held transaction id", Required: true},
+					&cli.StringFlag{Name: "action", Usage: "release or reject", Required: true},
+				}, flags...),
+				Description: "Release or reject a deposit or withdraw held by address screening or risk scoring",
+				Action:      runHandleHold,
+			},
 		},
 	}
 }
@@ -93,11 +145,22 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 		return nil, err
 	}
 	grpcServerConfig := &config.WalletBusinessConfig{
-		GrpcHostName: cfg.RpcServer.Host,
-		GrpcPort:     cfg.RpcServer.Port,
+		GrpcHostName:             cfg.RpcServer.Host,
+		GrpcPort:                 cfg.RpcServer.Port,
+		DisperseContract:         cfg.Disperse.ContractAddress,
+		FeeLegacy:                cfg.Fee.Legacy,
+		GasLimitMargin:           cfg.Fee.GasLimitMargin,
+		MetricsHostName:          cfg.MetricsServer.Host,
+		MetricsPort:              cfg.MetricsServer.Port,
+		TracingEndpoint:          cfg.Tracing.Endpoint,
+		TracingSampleRatio:       cfg.Tracing.SampleRatio,
+		TLS:                      cfg.RpcServerTLS,
+		RateLimitRefreshInterval: cfg.RateLimit.RefreshInterval,
+		ApiCacheEnable:           cfg.ApiCacheEnable,
+		ApiCache:                 cfg.CacheConfig,
 	}
 	/*  1.数据库*/
-	db, err := database.NewDB(context.Background(), cfg.MasterDB)
+	db, err := database.NewDBWithReplica(context.Background(), &cfg)
 	if err != nil {
 		log.Error("failed to connect database", "err", err)
 		return nil, err
@@ -105,7 +168,12 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 	log.Info("successfully connected to database")
 	/* 2. 新建 chains-union-rpc client*/
 	log.Info("creating chains-union-rpc client")
-	conn, err := grpc.NewClient(cfg.ChainsUnionRpc, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds, err := tlsutil.ClientCredentials(cfg.ChainsUnionTLS)
+	if err != nil {
+		log.Error("failed to load chains-union-rpc tls config", "err", err)
+		return nil, err
+	}
+	conn, err := grpc.NewClient(cfg.ChainsUnionRpc, grpc.WithTransportCredentials(creds), grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor), tracing.DialOption())
 	if err != nil {
 		log.Error("Connect to da retriever fail", "err", err)
 		return nil, err
@@ -118,8 +186,34 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 	}
 	log.Info("successfully connected to chains-union-rpc client", "chains-union-rpc client", &rpcClient)
 
-	/*3. grpc 服务启动 */
-	return services.NewWalletBusinessService(grpcServerConfig, db, rpcClient)
+	/*3. 地址筛查*/
+	screener, err := screening.NewScreener(cfg.Screening)
+	if err != nil {
+		log.Error("failed to create screener", "err", err)
+		return nil, err
+	}
+
+	/*4. 风险评分*/
+	scorer, err := risk.NewRiskScorer(cfg.Risk)
+	if err != nil {
+		log.Error("failed to create risk scorer", "err", err)
+		return nil, err
+	}
+
+	/*5. 费率策略，开启 gas 估算时直连链节点*/
+	feeStrategy := fee.NewStrategy(cfg.Fee.Legacy)
+	var gasEstimator rpcclient.GasEstimator
+	if cfg.Fee.GasEstimateEnable {
+		ethClient, err := rpcclient.NewEthClient(context.Background(), cfg.ChainNode.RpcUrl)
+		if err != nil {
+			log.Error("failed to connect to eth node", "err", err)
+			return nil, err
+		}
+		gasEstimator = ethClient
+	}
+
+	/*6. grpc 服务启动 */
+	return services.NewWalletBusinessService(grpcServerConfig, db, rpcClient, screener, scorer, feeStrategy, gasEstimator)
 }
 
 /*启动所有定时任务，扫链，处理充值、提现、内部、回滚*/
@@ -132,3 +226,214 @@ func runAllWorker(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Li
 	}
 	return worker.NewAllWorker(ctx.Context, &cfg, shutdown)
 }
+
+/*余额对账命令：执行一轮对账后退出*/
+func runReconcile(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	log.Info("starting reconcile")
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	db, err := database.NewDBWithReplica(ctx.Context, &cfg)
+	if err != nil {
+		log.Error("failed to connect database", "err", err)
+		return err
+	}
+	defer func(db *database.DB) {
+		err := db.Close()
+		if err != nil {
+			log.Error("failed to close database connection", "err", err)
+		}
+	}(db)
+	creds, err := tlsutil.ClientCredentials(cfg.ChainsUnionTLS)
+	if err != nil {
+		log.Error("failed to load chains-union-rpc tls config", "err", err)
+		return err
+	}
+	conn, err := grpc.NewClient(cfg.ChainsUnionRpc, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Error("failed to connect to chains-union-rpc", "err", err)
+		return err
+	}
+	defer conn.Close()
+	rpcClient, err := rpcclient.NewChainsUnionRpcClient(ctx.Context, chainsunion.NewChainsUnionServiceClient(conn), "Ethereum")
+	if err != nil {
+		log.Error("failed to create chains-union-rpc client", "err", err)
+		return err
+	}
+	reconciler, err := worker.NewReconciler(&cfg, db, rpcClient, func(error) {})
+	if err != nil {
+		return err
+	}
+	reports, err := reconciler.ReconcileAll()
+	for _, report := range reports {
+		log.Info("reconcile report", "businessId", report.BusinessId, "roundId", report.RoundId, "checked", report.Checked, "failed", report.Failed, "ledgerMismatches", report.LedgerMismatches, "discrepancies", len(report.Discrepancies))
+	}
+	return err
+}
+
+/*储备证明导出命令：生成钱包链上余额与用户余额 Merkle 树，输出 JSON*/
+func runProofOfReserves(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	log.Info("starting proof of reserves export")
+	var blockNumber *big.Int
+	if ctx.String("block-number") != "" {
+		number, ok := new(big.Int).SetString(ctx.String("block-number"), 10)
+		if !ok {
+			return fmt.Errorf("invalid block number: %s", ctx.String("block-number"))
+		}
+		blockNumber = number
+	}
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	/*同步区块与余额快照须从同一个库读取，使用主库*/
+	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
+	if err != nil {
+		log.Error("failed to connect database", "err", err)
+		return err
+	}
+	defer func(db *database.DB) {
+		err := db.Close()
+		if err != nil {
+			log.Error("failed to close database connection", "err", err)
+		}
+	}(db)
+	/*储备按区块查询链上余额，直连链节点*/
+	ethClient, err := rpcclient.NewEthClient(ctx.Context, cfg.ChainNode.RpcUrl)
+	if err != nil {
+		log.Error("failed to connect to eth node", "err", err)
+		return err
+	}
+	defer ethClient.Close()
+	report, err := reserves.NewBuilder(db, ethClient).Build(ctx.Context, ctx.String("request-id"), blockNumber)
+	if err != nil {
+		log.Error("failed to build proof of reserves", "err", err)
+		return err
+	}
+	/*存库供 rpc 接口查询*/
+	stored, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	reportBlock, _ := new(big.Int).SetString(report.BlockNumber, 10)
+	err = db.ProofOfReserves.StoreProofOfReserves(ctx.String("request-id"), &database.ProofOfReserves{
+		GUID:        uuid.New(),
+		BlockNumber: reportBlock,
+		MerkleRoot:  report.Liabilities.Root,
+		Report:      string(stored),
+		Timestamp:   report.GeneratedAt,
+	})
+	if err != nil {
+		log.Error("failed to store proof of reserves", "err", err)
+		return err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if ctx.String("output") == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return os.WriteFile(ctx.String("output"), data, 0o644)
+}
+
+/*手续费报表命令：可跨项目方汇总，rpc 接口只允许查询单个项目方*/
+func runFeeReport(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	log.Info("starting fee report export")
+	startTime, endTime := ctx.Uint64("start-time"), ctx.Uint64("end-time")
+	if endTime > 0 && endTime < startTime {
+		return errors.New("end time must not be before start time")
+	}
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	db, err := database.NewDBWithReplica(ctx.Context, &cfg)
+	if err != nil {
+		log.Error("failed to connect database", "err", err)
+		return err
+	}
+	defer func(db *database.DB) {
+		err := db.Close()
+		if err != nil {
+			log.Error("failed to close database connection", "err", err)
+		}
+	}(db)
+	reader := db.Replica()
+	businessIds := []string{ctx.String("request-id")}
+	if ctx.String("request-id") == "" {
+		businessList, err := reader.Business.QueryBusinessList()
+		if err != nil {
+			log.Error("failed to query business list", "err", err)
+			return err
+		}
+		businessIds = businessIds[:0]
+		for _, business := range businessList {
+			businessIds = append(businessIds, business.BusinessUid)
+		}
+	}
+	items := make([]*exchange_wallet_go.FeeReportItem, 0)
+	for _, businessId := range businessIds {
+		summaries, err := reader.Fees.QueryFeeSummary(businessId, startTime, endTime)
+		if err != nil {
+			log.Error("failed to query fee summary", "requestId", businessId, "err", err)
+			return err
+		}
+		items = append(items, services.FeeReportItems(businessId, summaries)...)
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	if ctx.String("output") == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return os.WriteFile(ctx.String("output"), data, 0o644)
+}
+
+/*挂起交易人工处理命令：放行或拒绝筛查、风险评分挂起的充值与提现*/
+func runHandleHold(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	txType, err := constant.ParseTransactionType(ctx.String("tx-type"))
+	if err != nil {
+		return err
+	}
+	var release bool
+	switch ctx.String("action") {
+	case "release":
+		release = true
+	case "reject":
+	default:
+		return fmt.Errorf("invalid hold action: %s", ctx.String("action"))
+	}
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
+	if err != nil {
+		log.Error("failed to connect database", "err", err)
+		return err
+	}
+	defer func(db *database.DB) {
+		err := db.Close()
+		if err != nil {
+			log.Error("failed to close database connection", "err", err)
+		}
+	}(db)
+	if err := services.HandleHold(db, ctx.String("request-id"), txType, ctx.String("transaction-id"), release); err != nil {
+		log.Error("failed to handle hold transaction", "requestId", ctx.String("request-id"), "transactionId", ctx.String("transaction-id"), "err", err)
+		return err
+	}
+	return nil
+}
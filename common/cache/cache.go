@@ -1,30 +1,111 @@
 package cache
 
-//// 定义一个全局的Cache实例
-//var globalCache *ristretto.Cache[string, *database.Addresses]
-//var once sync.Once
-//
-//// InitGlobalCache 初始化全局缓存实例，只会执行一次
-//func InitGlobalCache() {
-//	once.Do(func() {
-//		// 创建一个新的 Ristretto 缓存实例
-//		cache, err := ristretto.NewCache[string, *database.Addresses](&ristretto.Config[string, *database.Addresses]{
-//			NumCounters: 1e7,     // number of keys to track frequency of (10M).
-//			MaxCost:     1 << 30, // maximum cost of cache (1GB).
-//			BufferItems: 64,      // number of keys per Get buffer.
-//		})
-//		if err != nil {
-//			log.Error("create ristretto cache failed", "err", err)
-//			return // 如果发生错误，直接返回，globalCache 仍然是 nil
-//		}
-//		globalCache = cache
-//	})
-//}
-//
-//// GetGlobalCache 获取全局缓存实例
-//func GetGlobalCache() *ristretto.Cache[string, *database.Addresses] {
-//	if globalCache == nil {
-//		InitGlobalCache()
-//	}
-//	return globalCache
-//}
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"exchange-wallet-service/common/clock"
+)
+
+/*
+并发安全的 LRU + TTL 缓存：超过容量淘汰最久未使用的条目，
+过期条目在读取时删除；size 小于等于 0 表示不限容量
+*/
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	clk   clock.Clock
+	items map[K]*list.Element
+	/*最近使用的在前*/
+	order *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	expireAt time.Time
+}
+
+func New[K comparable, V any](size int, ttl time.Duration, clk clock.Clock) *Cache[K, V] {
+	return &Cache[K, V]{
+		size:  size,
+		ttl:   ttl,
+		clk:   clk,
+		items: make(map[K]*list.Element),
+		order: list.New(),
+	}
+}
+
+/*读取缓存，命中时移到最前；已过期则删除并视为未命中*/
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var zero V
+	element, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	item := element.Value.(*entry[K, V])
+	if c.expired(item) {
+		c.removeElement(element)
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return item.value, true
+}
+
+/*写入缓存并刷新过期时间，超过容量时淘汰最久未使用的条目*/
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expireAt := time.Time{}
+	if c.ttl > 0 {
+		expireAt = c.clk.Now().Add(c.ttl)
+	}
+	if element, ok := c.items[key]; ok {
+		item := element.Value.(*entry[K, V])
+		item.value = value
+		item.expireAt = expireAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expireAt: expireAt})
+	for c.size > 0 && c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+/*删除指定条目*/
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.items[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+/*清空缓存*/
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+/*当前条目数（含尚未清理的过期条目）*/
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache[K, V]) expired(item *entry[K, V]) bool {
+	return !item.expireAt.IsZero() && !c.clk.Now().Before(item.expireAt)
+}
+
+func (c *Cache[K, V]) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*entry[K, V]).key)
+}
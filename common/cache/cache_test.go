@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"exchange-wallet-service/common/clock"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheEvictLeastRecentlyUsed(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1000, 0))
+	c := New[string, int](2, time.Minute, clk)
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	/*访问 a 后 b 成为最久未使用*/
+	value, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	c.Add("c", 3)
+	require.Equal(t, 2, c.Len())
+	_, ok = c.Get("b")
+	require.False(t, ok)
+	_, ok = c.Get("a")
+	require.True(t, ok)
+	_, ok = c.Get("c")
+	require.True(t, ok)
+}
+
+func TestCacheExpire(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1000, 0))
+	c := New[string, int](10, time.Minute, clk)
+	c.Add("a", 1)
+
+	clk.AdvanceTime(59 * time.Second)
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	clk.AdvanceTime(time.Second)
+	_, ok = c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+
+	/*重新写入刷新过期时间*/
+	c.Add("a", 2)
+	clk.AdvanceTime(30 * time.Second)
+	c.Add("a", 3)
+	clk.AdvanceTime(45 * time.Second)
+	value, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 3, value)
+}
+
+func TestCacheRemoveAndPurge(t *testing.T) {
+	c := New[string, int](0, 0, clock.NewDeterministicClock(time.Unix(1000, 0)))
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+
+	c.Remove("b")
+	_, ok := c.Get("b")
+	require.False(t, ok)
+	require.Equal(t, 2, c.Len())
+
+	c.Purge()
+	require.Equal(t, 0, c.Len())
+	_, ok = c.Get("a")
+	require.False(t, ok)
+}
@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+/*
+Merkle 树（keccak256）：
+叶子与中间节点分别加前缀 0x00、0x01 再哈希，防止中间节点被当作叶子伪造证明；
+某层节点数为奇数时，最后一个节点直接提升到上一层（不复制），同一组叶子只对应唯一的根
+*/
+const (
+	leafPrefix = byte(0x00)
+	nodePrefix = byte(0x01)
+)
+
+var ErrEmptyTree = errors.New("merkle tree has no leaves")
+
+/*证明中的兄弟节点，Left 表示兄弟节点在左侧*/
+type ProofNode struct {
+	Hash common.Hash `json:"hash"`
+	Left bool        `json:"left"`
+}
+
+type Tree struct {
+	/*levels[0] 为叶子哈希，最后一层为根*/
+	levels [][]common.Hash
+}
+
+/*叶子哈希：对叶子数据各部分拼接后加前缀哈希*/
+func LeafHash(data ...[]byte) common.Hash {
+	return crypto.Keccak256Hash(append([][]byte{{leafPrefix}}, data...)...)
+}
+
+/*中间节点哈希*/
+func NodeHash(left, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte{nodePrefix}, left.Bytes(), right.Bytes())
+}
+
+/*由叶子哈希构建 Merkle 树，叶子顺序由调用方确定*/
+func NewTree(leaves []common.Hash) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+	level := make([]common.Hash, len(leaves))
+	copy(level, leaves)
+	levels := [][]common.Hash{level}
+	for len(level) > 1 {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, NodeHash(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &Tree{levels: levels}, nil
+}
+
+func (t *Tree) Root() common.Hash {
+	return t.levels[len(t.levels)-1][0]
+}
+
+func (t *Tree) LeafCount() int {
+	return len(t.levels[0])
+}
+
+/*第 index 个叶子的包含证明，自底向上*/
+func (t *Tree) Proof(index int) ([]ProofNode, error) {
+	if index < 0 || index >= t.LeafCount() {
+		return nil, fmt.Errorf("leaf index %d out of range", index)
+	}
+	var proof []ProofNode
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, ProofNode{Hash: level[sibling], Left: sibling < index})
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+/*校验叶子哈希与证明能否得到根*/
+func Verify(root, leaf common.Hash, proof []ProofNode) bool {
+	hash := leaf
+	for _, node := range proof {
+		if node.Left {
+			hash = NodeHash(node.Hash, hash)
+		} else {
+			hash = NodeHash(hash, node.Hash)
+		}
+	}
+	return hash == root
+}
@@ -0,0 +1,76 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func leaves(n int) []common.Hash {
+	out := make([]common.Hash, n)
+	for i := range out {
+		out[i] = LeafHash([]byte{byte(i)})
+	}
+	return out
+}
+
+func TestNewTreeEmpty(t *testing.T) {
+	_, err := NewTree(nil)
+	require.ErrorIs(t, err, ErrEmptyTree)
+}
+
+func TestSingleLeaf(t *testing.T) {
+	tree, err := NewTree(leaves(1))
+	require.NoError(t, err)
+	require.Equal(t, leaves(1)[0], tree.Root())
+
+	proof, err := tree.Proof(0)
+	require.NoError(t, err)
+	require.Empty(t, proof)
+	require.True(t, Verify(tree.Root(), leaves(1)[0], proof))
+}
+
+func TestRoot(t *testing.T) {
+	l := leaves(3)
+	tree, err := NewTree(l)
+	require.NoError(t, err)
+	/*奇数节点直接提升*/
+	require.Equal(t, NodeHash(NodeHash(l[0], l[1]), l[2]), tree.Root())
+}
+
+func TestProofs(t *testing.T) {
+	for _, n := range []int{2, 3, 5, 8, 13} {
+		l := leaves(n)
+		tree, err := NewTree(l)
+		require.NoError(t, err)
+		for i := range l {
+			proof, err := tree.Proof(i)
+			require.NoError(t, err)
+			require.True(t, Verify(tree.Root(), l[i], proof), "n=%d i=%d", n, i)
+			/*换一个叶子校验应失败*/
+			require.False(t, Verify(tree.Root(), LeafHash([]byte("other")), proof))
+		}
+	}
+}
+
+func TestProofOutOfRange(t *testing.T) {
+	tree, err := NewTree(leaves(4))
+	require.NoError(t, err)
+	_, err = tree.Proof(4)
+	require.Error(t, err)
+	_, err = tree.Proof(-1)
+	require.Error(t, err)
+}
+
+func TestNodeCannotBeLeaf(t *testing.T) {
+	l := leaves(4)
+	tree, err := NewTree(l)
+	require.NoError(t, err)
+	/*以两个子节点拼接作为叶子数据，哈希不等于中间节点，无法用短证明伪造*/
+	proof, err := tree.Proof(0)
+	require.NoError(t, err)
+	forged := LeafHash(l[0].Bytes(), l[1].Bytes())
+	require.NotEqual(t, NodeHash(l[0], l[1]), forged)
+	require.False(t, Verify(tree.Root(), forged, proof[1:]))
+}
@@ -21,11 +21,23 @@ type Config struct {
 	MasterDB       DBConfig
 	SlaveDB        DBConfig
 	SlaveDbEnable  bool
+	SlaveDbMaxLag  time.Duration
 	ApiCacheEnable bool
 	CacheConfig    CacheConfig
 	RpcServer      ServerConfig
 	MetricsServer  ServerConfig
 	ChainsUnionRpc string
+	Screening      ScreeningConfig
+	Risk           RiskConfig
+	Reconcile      ReconcileConfig
+	Snapshot       SnapshotConfig
+	Disperse       DisperseConfig
+	Fee            FeeConfig
+	Health         HealthConfig
+	Tracing        TracingConfig
+	RpcServerTLS   ServerTLSConfig
+	ChainsUnionTLS ClientTLSConfig
+	RateLimit      RateLimitConfig
 }
 
 type ChainNodeConfig struct {
@@ -37,6 +49,94 @@ type ChainNodeConfig struct {
 	SynchronizerInterval time.Duration
 	WorkerInterval       time.Duration
 	BlocksStep           uint64
+	TransferLogEnable    bool
+	TraceEnable          bool
+}
+
+/*地址筛查配置*/
+type ScreeningConfig struct {
+	DenyListFile          string
+	LookalikePrefixLength int
+	LookalikeSuffixLength int
+	CounterpartyLimit     int
+}
+
+/*风险评分配置*/
+type RiskConfig struct {
+	/*外部评分服务地址，为空则使用本地规则评分*/
+	ScorerUrl     string
+	ScorerTimeout time.Duration
+	HoldScore     int
+	RejectScore   int
+	/*大额规则阈值，格式 token地址:金额（最小单位）*/
+	LargeAmounts []string
+}
+
+/*对账配置*/
+type ReconcileConfig struct {
+	/*对账间隔，为 0 则 work 中不定时对账*/
+	Interval    time.Duration
+	AlertEnable bool
+}
+
+/*余额快照配置*/
+type SnapshotConfig struct {
+	/*每隔多少个区块快照一次，为 0 则不按区块快照*/
+	BlockInterval uint64
+	/*是否每日（UTC）快照一次*/
+	Daily bool
+}
+
+type DisperseConfig struct {
+	/*批量提现 disperse 合约地址，为空则不支持批量提现*/
+	ContractAddress string
+}
+
+type FeeConfig struct {
+	/*legacy（非 EIP-1559）gas 定价*/
+	Legacy bool
+	/*直连链节点估算 gasLimit，关闭则使用默认 gasLimit*/
+	GasEstimateEnable bool
+	/*估算 gasLimit 的安全余量（百分比）*/
+	GasLimitMargin uint64
+}
+
+type HealthConfig struct {
+	/*同步器超过该时长没有进展视为卡死，存活检查失败*/
+	SyncStaleTimeout time.Duration
+}
+
+type TracingConfig struct {
+	/*OTLP gRPC collector 地址（如 127.0.0.1:4317），为空不导出*/
+	Endpoint string
+	/*根 span 采样比例，0~1*/
+	SampleRatio float64
+}
+
+type RateLimitConfig struct {
+	/*限流规则与项目方配额存于 rate_limits、business_quotas 表，按该间隔重新加载*/
+	RefreshInterval time.Duration
+}
+
+/*服务端 TLS，证书与 CA 文件变更后自动重新加载*/
+type ServerTLSConfig struct {
+	/*服务端证书与私钥，为空则明文*/
+	CertFile string
+	KeyFile  string
+	/*客户端证书 CA，配置后开启双向 TLS，证书 CommonName 为项目方 request_id*/
+	ClientCAFile string
+}
+
+/*客户端 TLS，证书与 CA 文件变更后自动重新加载*/
+type ClientTLSConfig struct {
+	Enable bool
+	/*校验服务端证书的 CA，为空使用系统根证书*/
+	CAFile string
+	/*客户端证书与私钥，服务端要求双向 TLS 时配置*/
+	CertFile string
+	KeyFile  string
+	/*校验服务端证书的主机名，为空取连接地址*/
+	ServerName string
 }
 
 type DBConfig struct {
@@ -96,6 +196,8 @@ func NewConfig(ctx *cli.Context) Config {
 			SynchronizerInterval: ctx.Duration(flags.SynchronizerIntervalFlag.Name),
 			WorkerInterval:       ctx.Duration(flags.WorkerIntervalFlag.Name),
 			BlocksStep:           ctx.Uint64(flags.BlocksStepFlag.Name),
+			TransferLogEnable:    ctx.Bool(flags.TransferLogEnableFlag.Name),
+			TraceEnable:          ctx.Bool(flags.TraceEnableFlag.Name),
 		},
 		MasterDB: DBConfig{
 			Host:     ctx.String(flags.MasterDbHostFlag.Name),
@@ -112,6 +214,7 @@ func NewConfig(ctx *cli.Context) Config {
 			Password: ctx.String(flags.SlaveDbPasswordFlag.Name),
 		},
 		SlaveDbEnable:  ctx.Bool(flags.SlaveDbEnableFlag.Name),
+		SlaveDbMaxLag:  ctx.Duration(flags.SlaveDbMaxLagFlag.Name),
 		ApiCacheEnable: ctx.Bool(flags.ApiCacheEnableFlag.Name),
 		CacheConfig: CacheConfig{
 			ListSize:         ctx.Int(flags.ApiCacheListSizeFlag.Name),
@@ -127,5 +230,56 @@ func NewConfig(ctx *cli.Context) Config {
 			Host: ctx.String(flags.MetricsHostFlag.Name),
 			Port: ctx.Int(flags.MetricsPortFlag.Name),
 		},
+		Screening: ScreeningConfig{
+			DenyListFile:          ctx.String(flags.DenyListFileFlag.Name),
+			LookalikePrefixLength: ctx.Int(flags.LookalikePrefixLengthFlag.Name),
+			LookalikeSuffixLength: ctx.Int(flags.LookalikeSuffixLengthFlag.Name),
+			CounterpartyLimit:     ctx.Int(flags.CounterpartyLimitFlag.Name),
+		},
+		Risk: RiskConfig{
+			ScorerUrl:     ctx.String(flags.RiskScorerUrlFlag.Name),
+			ScorerTimeout: ctx.Duration(flags.RiskScorerTimeoutFlag.Name),
+			HoldScore:     ctx.Int(flags.RiskHoldScoreFlag.Name),
+			RejectScore:   ctx.Int(flags.RiskRejectScoreFlag.Name),
+			LargeAmounts:  ctx.StringSlice(flags.RiskLargeAmountsFlag.Name),
+		},
+		Reconcile: ReconcileConfig{
+			Interval:    ctx.Duration(flags.ReconcileIntervalFlag.Name),
+			AlertEnable: ctx.Bool(flags.ReconcileAlertEnableFlag.Name),
+		},
+		Snapshot: SnapshotConfig{
+			BlockInterval: ctx.Uint64(flags.SnapshotBlockIntervalFlag.Name),
+			Daily:         ctx.Bool(flags.SnapshotDailyFlag.Name),
+		},
+		Disperse: DisperseConfig{
+			ContractAddress: ctx.String(flags.DisperseContractFlag.Name),
+		},
+		Fee: FeeConfig{
+			Legacy:            ctx.Bool(flags.FeeLegacyFlag.Name),
+			GasEstimateEnable: ctx.Bool(flags.GasEstimateEnableFlag.Name),
+			GasLimitMargin:    ctx.Uint64(flags.GasLimitMarginFlag.Name),
+		},
+		Health: HealthConfig{
+			SyncStaleTimeout: ctx.Duration(flags.SyncStaleTimeoutFlag.Name),
+		},
+		Tracing: TracingConfig{
+			Endpoint:    ctx.String(flags.TracingEndpointFlag.Name),
+			SampleRatio: ctx.Float64(flags.TracingSampleRatioFlag.Name),
+		},
+		RpcServerTLS: ServerTLSConfig{
+			CertFile:     ctx.String(flags.RpcTLSCertFileFlag.Name),
+			KeyFile:      ctx.String(flags.RpcTLSKeyFileFlag.Name),
+			ClientCAFile: ctx.String(flags.RpcTLSClientCAFileFlag.Name),
+		},
+		ChainsUnionTLS: ClientTLSConfig{
+			Enable:     ctx.Bool(flags.ChainsUnionTLSEnableFlag.Name),
+			CAFile:     ctx.String(flags.ChainsUnionTLSCAFileFlag.Name),
+			CertFile:   ctx.String(flags.ChainsUnionTLSCertFileFlag.Name),
+			KeyFile:    ctx.String(flags.ChainsUnionTLSKeyFileFlag.Name),
+			ServerName: ctx.String(flags.ChainsUnionTLSServerNameFlag.Name),
+		},
+		RateLimit: RateLimitConfig{
+			RefreshInterval: ctx.Duration(flags.RateLimitRefreshIntervalFlag.Name),
+		},
 	}
 }
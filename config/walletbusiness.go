@@ -1,6 +1,27 @@
 package config
 
+import "time"
+
 type WalletBusinessConfig struct {
 	GrpcHostName string
 	GrpcPort     int
+	/*批量提现 disperse 合约地址*/
+	DisperseContract string
+	/*legacy（非 EIP-1559）gas 定价*/
+	FeeLegacy bool
+	/*估算 gasLimit 的安全余量（百分比）*/
+	GasLimitMargin uint64
+	/*指标服务，端口为 0 不启动*/
+	MetricsHostName string
+	MetricsPort     int
+	/*链路追踪 OTLP gRPC collector 地址，为空不导出*/
+	TracingEndpoint    string
+	TracingSampleRatio float64
+	/*gRPC 与 HTTP/JSON 网关的 TLS*/
+	TLS ServerTLSConfig
+	/*限流与配额配置的刷新间隔*/
+	RateLimitRefreshInterval time.Duration
+	/*查询接口响应缓存*/
+	ApiCacheEnable bool
+	ApiCache       CacheConfig
 }
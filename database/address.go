@@ -3,6 +3,7 @@ package database
 import (
 	"errors"
 	"exchange-wallet-service/database/constant"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -19,6 +20,7 @@ type Address struct {
 
 type AddressesView interface {
 	AddressExist(requestId string, address *common.Address) (bool, constant.AddressType)
+	QueryAddressList(requestId string) ([]*Address, error)
 
 	//	todo
 }
@@ -43,6 +45,15 @@ func (db *addressDB) StoreAddresses(requestId string, addressList []*Address) er
 		CreateInBatches(&addressList, len(addressList)).Error
 }
 
+/*项目方全部钱包地址（用户、热、冷）*/
+func (db *addressDB) QueryAddressList(requestId string) ([]*Address, error) {
+	var addressList []*Address
+	if err := db.gorm.Table("addresses_" + requestId).Find(&addressList).Error; err != nil {
+		return nil, fmt.Errorf("query address list failed: %w", err)
+	}
+	return addressList, nil
+}
+
 /*是否存在地址*/
 func (db *addressDB) AddressExist(requestId string, address *common.Address) (bool, constant.AddressType) {
 	var addressEntry Address
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"errors"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+	"strings"
+)
+
+/*余额快照表：按区块间隔或每日记录各地址各代币余额*/
+type BalanceSnapshots struct {
+	GUID uuid.UUID `gorm:"primary_key" json:"guid"`
+	/*快照时同步器已处理的最新区块及其时间*/
+	BlockNumber  *big.Int             `gorm:"type:numeric;not null;serializer:u256" json:"block_number"`
+	SnapshotTime uint64               `gorm:"type:bigint;not null" json:"snapshot_time"`
+	Address      common.Address       `gorm:"type:varchar;not null;serializer:bytes" json:"address"`
+	AddressType  constant.AddressType `gorm:"type:varchar;not null" json:"address_type"`
+	TokenAddress common.Address       `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	Balance      *big.Int             `gorm:"type:numeric;not null;default:0;serializer:u256" json:"balance"`
+	LockBalance  *big.Int             `gorm:"type:numeric;not null;default:0;serializer:u256" json:"lock_balance"`
+	Timestamp    uint64               `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+/*时间点余额查询条件：区块号与时间二选一，address、token 为空则不限*/
+type BalanceAtQuery struct {
+	Address      *common.Address
+	TokenAddress *common.Address
+	BlockNumber  *big.Int
+	Timestamp    uint64
+}
+
+type BalanceSnapshotsView interface {
+	QueryLatestSnapshot(requestId string) (*BalanceSnapshots, error)
+	QueryBalanceAt(requestId string, query *BalanceAtQuery) ([]*BalanceSnapshots, error)
+}
+
+type BalanceSnapshotsDB interface {
+	BalanceSnapshotsView
+
+	StoreSnapshots(requestId string, snapshots []*BalanceSnapshots) error
+}
+
+type balanceSnapshotsDB struct {
+	gorm *gorm.DB
+}
+
+func NewBalanceSnapshotsDB(db *gorm.DB) BalanceSnapshotsDB {
+	return &balanceSnapshotsDB{gorm: db}
+}
+
+/*批量存储快照*/
+func (db *balanceSnapshotsDB) StoreSnapshots(requestId string, snapshots []*BalanceSnapshots) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return db.gorm.Table("balance_snapshots_"+requestId).CreateInBatches(snapshots, len(snapshots)).Error
+}
+
+/*最近一次快照，没有快照返回 nil*/
+func (db *balanceSnapshotsDB) QueryLatestSnapshot(requestId string) (*BalanceSnapshots, error) {
+	var snapshot BalanceSnapshots
+	result := db.gorm.Table("balance_snapshots_" + requestId).
+		Order("block_number DESC").
+		Take(&snapshot)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &snapshot, nil
+}
+
+/*查询时间点余额：每个地址、代币取不晚于指定区块（或时间）的最近一次快照*/
+func (db *balanceSnapshotsDB) QueryBalanceAt(requestId string, query *BalanceAtQuery) ([]*BalanceSnapshots, error) {
+	tx := db.gorm.Table("balance_snapshots_" + requestId)
+	switch {
+	case query.BlockNumber != nil:
+		tx = tx.Where("block_number <= ?", query.BlockNumber.String())
+	case query.Timestamp > 0:
+		tx = tx.Where("snapshot_time <= ?", query.Timestamp)
+	default:
+		return nil, errors.New("block number or timestamp is required")
+	}
+	if query.Address != nil {
+		tx = tx.Where("address = ?", strings.ToLower(query.Address.String()))
+	}
+	if query.TokenAddress != nil {
+		tx = tx.Where("token_address = ?", strings.ToLower(query.TokenAddress.String()))
+	}
+	var snapshots []*BalanceSnapshots
+	err := tx.Select("DISTINCT ON (address, token_address) *").
+		Order("address, token_address, block_number DESC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("query balance at failed: %w", err)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var balanceSnapshotColumns = []string{"guid", "block_number", "snapshot_time", "address", "address_type", "token_address", "balance", "lock_balance", "timestamp"}
+
+/*按区块查询：每个地址、代币取不晚于该区块的最近一次快照*/
+func TestQueryBalanceAtBlock(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(address, token_address\) \* FROM "balance_snapshots_biz" WHERE block_number <= \$1 ORDER BY address, token_address, block_number DESC`).
+		WithArgs("1000").
+		WillReturnRows(sqlmock.NewRows(balanceSnapshotColumns).
+			AddRow(uuid.New().String(), "990", 100, "0x00000000000000000000000000000000000000c1", "user", "0x0000000000000000000000000000000000000000", "500", "20", 100))
+
+	db := NewBalanceSnapshotsDB(gormDB)
+	snapshots, err := db.QueryBalanceAt("biz", &BalanceAtQuery{BlockNumber: big.NewInt(1000)})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "990", snapshots[0].BlockNumber.String())
+	assert.Equal(t, common.HexToAddress("0x00000000000000000000000000000000000000C1"), snapshots[0].Address)
+	assert.Equal(t, "500", snapshots[0].Balance.String())
+	assert.Equal(t, "20", snapshots[0].LockBalance.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*按时间查询，并限定地址与代币*/
+func TestQueryBalanceAtTimestampWithFilter(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	address := common.HexToAddress("0x00000000000000000000000000000000000000C1")
+	tokenAddress := common.HexToAddress("0x00000000000000000000000000000000000000A1")
+	mock.ExpectQuery(`SELECT DISTINCT ON \(address, token_address\) \* FROM "balance_snapshots_biz" WHERE snapshot_time <= \$1 AND address = \$2 AND token_address = \$3 ORDER BY address, token_address, block_number DESC`).
+		WithArgs(1700000000, "0x00000000000000000000000000000000000000c1", "0x00000000000000000000000000000000000000a1").
+		WillReturnRows(sqlmock.NewRows(balanceSnapshotColumns))
+
+	db := NewBalanceSnapshotsDB(gormDB)
+	snapshots, err := db.QueryBalanceAt("biz", &BalanceAtQuery{Address: &address, TokenAddress: &tokenAddress, Timestamp: 1700000000})
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*区块号与时间都未指定直接报错，不查库*/
+func TestQueryBalanceAtRequiresPoint(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	db := NewBalanceSnapshotsDB(gormDB)
+	_, err := db.QueryBalanceAt("biz", &BalanceAtQuery{})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryLatestSnapshot(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "balance_snapshots_biz" ORDER BY block_number DESC LIMIT \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(balanceSnapshotColumns).
+			AddRow(uuid.New().String(), "990", 100, "0x00000000000000000000000000000000000000c1", "user", "0x0000000000000000000000000000000000000000", "500", "0", 100))
+	mock.ExpectQuery(`SELECT \* FROM "balance_snapshots_biz" ORDER BY block_number DESC LIMIT \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(balanceSnapshotColumns))
+
+	db := NewBalanceSnapshotsDB(gormDB)
+	snapshot, err := db.QueryLatestSnapshot("biz")
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "990", snapshot.BlockNumber.String())
+
+	/*没有快照返回 nil*/
+	snapshot, err = db.QueryLatestSnapshot("biz")
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
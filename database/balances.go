@@ -28,6 +28,10 @@ type Balances struct {
 	*/
 	LockBalance *big.Int `gorm:"type:numeric;not null;default:0;serializer:u256" json:"lock_balance"`
 	Timestamp   uint64   `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+
+	/*锁定余额时记账引用的来源交易，不入库*/
+	TxType constant.TransactionType `gorm:"-" json:"-"`
+	TxHash common.Hash              `gorm:"-" json:"-"`
 }
 
 type BalancesView interface {
@@ -37,6 +41,7 @@ type BalancesView interface {
 		address,
 		tokenAddress common.Address,
 	) (*Balances, error)
+	QueryBalanceList(requestId string) ([]*Balances, error)
 }
 
 type BalancesDB interface {
@@ -187,6 +192,10 @@ func (db *balancesDB) UpdateOrCreate(requestId string, balanceList []*TokenBalan
 			if err := db.handleBalanceUpdate(tx, requestId, balance); err != nil {
 				return fmt.Errorf("failed to handle balance update: %w", err)
 			}
+			/*记账*/
+			if err := storeTransferJournal(tx, requestId, balance, false); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -205,6 +214,8 @@ func (db *balancesDB) handleBalanceUpdate(tx *gorm.DB, requestId string, balance
 		return db.handleHotToCold(tx, requestId, balance)
 	case constant.TxTypeCold2Hot:
 		return db.handleColdToHot(tx, requestId, balance)
+	case constant.TxTypeGasFunding:
+		return db.handleGasFunding(tx, requestId, balance)
 	default:
 		return fmt.Errorf("unsupported transaction type: %s", balance.TxType)
 	}
@@ -237,6 +248,9 @@ func (db *balancesDB) handleColdToHot(tx *gorm.DB, requestId string, balance *To
 		log.Error("Query cold wallet failed", "err", err)
 		return err
 	}
+	if err := releaseLock(tx, requestId, coldWallet, balance); err != nil {
+		return err
+	}
 	coldWallet.Balance = new(big.Int).Sub(coldWallet.Balance, balance.Balance)
 	if err := db.UpdateAndSaveBalance(tx, requestId, coldWallet); err != nil {
 		return err
@@ -258,6 +272,9 @@ func (db *balancesDB) handleHotToCold(tx *gorm.DB, requestId string, balance *To
 		log.Error("Query hot wallet failed", "err", err)
 		return err
 	}
+	if err := releaseLock(tx, requestId, hotWallet, balance); err != nil {
+		return err
+	}
 	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
 	if err := db.UpdateAndSaveBalance(tx, requestId, hotWallet); err != nil {
 		return err
@@ -272,6 +289,30 @@ func (db *balancesDB) handleHotToCold(tx *gorm.DB, requestId string, balance *To
 	return db.UpdateAndSaveBalance(tx, requestId, coldWallet)
 }
 
+/*gas 补充余额更新，热钱包主币-，用户主币+*/
+func (db *balancesDB) handleGasFunding(tx *gorm.DB, requestId string, balance *TokenBalance) error {
+	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, constant.AddressTypeHot, balance.FromAddress, balance.TokenAddress)
+	if err != nil {
+		log.Error("Query hot wallet failed", "err", err)
+		return err
+	}
+	if err := releaseLock(tx, requestId, hotWallet, balance); err != nil {
+		return err
+	}
+	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
+	if err := db.UpdateAndSaveBalance(tx, requestId, hotWallet); err != nil {
+		return err
+	}
+
+	userWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, constant.AddressTypeUser, balance.ToAddress, balance.TokenAddress)
+	if err != nil {
+		log.Error("Query user wallet failed", "err", err)
+		return err
+	}
+	userWallet.Balance = new(big.Int).Add(userWallet.Balance, balance.Balance)
+	return db.UpdateAndSaveBalance(tx, requestId, userWallet)
+}
+
 /*归集余额更新*/
 func (db *balancesDB) handleCollection(tx *gorm.DB, requestId string, balance *TokenBalance) error {
 	userWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, constant.AddressTypeUser, balance.FromAddress, balance.TokenAddress)
@@ -279,6 +320,9 @@ func (db *balancesDB) handleCollection(tx *gorm.DB, requestId string, balance *T
 		log.Error("Query user wallet failed", "err", err)
 		return err
 	}
+	if err := releaseLock(tx, requestId, userWallet, balance); err != nil {
+		return err
+	}
 	userWallet.Balance = new(big.Int).Sub(userWallet.Balance, balance.Balance)
 	if err := db.UpdateAndSaveBalance(tx, requestId, userWallet); err != nil {
 		return err
@@ -327,7 +371,10 @@ func (db *balancesDB) UpdateAndSaveBalance(tx *gorm.DB, requestId string, balanc
 		3. 确认位到了后，balance = balance + 100；lockBalance = lockBalance - 100；
 		4. 对于可用余额：直接就是 balance。对于总余额：balance + lockBalance；
 	*/
-	currentBalance.LockBalance = new(big.Int).Add(currentBalance.LockBalance, balance.LockBalance)
+	/*上游传入的是整行余额，锁定余额同样直接取上游的值*/
+	if balance.LockBalance != nil {
+		currentBalance.LockBalance = balance.LockBalance
+	}
 	currentBalance.Timestamp = uint64(time.Now().Unix())
 
 	/*修改*/
@@ -357,10 +404,35 @@ func (db *balancesDB) handleWithdraw(tx *gorm.DB, requestId string, balance *Tok
 		return err
 	}
 
+	if err := releaseLock(tx, requestId, hotWallet, balance); err != nil {
+		return err
+	}
 	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
 	return db.UpdateAndSaveBalance(tx, requestId, hotWallet)
 }
 
+/*
+发出交易确认后解锁：发送时已从 balance 转入 lockBalance，
+确认时先把锁定的金额（不超过交易金额）转回 balance 并记解锁分录，再按交易从 balance 扣减。
+非本钱包发起（未锁定）的交易不解锁，直接从 balance 扣减
+*/
+func releaseLock(tx *gorm.DB, requestId string, wallet *Balances, balance *TokenBalance) error {
+	if wallet.LockBalance == nil || wallet.LockBalance.Sign() <= 0 || balance.Balance == nil {
+		return nil
+	}
+	released := new(big.Int).Set(balance.Balance)
+	if released.Cmp(wallet.LockBalance) > 0 {
+		released.Set(wallet.LockBalance)
+	}
+	wallet.LockBalance = new(big.Int).Sub(wallet.LockBalance, released)
+	wallet.Balance = new(big.Int).Add(wallet.Balance, released)
+	/*解锁记账：借 可用，贷 锁定*/
+	return storeJournal(tx, requestId, constant.LedgerEventUnlock, balance.TxType, balance.TxHash,
+		LedgerAccount{Address: wallet.Address, AddressType: wallet.AddressType, Bucket: constant.LedgerBucketAvailable},
+		LedgerAccount{Address: wallet.Address, AddressType: wallet.AddressType, Bucket: constant.LedgerBucketLocked},
+		wallet.TokenAddress, released)
+}
+
 /*更新已有的地址余额*/
 func (db *balancesDB) UpdateBalanceListByTwoAddress(requestId string, balanceList []*Balances) error {
 	if len(balanceList) == 0 {
@@ -370,20 +442,12 @@ func (db *balancesDB) UpdateBalanceListByTwoAddress(requestId string, balanceLis
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, balance := range balanceList {
 			var currentBalance Balances
-			/*todo bug！！！take 地址是大写的，查不出来，无法插入库*/
 			result := tx.Table("balances_"+requestId).
 				Where("address = ? AND token_address = ?",
-					balance.Address.String(),
-					balance.TokenAddress.String()).
+					strings.ToLower(balance.Address.String()),
+					strings.ToLower(balance.TokenAddress.String())).
 				Take(&currentBalance)
 
-			/*todo 这里才是正确的*/
-			//result := tx.Table("balances_"+requestId).
-			//	Where("address = ? AND token_address = ?",
-			//		strings.ToLower(balance.Address.String()),
-			//		strings.ToLower(balance.TokenAddress.String())).
-			//	Take(&currentBalance)
-
 			if result.Error != nil {
 				if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 					continue
@@ -398,13 +462,21 @@ func (db *balancesDB) UpdateBalanceListByTwoAddress(requestId string, balanceLis
 				其他交易可类比
 			*/
 			currentBalance.Balance = new(big.Int).Sub(currentBalance.Balance, balance.LockBalance)
-			/*todo 此处应加上而不是直接赋值（可能有多笔交易）*/
-			currentBalance.LockBalance = balance.LockBalance
+			/*可能有多笔在途交易，累加*/
+			currentBalance.LockBalance = new(big.Int).Add(currentBalance.LockBalance, balance.LockBalance)
 			currentBalance.Timestamp = uint64(time.Now().Unix())
 
 			if err := tx.Table("balances_" + requestId).Save(&currentBalance).Error; err != nil {
 				return fmt.Errorf("save balance failed: %w", err)
 			}
+
+			/*锁定记账：借 锁定，贷 可用*/
+			if err := storeJournal(tx, requestId, constant.LedgerEventLock, balance.TxType, balance.TxHash,
+				LedgerAccount{Address: currentBalance.Address, AddressType: currentBalance.AddressType, Bucket: constant.LedgerBucketLocked},
+				LedgerAccount{Address: currentBalance.Address, AddressType: currentBalance.AddressType, Bucket: constant.LedgerBucketAvailable},
+				currentBalance.TokenAddress, balance.LockBalance); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -416,19 +488,29 @@ func (db *balancesDB) UpdateFallBackBalance(requestId string, balanceList []*Tok
 	}
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, balance := range balanceList {
+			var err error
 			switch balance.TxType {
 			case constant.TxTypeDeposit:
-				return db.handleFallBackDeposit(tx, requestId, balance)
+				err = db.handleFallBackDeposit(tx, requestId, balance)
 			case constant.TxTypeWithdraw:
-				return db.handleFallBackWithdraw(tx, requestId, balance)
+				err = db.handleFallBackWithdraw(tx, requestId, balance)
 			case constant.TxTypeCollection:
-				return db.handleFallBackCollection(tx, requestId, balance)
+				err = db.handleFallBackCollection(tx, requestId, balance)
 			case constant.TxTypeHot2Cold:
-				return db.handleFallBackHotToCold(tx, requestId, balance)
+				err = db.handleFallBackHotToCold(tx, requestId, balance)
 			case constant.TxTypeCold2Hot:
-				return db.handleFallBackColdToHot(tx, requestId, balance)
+				err = db.handleFallBackColdToHot(tx, requestId, balance)
+			case constant.TxTypeGasFunding:
+				err = db.handleFallBackGasFunding(tx, requestId, balance)
 			default:
-				return fmt.Errorf("unsupported transaction type: %s", balance.TxType)
+				err = fmt.Errorf("unsupported transaction type: %s", balance.TxType)
+			}
+			if err != nil {
+				return err
+			}
+			/*冲正记账*/
+			if err := storeTransferJournal(tx, requestId, balance, true); err != nil {
+				return err
 			}
 		}
 		return nil
@@ -498,6 +580,27 @@ func (db *balancesDB) handleFallBackHotToCold(tx *gorm.DB, requestId string, bal
 	return db.UpdateAndSaveBalance(tx, requestId, coldWallet)
 }
 
+/*gas 补充余额回滚，热钱包+，用户-*/
+func (db *balancesDB) handleFallBackGasFunding(tx *gorm.DB, requestId string, balance *TokenBalance) error {
+	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, constant.AddressTypeHot, balance.FromAddress, balance.TokenAddress)
+	if err != nil {
+		log.Error("Query hot wallet failed", "err", err)
+		return err
+	}
+	hotWallet.Balance = new(big.Int).Add(hotWallet.Balance, balance.Balance)
+	if err := db.UpdateAndSaveBalance(tx, requestId, hotWallet); err != nil {
+		return err
+	}
+
+	userWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, constant.AddressTypeUser, balance.ToAddress, balance.TokenAddress)
+	if err != nil {
+		log.Error("Query user wallet failed", "err", err)
+		return err
+	}
+	userWallet.Balance = new(big.Int).Sub(userWallet.Balance, balance.Balance)
+	return db.UpdateAndSaveBalance(tx, requestId, userWallet)
+}
+
 /*提现余额回滚，热钱包余额增加*/
 func (db *balancesDB) handleFallBackWithdraw(tx *gorm.DB, requestId string, balance *TokenBalance) error {
 	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, constant.AddressTypeHot, balance.FromAddress, balance.TokenAddress)
@@ -532,6 +635,16 @@ func (db *balancesDB) handleFallBackDeposit(tx *gorm.DB, requestId string, balan
 	return db.UpdateAndSaveBalance(tx, requestId, userAddress)
 }
 
+/*查询项目方全部地址余额*/
+func (db *balancesDB) QueryBalanceList(requestId string) ([]*Balances, error) {
+	var balanceList []*Balances
+	err := db.gorm.Table("balances_" + requestId).Find(&balanceList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query balance list failed: %w", err)
+	}
+	return balanceList, nil
+}
+
 func NewBalancesDB(db *gorm.DB) BalancesDB {
 	return &balancesDB{gorm: db}
 }
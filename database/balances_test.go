@@ -1,9 +1,18 @@
 package database
 
 import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
 	"testing"
 
+	"exchange-wallet-service/database/constant"
+
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -33,3 +42,152 @@ func TestNewBalancesDB_WithSQLMock(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 }
+
+/*记录语句参数，供断言写入的值*/
+type argCapture struct {
+	values []driver.Value
+}
+
+func (c *argCapture) Match(v driver.Value) bool {
+	c.values = append(c.values, v)
+	return true
+}
+
+func (c *argCapture) args(n int) []driver.Value {
+	args := make([]driver.Value, n)
+	for i := range args {
+		args[i] = c
+	}
+	return args
+}
+
+/*余额表整行更新：address, token_address, address_type, balance, lock_balance, timestamp, guid*/
+const balanceSaveArgs = 7
+
+/*账本一借一贷两行，每行 12 列*/
+const (
+	ledgerColumns     = 12
+	ledgerJournalArgs = 2 * ledgerColumns
+)
+
+var balanceColumns = []string{"guid", "address", "token_address", "address_type", "balance", "lock_balance", "timestamp"}
+
+func balanceRow(guid uuid.UUID, address, tokenAddress common.Address, balance, lockBalance string) *sqlmock.Rows {
+	return sqlmock.NewRows(balanceColumns).
+		AddRow(guid.String(), fmt.Sprintf("0x%x", address.Bytes()), fmt.Sprintf("0x%x", tokenAddress.Bytes()), "hot", balance, lockBalance, 1)
+}
+
+/*按余额表保存参数取 balance、lock_balance*/
+func savedBalance(t *testing.T, capture *argCapture) (*big.Int, *big.Int) {
+	require.Len(t, capture.values, balanceSaveArgs)
+	return numericValue(t, capture.values[3]), numericValue(t, capture.values[4])
+}
+
+/*u256 序列化为 numeric 文本（如 900e0）*/
+func numericValue(t *testing.T, value driver.Value) *big.Int {
+	number, ok := new(big.Float).SetPrec(512).SetString(fmt.Sprint(value))
+	require.True(t, ok, "invalid numeric %v", value)
+	result, _ := number.Int(nil)
+	return result
+}
+
+/*把分录累加到账本余额：address 作为钱包资产账户，借增贷减*/
+func applyJournal(t *testing.T, ledger map[constant.LedgerBucket]*big.Int, address common.Address, capture *argCapture) {
+	require.Len(t, capture.values, ledgerJournalArgs)
+	for row := 0; row < 2; row++ {
+		values := capture.values[row*ledgerColumns : (row+1)*ledgerColumns]
+		if fmt.Sprint(values[6]) != fmt.Sprintf("0x%x", address.Bytes()) || fmt.Sprint(values[7]) == string(constant.AddressTypeExternal) {
+			continue
+		}
+		amount := numericValue(t, values[10])
+		bucket := constant.LedgerBucket(fmt.Sprint(values[8]))
+		if constant.LedgerDirection(fmt.Sprint(values[5])) == constant.LedgerCredit {
+			amount.Neg(amount)
+		}
+		ledger[bucket] = new(big.Int).Add(ledger[bucket], amount)
+	}
+}
+
+/*
+提现全流程：发送时锁定，确认时解锁并扣减，
+每一步后账本推导的可用、锁定余额都应与余额表一致
+*/
+func TestWithdrawLedgerMatchesBalances(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	hotAddress := common.HexToAddress("0xAbC0000000000000000000000000000000000001")
+	toAddress := common.HexToAddress("0x00000000000000000000000000000000000000E1")
+	tokenAddress := common.HexToAddress("0x00")
+	txHash := common.HexToHash("0x01")
+	guid := uuid.New()
+	amount := big.NewInt(100)
+
+	/*初始：可用 1000，账本已有对应的 1000 可用*/
+	ledger := map[constant.LedgerBucket]*big.Int{
+		constant.LedgerBucketAvailable: big.NewInt(1000),
+		constant.LedgerBucketLocked:    big.NewInt(0),
+	}
+
+	/*1. 广播后锁定*/
+	lockSave, lockJournal := &argCapture{}, &argCapture{}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "balances_biz" WHERE address = \$1 AND token_address = \$2`).
+		WithArgs("0xabc0000000000000000000000000000000000001", "0x0000000000000000000000000000000000000000", 1).
+		WillReturnRows(balanceRow(guid, hotAddress, tokenAddress, "1000", "0"))
+	mock.ExpectExec(`UPDATE "balances_biz"`).WithArgs(lockSave.args(balanceSaveArgs)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "ledger_biz"`).WithArgs(lockJournal.args(ledgerJournalArgs)...).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewBalancesDB(gormDB)
+	err := db.UpdateBalanceListByTwoAddress("biz", []*Balances{{
+		Address:      hotAddress,
+		TokenAddress: tokenAddress,
+		LockBalance:  amount,
+		TxType:       constant.TxTypeWithdraw,
+		TxHash:       txHash,
+	}})
+	require.NoError(t, err)
+
+	balance, lockBalance := savedBalance(t, lockSave)
+	assert.Equal(t, "900", balance.String())
+	assert.Equal(t, "100", lockBalance.String())
+	applyJournal(t, ledger, hotAddress, lockJournal)
+	assert.Equal(t, balance.String(), ledger[constant.LedgerBucketAvailable].String())
+	assert.Equal(t, lockBalance.String(), ledger[constant.LedgerBucketLocked].String())
+
+	/*2. 发现器确认提现：解锁后从可用扣减*/
+	unlockJournal, confirmSave, transferJournal := &argCapture{}, &argCapture{}, &argCapture{}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "balances_biz" WHERE address = \$1 AND token_address = \$2`).
+		WillReturnRows(balanceRow(guid, hotAddress, tokenAddress, "900", "100"))
+	mock.ExpectExec(`INSERT INTO "ledger_biz"`).WithArgs(unlockJournal.args(ledgerJournalArgs)...).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`SELECT \* FROM "balances_biz" WHERE address = \$1 AND token_address = \$2`).
+		WillReturnRows(balanceRow(guid, hotAddress, tokenAddress, "900", "100"))
+	mock.ExpectExec(`UPDATE "balances_biz"`).WithArgs(confirmSave.args(balanceSaveArgs)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "ledger_biz"`).WithArgs(transferJournal.args(ledgerJournalArgs)...).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = db.UpdateOrCreate("biz", []*TokenBalance{{
+		FromAddress:  hotAddress,
+		ToAddress:    toAddress,
+		TokenAddress: tokenAddress,
+		Balance:      amount,
+		TxType:       constant.TxTypeWithdraw,
+		TxHash:       txHash,
+	}})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	balance, lockBalance = savedBalance(t, confirmSave)
+	assert.Equal(t, "900", balance.String())
+	assert.Equal(t, "0", lockBalance.String())
+	assert.Equal(t, string(constant.LedgerEventUnlock), fmt.Sprint(unlockJournal.values[2]))
+	applyJournal(t, ledger, hotAddress, unlockJournal)
+	applyJournal(t, ledger, hotAddress, transferJournal)
+	assert.Equal(t, balance.String(), ledger[constant.LedgerBucketAvailable].String())
+	assert.Equal(t, lockBalance.String(), ledger[constant.LedgerBucketLocked].String())
+}
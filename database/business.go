@@ -2,9 +2,11 @@ package database
 
 import (
 	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"math/big"
 )
 
 // Business 代表业务系统注册的信息。
@@ -13,6 +15,8 @@ type Business struct {
 	BusinessUid string    `json:"business_uid"`
 	NotifyUrl   string    `json:"notify_url"`
 	Timestamp   uint64
+	// FeeCeiling 每单位 gas 最高价格（wei），0 表示不限制。
+	FeeCeiling *big.Int `gorm:"type:numeric;serializer:u256" json:"fee_ceiling"`
 }
 
 // BusinessDB 定义了对 business 表的写操作接口（包含读接口 BusinessView）。
@@ -20,6 +24,7 @@ type BusinessDB interface {
 	BusinessView
 
 	StoreBusiness(*Business) error
+	UpdateFeeCeiling(businessUuid string, feeCeiling *big.Int) error
 }
 
 // businessDB 是 BusinessDB 的具体实现。
@@ -64,6 +69,23 @@ func (db *businessDB) QueryBusinessByUuid(businessUuid string) (*Business, error
 
 // StoreBusiness 将新的业务记录插入 business 表。
 func (db *businessDB) StoreBusiness(business *Business) error {
+	if business.FeeCeiling == nil {
+		business.FeeCeiling = big.NewInt(0)
+	}
 	result := db.gorm.Table("business").Create(business)
 	return result.Error
 }
+
+// UpdateFeeCeiling 更新项目方的 gas 费率上限。
+func (db *businessDB) UpdateFeeCeiling(businessUuid string, feeCeiling *big.Int) error {
+	result := db.gorm.Table("business").
+		Where("business_uid = ?", businessUuid).
+		Update("fee_ceiling", feeCeiling.String())
+	if result.Error != nil {
+		return fmt.Errorf("update fee ceiling failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("business not found: %s", businessUuid)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+/*项目方每日配额（UTC 自然日），0 表示不限制；business_uid 为 * 表示默认配额*/
+type BusinessQuotas struct {
+	GUID        uuid.UUID `gorm:"primary_key" json:"guid"`
+	BusinessUid string    `gorm:"type:varchar;not null" json:"business_uid"`
+	/*每日导出地址数*/
+	DailyAddresses int64 `gorm:"type:bigint;not null;default:0" json:"daily_addresses"`
+	/*每日提现笔数，批量提现按笔计*/
+	DailyWithdraws int64  `gorm:"type:bigint;not null;default:0" json:"daily_withdraws"`
+	Timestamp      uint64 `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+/*项目方每日配额用量，按 UTC 自然日零点（unix 秒）分行*/
+type BusinessQuotaUsages struct {
+	BusinessUid string `gorm:"primary_key;type:varchar" json:"business_uid"`
+	Day         uint64 `gorm:"primary_key;type:bigint" json:"day"`
+	Addresses   int64  `gorm:"type:bigint;not null;default:0" json:"addresses"`
+	Withdraws   int64  `gorm:"type:bigint;not null;default:0" json:"withdraws"`
+	Timestamp   uint64 `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type BusinessQuotasView interface {
+	QueryBusinessQuotas() ([]*BusinessQuotas, error)
+}
+
+type BusinessQuotasDB interface {
+	BusinessQuotasView
+
+	StoreBusinessQuota(quota *BusinessQuotas) error
+	ConsumeDailyAddresses(businessUid string, day uint64, count int64, quota int64) (bool, error)
+	ConsumeDailyWithdraws(businessUid string, day uint64, count int64, quota int64) (bool, error)
+}
+
+type businessQuotasDB struct {
+	gorm *gorm.DB
+}
+
+func NewBusinessQuotasDB(db *gorm.DB) BusinessQuotasDB {
+	return &businessQuotasDB{gorm: db}
+}
+
+/*查询所有项目方配额*/
+func (db *businessQuotasDB) QueryBusinessQuotas() ([]*BusinessQuotas, error) {
+	var quotas []*BusinessQuotas
+	if err := db.gorm.Table("business_quotas").Find(&quotas).Error; err != nil {
+		return nil, fmt.Errorf("query business quotas failed: %w", err)
+	}
+	return quotas, nil
+}
+
+/*新增或更新项目方配额*/
+func (db *businessQuotasDB) StoreBusinessQuota(quota *BusinessQuotas) error {
+	return db.gorm.Table("business_quotas").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "business_uid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"daily_addresses", "daily_withdraws", "timestamp"}),
+	}).Create(quota).Error
+}
+
+/*占用当日导出地址配额，超出配额返回 false*/
+func (db *businessQuotasDB) ConsumeDailyAddresses(businessUid string, day uint64, count int64, quota int64) (bool, error) {
+	return db.consume(businessUid, day, "addresses", count, quota)
+}
+
+/*占用当日提现配额，超出配额返回 false*/
+func (db *businessQuotasDB) ConsumeDailyWithdraws(businessUid string, day uint64, count int64, quota int64) (bool, error) {
+	return db.consume(businessUid, day, "withdraws", count, quota)
+}
+
+/*
+先确保当日用量行存在，再按 用量 + 本次 <= 配额 条件递增：
+并发请求由行锁串行，条件不满足时不更新任何行，不会超出配额
+*/
+func (db *businessQuotasDB) consume(businessUid string, day uint64, column string, count int64, quota int64) (bool, error) {
+	now := uint64(time.Now().Unix())
+	err := db.gorm.Table("business_quota_usages").Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&BusinessQuotaUsages{BusinessUid: businessUid, Day: day, Timestamp: now}).Error
+	if err != nil {
+		return false, fmt.Errorf("create quota usage failed: %w", err)
+	}
+	result := db.gorm.Table("business_quota_usages").
+		Where("business_uid = ? AND day = ? AND "+column+" + ? <= ?", businessUid, day, count, quota).
+		Updates(map[string]interface{}{
+			column:      gorm.Expr(column+" + ?", count),
+			"timestamp": now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("consume %s quota failed: %w", column, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
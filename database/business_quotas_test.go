@@ -0,0 +1,108 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBusinessQuotas(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "business_quotas"`).
+		WillReturnRows(sqlmock.NewRows([]string{"guid", "business_uid", "daily_addresses", "daily_withdraws", "timestamp"}).
+			AddRow(uuid.New().String(), "*", 1000, 0, 1).
+			AddRow(uuid.New().String(), "biz", 10, 5, 1))
+
+	db := NewBusinessQuotasDB(gormDB)
+	quotas, err := db.QueryBusinessQuotas()
+	require.NoError(t, err)
+	require.Len(t, quotas, 2)
+	assert.Equal(t, "*", quotas[0].BusinessUid)
+	assert.Equal(t, int64(1000), quotas[0].DailyAddresses)
+	assert.Equal(t, int64(5), quotas[1].DailyWithdraws)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*同一项目方重复设置时覆盖配额*/
+func TestStoreBusinessQuotaUpsert(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	quota := &BusinessQuotas{GUID: uuid.New(), BusinessUid: "biz", DailyAddresses: 10, DailyWithdraws: 5, Timestamp: 1}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "business_quotas" .* ON CONFLICT \("business_uid"\) DO UPDATE SET "daily_addresses"="excluded"\."daily_addresses","daily_withdraws"="excluded"\."daily_withdraws","timestamp"="excluded"\."timestamp"`).
+		WithArgs(quota.GUID, "biz", int64(10), int64(5), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewBusinessQuotasDB(gormDB)
+	require.NoError(t, db.StoreBusinessQuota(quota))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*配额用量：确保当日用量行存在，再按 用量 + 本次 <= 配额 条件递增*/
+func TestConsumeDailyWithdraws(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "business_quota_usages" \("business_uid","day","addresses","withdraws","timestamp"\) VALUES \(\$1,\$2,\$3,\$4,\$5\) ON CONFLICT DO NOTHING`).
+			WithArgs("biz", uint64(1700006400), int64(0), int64(0), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "business_quota_usages" SET "timestamp"=\$1,"withdraws"=withdraws \+ \$2 WHERE business_uid = \$3 AND day = \$4 AND withdraws \+ \$5 <= \$6`).
+			WithArgs(sqlmock.AnyArg(), int64(3), "biz", uint64(1700006400), int64(3), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	db := NewBusinessQuotasDB(gormDB)
+	ok, err := db.ConsumeDailyWithdraws("biz", 1700006400, 3, 5)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	/*超出配额不更新任何行*/
+	ok, err = db.ConsumeDailyWithdraws("biz", 1700006400, 3, 5)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConsumeDailyAddresses(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "business_quota_usages" .* ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "business_quota_usages" SET "addresses"=addresses \+ \$1,"timestamp"=\$2 WHERE business_uid = \$3 AND day = \$4 AND addresses \+ \$5 <= \$6`).
+		WithArgs(int64(7), sqlmock.AnyArg(), "biz", uint64(1700006400), int64(7), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewBusinessQuotasDB(gormDB)
+	ok, err := db.ConsumeDailyAddresses("biz", 1700006400, 7, 10)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
@@ -18,7 +19,9 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	dialector := postgres.New(postgres.Config{
 		Conn: db,
 	})
-	gormDB, err := gorm.Open(dialector, &gorm.Config{})
+	gormDB, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
 	assert.NoError(t, err)
 
 	return gormDB, mock
@@ -78,7 +81,7 @@ func TestStoreBusiness(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(`INSERT INTO "business"`).
-		WithArgs(biz.GUID, biz.BusinessUid, biz.NotifyUrl, biz.Timestamp).
+		WithArgs(biz.GUID, biz.BusinessUid, biz.NotifyUrl, biz.Timestamp, sqlmock.AnyArg()).
 		WillReturnResult(driver.ResultNoRows)
 	mock.ExpectCommit()
 
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*项目方查询接口缓存版本：worker 变更项目方数据时递增，缓存 key 带版本，版本变化即失效*/
+type CacheVersions struct {
+	BusinessUid string `gorm:"primary_key;type:varchar" json:"business_uid"`
+	Version     int64  `gorm:"type:bigint;not null;default:0" json:"version"`
+	Timestamp   uint64 `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type CacheVersionsView interface {
+	QueryCacheVersion(businessUid string) (int64, error)
+}
+
+type CacheVersionsDB interface {
+	CacheVersionsView
+
+	BumpCacheVersion(businessUid string) error
+}
+
+type cacheVersionsDB struct {
+	gorm *gorm.DB
+}
+
+func NewCacheVersionsDB(db *gorm.DB) CacheVersionsDB {
+	return &cacheVersionsDB{gorm: db}
+}
+
+/*查询项目方缓存版本，未变更过为 0*/
+func (db *cacheVersionsDB) QueryCacheVersion(businessUid string) (int64, error) {
+	var cacheVersion CacheVersions
+	err := db.gorm.Table("cache_versions").Where("business_uid = ?", businessUid).Take(&cacheVersion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("query cache version failed: %w", err)
+	}
+	return cacheVersion.Version, nil
+}
+
+/*递增项目方缓存版本，应与数据变更在同一事务内*/
+func (db *cacheVersionsDB) BumpCacheVersion(businessUid string) error {
+	cacheVersion := &CacheVersions{
+		BusinessUid: businessUid,
+		Version:     1,
+		Timestamp:   uint64(time.Now().Unix()),
+	}
+	err := db.gorm.Table("cache_versions").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "business_uid"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"version":   gorm.Expr("cache_versions.version + 1"),
+			"timestamp": cacheVersion.Timestamp,
+		}),
+	}).Create(cacheVersion).Error
+	if err != nil {
+		return fmt.Errorf("bump cache version failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCacheVersion(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "cache_versions" WHERE business_uid = \$1 LIMIT \$2`).
+		WithArgs("biz", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"business_uid", "version", "timestamp"}).AddRow("biz", 3, 1))
+
+	db := NewCacheVersionsDB(gormDB)
+	version, err := db.QueryCacheVersion("biz")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*未变更过的项目方版本为 0*/
+func TestQueryCacheVersionNotFound(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "cache_versions" WHERE business_uid = \$1 LIMIT \$2`).
+		WithArgs("biz", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"business_uid", "version", "timestamp"}))
+
+	db := NewCacheVersionsDB(gormDB)
+	version, err := db.QueryCacheVersion("biz")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*首次写入版本 1，已存在时在库内原子递增*/
+func TestBumpCacheVersion(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "cache_versions" \("business_uid","version","timestamp"\) VALUES \(\$1,\$2,\$3\) ON CONFLICT \("business_uid"\) DO UPDATE SET "timestamp"=\$4,"version"=cache_versions\.version \+ 1`).
+		WithArgs("biz", int64(1), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewCacheVersionsDB(gormDB)
+	require.NoError(t, db.BumpCacheVersion("biz"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
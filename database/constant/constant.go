@@ -17,8 +17,40 @@ const (
 	TxStatusWalletDone TxStatus = "wallet_done" /*交易已完全确认*/
 	TxStatusNotified   TxStatus = "notified"
 	TxStatusFallback   TxStatus = "fallback"
+	/*非白名单代币充值，隔离待人工处理*/
+	TxStatusQuarantine TxStatus = "quarantine"
+	TxStatusIgnored    TxStatus = "ignored"
+	/*低于最小充值金额的粉尘充值，不入账、不通知*/
+	TxStatusDust TxStatus = "dust"
+	/*地址筛查命中，挂起待人工处理，通知后为 hold_notified*/
+	TxStatusHold         TxStatus = "hold"
+	TxStatusHoldNotified TxStatus = "hold_notified"
+	/*风险评分拒绝的提现，不签名*/
+	TxStatusRejected TxStatus = "rejected"
 )
 
+/*风险评分处置动作*/
+type RiskAction string
+
+const (
+	RiskActionAllow  RiskAction = "allow"
+	RiskActionHold   RiskAction = "hold"
+	RiskActionReject RiskAction = "reject"
+)
+
+func ParseRiskAction(s string) (RiskAction, error) {
+	switch strings.ToLower(s) {
+	case string(RiskActionAllow):
+		return RiskActionAllow, nil
+	case string(RiskActionHold):
+		return RiskActionHold, nil
+	case string(RiskActionReject):
+		return RiskActionReject, nil
+	default:
+		return "", fmt.Errorf("invalid risk action: %s", s)
+	}
+}
+
 type TokenType string
 
 const (
@@ -34,6 +66,8 @@ const (
 	AddressTypeUser AddressType = "user"
 	AddressTypeHot  AddressType = "hot"
 	AddressTypeCold AddressType = "cold"
+	/*钱包外部地址，仅用于账本对手方*/
+	AddressTypeExternal AddressType = "external"
 )
 
 func (at AddressType) String() string {
@@ -62,6 +96,8 @@ const (
 	TxTypeCollection TransactionType = "collection"
 	TxTypeHot2Cold   TransactionType = "hot2cold"
 	TxTypeCold2Hot   TransactionType = "cold2hot"
+	/*归集代币前给用户地址补充主币作为 gas*/
+	TxTypeGasFunding TransactionType = "gas_funding"
 )
 
 func ParseTransactionType(s string) (TransactionType, error) {
@@ -76,7 +112,38 @@ func ParseTransactionType(s string) (TransactionType, error) {
 		return TxTypeHot2Cold, nil
 	case string(TxTypeCold2Hot):
 		return TxTypeCold2Hot, nil
+	case string(TxTypeGasFunding):
+		return TxTypeGasFunding, nil
 	default:
 		return TxTypeUnKnow, errors.New("unknown transaction type")
 	}
 }
+
+/*账本借贷方向：钱包地址为资产账户，借增贷减*/
+type LedgerDirection string
+
+const (
+	LedgerDebit  LedgerDirection = "debit"
+	LedgerCredit LedgerDirection = "credit"
+)
+
+/*账本余额分类：可用、锁定*/
+type LedgerBucket string
+
+const (
+	LedgerBucketAvailable LedgerBucket = "available"
+	LedgerBucketLocked    LedgerBucket = "locked"
+)
+
+/*账本事件*/
+type LedgerEvent string
+
+const (
+	/*充值、提现、归集、冷热互转*/
+	LedgerEventTransfer LedgerEvent = "transfer"
+	LedgerEventLock     LedgerEvent = "lock"
+	LedgerEventUnlock   LedgerEvent = "unlock"
+	LedgerEventFee      LedgerEvent = "fee"
+	/*回滚冲正*/
+	LedgerEventFallback LedgerEvent = "fallback"
+)
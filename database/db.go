@@ -21,22 +21,38 @@ import (
 
 // DB 封装了 GORM 的数据库连接以及后续可能扩展的其他表接口。
 type DB struct {
-	gorm         *gorm.DB
-	CreateTable  dynamic.CreateTableDB
-	Business     BusinessDB
-	Blocks       BlocksDB
-	ReorgBlocks  ReorgBlocksDB
-	Address      AddressDB
-	Balances     BalancesDB
-	Deposits     DepositsDB
-	Withdraws    WithdrawDB
-	Internals    InternalsDB
-	Transactions TransactionsDB
-	Tokens       TokensDB
+	gorm            *gorm.DB
+	CreateTable     dynamic.CreateTableDB
+	Business        BusinessDB
+	Blocks          BlocksDB
+	ReorgBlocks     ReorgBlocksDB
+	Address         AddressDB
+	Balances        BalancesDB
+	Deposits        DepositsDB
+	Withdraws       WithdrawDB
+	Internals       InternalsDB
+	Transactions    TransactionsDB
+	Tokens          TokensDB
+	NftHoldings     NftHoldingsDB
+	Reconciliations ReconciliationsDB
+	Ledger          LedgerDB
+	Snapshots       BalanceSnapshotsDB
+	Fees            FeesDB
+	WithdrawBatches WithdrawBatchesDB
+	ProofOfReserves ProofOfReservesDB
+	RateLimits      RateLimitsDB
+	BusinessQuotas  BusinessQuotasDB
+	CacheVersions   CacheVersionsDB
+
+	/*从库，未开启为 nil，只读查询经 Replica() 路由*/
+	replica *replica
 }
 
-// Close 关闭底层数据库连接。
+// Close 关闭底层数据库连接（含从库）。
 func (db *DB) Close() error {
+	if err := db.replica.close(); err != nil {
+		return err
+	}
 	sql, err := db.gorm.DB()
 	if err != nil {
 		return err
@@ -66,23 +82,26 @@ func (db *DB) ExecuteSQLMigration(migrationsFolder string) error {
 	return err
 }
 
+/*检查数据库连通性*/
+func (db *DB) Ping(ctx context.Context) error {
+	sql, err := db.gorm.DB()
+	if err != nil {
+		return err
+	}
+	return sql.PingContext(ctx)
+}
+
+/*绑定请求上下文（链路追踪、超时），返回共享连接的副本*/
+func (db *DB) WithContext(ctx context.Context) *DB {
+	ctxDB := newDB(db.gorm.WithContext(ctx))
+	ctxDB.replica = db.replica
+	return ctxDB
+}
+
 /*开启事务封装*/
 func (db *DB) Transaction(fn func(db *DB) error) error {
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
-		txDB := &DB{
-			gorm:         tx,
-			CreateTable:  dynamic.NewCreateTableDB(tx),
-			Blocks:       NewBlocksDB(tx),
-			ReorgBlocks:  NewReorgBlocksDB(tx),
-			Address:      NewAddressDB(tx),
-			Balances:     NewBalancesDB(tx),
-			Deposits:     NewDepositsDB(tx),
-			Withdraws:    NewWithdrawsDB(tx),
-			Transactions: NewTransactionsDB(tx),
-			Tokens:       NewTokensDB(tx),
-			Business:     NewBusinessDB(tx),
-			Internals:    NewInternalsDB(tx),
-		}
+		txDB := newDB(tx)
 		return fn(txDB)
 	})
 }
@@ -90,6 +109,34 @@ func (db *DB) Transaction(fn func(db *DB) error) error {
 // NewDB 根据配置创建一个新的数据库连接，并封装成 DB 结构体。
 // 支持使用重试策略处理初始化连接失败的情况。
 func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
+	gormDb, err := openGorm(ctx, dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newDB(gormDb), nil
+}
+
+// NewDBWithReplica 连接主库，开启从库（SlaveDbEnable）时同时连接从库，
+// 只读查询经 Replica() 路由到从库，从库延迟超过 SlaveDbMaxLag 时回退主库。
+func NewDBWithReplica(ctx context.Context, cfg *config.Config) (*DB, error) {
+	db, err := NewDB(ctx, cfg.MasterDB)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.SlaveDbEnable {
+		return db, nil
+	}
+	replicaGorm, err := openGorm(ctx, cfg.SlaveDB)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to slave database: %w", err)
+	}
+	db.replica = newReplica(replicaGorm, cfg.SlaveDbMaxLag)
+	return db, nil
+}
+
+/*打开 gorm 连接并注册指标、链路追踪回调*/
+func openGorm(ctx context.Context, dbConfig config.DBConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s dbname=%s sslmode=disable", dbConfig.Host, dbConfig.Name)
 	if dbConfig.Port != 0 {
 		dsn += fmt.Sprintf(" port=%d", dbConfig.Port)
@@ -128,20 +175,39 @@ func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := registerMetricsCallbacks(gormDbBox); err != nil {
+		return nil, err
+	}
+	if err := registerTracingCallbacks(gormDbBox); err != nil {
+		return nil, err
+	}
+	return gormDbBox, nil
+}
 
-	db := &DB{
-		gorm:         gormDbBox,
-		CreateTable:  dynamic.NewCreateTableDB(gormDbBox),
-		Business:     NewBusinessDB(gormDbBox),
-		Blocks:       NewBlocksDB(gormDbBox),
-		ReorgBlocks:  NewReorgBlocksDB(gormDbBox),
-		Address:      NewAddressDB(gormDbBox),
-		Balances:     NewBalancesDB(gormDbBox),
-		Deposits:     NewDepositsDB(gormDbBox),
-		Withdraws:    NewWithdrawsDB(gormDbBox),
-		Internals:    NewInternalsDB(gormDbBox),
-		Transactions: NewTransactionsDB(gormDbBox),
-		Tokens:       NewTokensDB(gormDbBox),
+/*基于 gorm 连接（或事务）构造各表接口*/
+func newDB(gormDb *gorm.DB) *DB {
+	return &DB{
+		gorm:            gormDb,
+		CreateTable:     dynamic.NewCreateTableDB(gormDb),
+		Business:        NewBusinessDB(gormDb),
+		Blocks:          NewBlocksDB(gormDb),
+		ReorgBlocks:     NewReorgBlocksDB(gormDb),
+		Address:         NewAddressDB(gormDb),
+		Balances:        NewBalancesDB(gormDb),
+		Deposits:        NewDepositsDB(gormDb),
+		Withdraws:       NewWithdrawsDB(gormDb),
+		Internals:       NewInternalsDB(gormDb),
+		Transactions:    NewTransactionsDB(gormDb),
+		Tokens:          NewTokensDB(gormDb),
+		NftHoldings:     NewNftHoldingsDB(gormDb),
+		Reconciliations: NewReconciliationsDB(gormDb),
+		Ledger:          NewLedgerDB(gormDb),
+		Snapshots:       NewBalanceSnapshotsDB(gormDb),
+		Fees:            NewFeesDB(gormDb),
+		WithdrawBatches: NewWithdrawBatchesDB(gormDb),
+		ProofOfReserves: NewProofOfReservesDB(gormDb),
+		RateLimits:      NewRateLimitsDB(gormDb),
+		BusinessQuotas:  NewBusinessQuotasDB(gormDb),
+		CacheVersions:   NewCacheVersionsDB(gormDb),
 	}
-	return db, nil
 }
@@ -25,6 +25,8 @@ type Deposits struct {
 	FromAddress common.Address `gorm:"type:varchar;not null;serializer:bytes" json:"from_address"`
 	ToAddress   common.Address `gorm:"type:varchar;not null;serializer:bytes" json:"to_address"`
 	Amount      *big.Int       `gorm:"not null;serializer:u256" json:"amount"`
+	/*链上实际支付的手续费（付款方为外部地址，不记账）*/
+	Fee *big.Int `gorm:"not null;default:0;serializer:u256" json:"fee"`
 
 	GasLimit             uint64 `gorm:"not null" json:"gas_limit"`
 	MaxFeePerGas         string `gorm:"type:varchar;not null" json:"max_fee_per_gas"`
@@ -36,10 +38,17 @@ type Deposits struct {
 	TokenMeta    string             `gorm:"type:varchar;not null" json:"token_meta"`
 
 	TxSignHex string `gorm:"type:varchar;not null" json:"tx_sign_hex"`
+	/*地址筛查命中原因*/
+	RiskReason string `gorm:"type:varchar;not null;default:''" json:"risk_reason"`
+	/*风险评分与处置动作*/
+	RiskScore  int                 `gorm:"not null;default:0" json:"risk_score"`
+	RiskAction constant.RiskAction `gorm:"type:varchar;not null;default:''" json:"risk_action"`
 }
 
 type DepositsView interface {
 	QueryNotifyDeposits(requestId string) ([]*Deposits, error)
+	QueryQuarantineDeposits(requestId string) ([]*Deposits, error)
+	QueryConfirmedDeposits(requestId string, blockNumber uint64, confirms uint64) ([]*Deposits, error)
 
 	// todo
 }
@@ -52,10 +61,20 @@ type DepositsDB interface {
 	UpdateDepositById(requestId string, guid string, signedTx string, status constant.TxStatus) error
 	UpdateDepositsConfirms(requestId string, blockNumber uint64, confirms uint64) error
 	UpdateDepositsStatusByTxHash(requestId string, status constant.TxStatus, depositList []*Deposits) error
+	UpdateDepositStatusById(requestId string, guid string, status constant.TxStatus) error
+	ReleaseQuarantineDeposit(requestId string, guid string, status constant.TxStatus) error
+	ReleaseHoldDeposit(requestId string, guid string, status constant.TxStatus, riskAction constant.RiskAction) error
+	UpdateDepositRiskById(requestId string, deposit *Deposits) error
 	HandleFallBackDeposits(requestId string, startBlock, EndBlock *big.Int) error
 	// todo
 }
 
+/*充值不存在或已不在隔离状态*/
+var ErrDepositNotQuarantined = errors.New("deposit is not in quarantine")
+
+/*充值不存在或已不在挂起状态*/
+var ErrDepositNotHeld = errors.New("deposit is not on hold")
+
 type depositsDB struct {
 	gorm *gorm.DB
 }
@@ -170,11 +189,11 @@ func (db *depositsDB) HandleFallBackDeposits(requestId string, startBlock, EndBl
 	return nil
 }
 
-/*查询充值通知交易*/
+/*查询充值通知交易（已确认的充值与筛查挂起的充值）*/
 func (db *depositsDB) QueryNotifyDeposits(requestId string) ([]*Deposits, error) {
 	var notifyDeposits []*Deposits
 	result := db.gorm.Table("deposits_"+requestId).
-		Where("status = ? ", constant.TxStatusWalletDone).
+		Where("status IN ?", []constant.TxStatus{constant.TxStatusWalletDone, constant.TxStatusHold}).
 		Find(&notifyDeposits) // Correctly populate the slice
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -221,6 +240,104 @@ func (db *depositsDB) UpdateDepositsStatusByTxHash(requestId string, status cons
 	})
 }
 
+/*查询隔离中的充值（非白名单代币）*/
+func (db *depositsDB) QueryQuarantineDeposits(requestId string) ([]*Deposits, error) {
+	var quarantineDeposits []*Deposits
+	result := db.gorm.Table("deposits_"+requestId).
+		Where("status = ?", constant.TxStatusQuarantine).
+		Order("block_number ASC").
+		Find(&quarantineDeposits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return quarantineDeposits, nil
+}
+
+/*根据 id 更新充值状态*/
+func (db *depositsDB) UpdateDepositStatusById(requestId string, guid string, status constant.TxStatus) error {
+	result := db.gorm.Table("deposits_"+requestId).
+		Where("guid = ?", guid).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("update deposit status failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("deposit not found for GUID: %s", guid)
+	}
+	return nil
+}
+
+/*
+隔离充值出隔离：仅当状态仍为 quarantine 时更新，应在入账前于同一事务内调用；
+并发或重复处理时后到者不更新任何行，返回 ErrDepositNotQuarantined
+*/
+func (db *depositsDB) ReleaseQuarantineDeposit(requestId string, guid string, status constant.TxStatus) error {
+	result := db.gorm.Table("deposits_"+requestId).
+		Where("guid = ? AND status = ?", guid, constant.TxStatusQuarantine).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("release quarantine deposit failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrDepositNotQuarantined
+	}
+	return nil
+}
+
+/*
+挂起充值人工处理：仅当状态仍为 hold / hold_notified 时更新状态与处置动作，应在入账前于同一事务内调用；
+并发或重复处理时后到者不更新任何行，返回 ErrDepositNotHeld
+*/
+func (db *depositsDB) ReleaseHoldDeposit(requestId string, guid string, status constant.TxStatus, riskAction constant.RiskAction) error {
+	result := db.gorm.Table("deposits_"+requestId).
+		Where("guid = ? AND status IN ?", guid, []constant.TxStatus{constant.TxStatusHold, constant.TxStatusHoldNotified}).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"risk_action": riskAction,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("release hold deposit failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrDepositNotHeld
+	}
+	return nil
+}
+
+/*查询本轮将过确认位的充值（状态 success 且确认数已足够），供风险评分*/
+func (db *depositsDB) QueryConfirmedDeposits(requestId string, blockNumber uint64, confirms uint64) ([]*Deposits, error) {
+	if blockNumber < confirms {
+		return nil, nil
+	}
+	var confirmedDeposits []*Deposits
+	result := db.gorm.Table("deposits_"+requestId).
+		Where("block_number <= ? AND status = ?", blockNumber-confirms, constant.TxStatusSuccess).
+		Find(&confirmedDeposits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return confirmedDeposits, nil
+}
+
+/*根据 id 更新充值的风险评分结果（状态、分数、动作、原因）*/
+func (db *depositsDB) UpdateDepositRiskById(requestId string, deposit *Deposits) error {
+	result := db.gorm.Table("deposits_"+requestId).
+		Where("guid = ?", deposit.GUID).
+		Updates(map[string]interface{}{
+			"status":      deposit.Status,
+			"risk_score":  deposit.RiskScore,
+			"risk_action": deposit.RiskAction,
+			"risk_reason": deposit.RiskReason,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("update deposit risk failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("deposit not found for GUID: %s", deposit.GUID)
+	}
+	return nil
+}
+
 func NewDepositsDB(db *gorm.DB) DepositsDB {
 	return &depositsDB{gorm: db}
 }
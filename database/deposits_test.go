@@ -3,7 +3,10 @@ package database
 import (
 	"testing"
 
+	"exchange-wallet-service/database/constant"
+
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -35,3 +38,81 @@ func TestNewDepositsDB(t *testing.T) {
 		t.Fatalf("NewDepositsDB returned nil")
 	}
 }
+
+func TestReleaseQuarantineDeposit(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "deposits_biz-1" SET "status"=\$1 WHERE guid = \$2 AND status = \$3`).
+		WithArgs(constant.TxStatusSuccess, "deposit-guid", constant.TxStatusQuarantine).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewDepositsDB(gormDB)
+	err := db.ReleaseQuarantineDeposit("biz-1", "deposit-guid", constant.TxStatusSuccess)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseQuarantineDepositAlreadyHandled(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	/*已被并发或上一次请求处理，状态不再是 quarantine，不更新任何行*/
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "deposits_biz-1" SET "status"=\$1 WHERE guid = \$2 AND status = \$3`).
+		WithArgs(constant.TxStatusSuccess, "deposit-guid", constant.TxStatusQuarantine).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	db := NewDepositsDB(gormDB)
+	err := db.ReleaseQuarantineDeposit("biz-1", "deposit-guid", constant.TxStatusSuccess)
+	assert.ErrorIs(t, err, ErrDepositNotQuarantined)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseHoldDeposit(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "deposits_biz-1" SET "risk_action"=\$1,"status"=\$2 WHERE guid = \$3 AND status IN \(\$4,\$5\)`).
+		WithArgs(constant.RiskActionAllow, constant.TxStatusSuccess, "deposit-guid", constant.TxStatusHold, constant.TxStatusHoldNotified).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewDepositsDB(gormDB)
+	err := db.ReleaseHoldDeposit("biz-1", "deposit-guid", constant.TxStatusSuccess, constant.RiskActionAllow)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseHoldDepositAlreadyHandled(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	/*已被并发或上一次处理，状态不再是 hold / hold_notified，不更新任何行*/
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "deposits_biz-1" SET "risk_action"=\$1,"status"=\$2 WHERE guid = \$3 AND status IN \(\$4,\$5\)`).
+		WithArgs(constant.RiskActionReject, constant.TxStatusIgnored, "deposit-guid", constant.TxStatusHold, constant.TxStatusHoldNotified).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	db := NewDepositsDB(gormDB)
+	err := db.ReleaseHoldDeposit("biz-1", "deposit-guid", constant.TxStatusIgnored, constant.RiskActionReject)
+	assert.ErrorIs(t, err, ErrDepositNotHeld)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
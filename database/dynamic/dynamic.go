@@ -25,11 +25,18 @@ func (c *createTableDB) CreateTableFromTemplate(requestId string) {
 	err := c.gorm.Transaction(func(tx *gorm.DB) error {
 		c.createTable(tx, "addresses", fmt.Sprintf("addresses_%s", requestId))
 		c.createTable(tx, "balances", fmt.Sprintf("balances_%s", requestId))
+		c.createTable(tx, "nft_holdings", fmt.Sprintf("nft_holdings_%s", requestId))
 		c.createTable(tx, "transactions", fmt.Sprintf("transactions_%s", requestId))
 		c.createTable(tx, "deposits", fmt.Sprintf("deposits_%s", requestId))
 		c.createTable(tx, "withdraws", fmt.Sprintf("withdraws_%s", requestId))
 		c.createTable(tx, "internals", fmt.Sprintf("internals_%s", requestId))
 		c.createTable(tx, "tokens", fmt.Sprintf("tokens_%s", requestId))
+		c.createTable(tx, "reconciliations", fmt.Sprintf("reconciliations_%s", requestId))
+		c.createTable(tx, "ledger", fmt.Sprintf("ledger_%s", requestId))
+		c.createTable(tx, "balance_snapshots", fmt.Sprintf("balance_snapshots_%s", requestId))
+		c.createTable(tx, "fees", fmt.Sprintf("fees_%s", requestId))
+		c.createTable(tx, "withdraw_batches", fmt.Sprintf("withdraw_batches_%s", requestId))
+		c.createTable(tx, "proof_of_reserves", fmt.Sprintf("proof_of_reserves_%s", requestId))
 		return nil
 	})
 	if err != nil {
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"errors"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+	"strings"
+	"time"
+)
+
+/*
+手续费表：钱包发出的交易（提现、归集、热转冷、冷转热）链上实际支付的手续费，
+手续费以主币支付，入库同时扣减付款地址的主币余额并记账，区块回滚时退回
+*/
+type Fees struct {
+	GUID        uuid.UUID                `gorm:"primary_key" json:"guid"`
+	TxHash      common.Hash              `gorm:"type:varchar;not null;serializer:bytes" json:"tx_hash"`
+	TxType      constant.TransactionType `gorm:"type:varchar;not null" json:"tx_type"`
+	Address     common.Address           `gorm:"type:varchar;not null;serializer:bytes" json:"address"`
+	AddressType constant.AddressType     `gorm:"type:varchar;not null" json:"address_type"`
+	/*交易转出的代币，手续费本身始终为主币*/
+	TokenAddress common.Address    `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	Fee          *big.Int          `gorm:"type:numeric;not null;serializer:u256" json:"fee"`
+	BlockNumber  *big.Int          `gorm:"type:numeric;not null;serializer:u256" json:"block_number"`
+	Status       constant.TxStatus `gorm:"type:varchar;not null" json:"status"`
+	Timestamp    uint64            `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+/*手续费汇总：按交易类型、代币、日期（UTC）*/
+type FeeSummary struct {
+	TxType       constant.TransactionType
+	TokenAddress string
+	Day          string
+	TxCount      int64
+	TotalFee     string
+}
+
+type FeesView interface {
+	QueryFeesByTxHashes(requestId string, txHashes []common.Hash) (map[common.Hash]*Fees, error)
+	QueryFeeSummary(requestId string, startTime, endTime uint64) ([]*FeeSummary, error)
+}
+
+type FeesDB interface {
+	FeesView
+
+	StoreFees(requestId string, fees []*Fees) error
+	HandleFallBackFees(requestId string, startBlock, endBlock *big.Int) error
+}
+
+type feesDB struct {
+	gorm *gorm.DB
+}
+
+func NewFeesDB(db *gorm.DB) FeesDB {
+	return &feesDB{gorm: db}
+}
+
+/*手续费入库，扣减付款地址主币余额并记账*/
+func (db *feesDB) StoreFees(requestId string, fees []*Fees) error {
+	if len(fees) == 0 {
+		return nil
+	}
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("fees_" + requestId).Create(&fees).Error; err != nil {
+			return fmt.Errorf("store fees failed: %w", err)
+		}
+		for _, fee := range fees {
+			debited, err := adjustNativeBalance(tx, requestId, fee.Address, new(big.Int).Neg(fee.Fee))
+			if err != nil {
+				return err
+			}
+			payer := LedgerAccount{Address: fee.Address, AddressType: fee.AddressType, Bucket: constant.LedgerBucketAvailable}
+			if err := storeJournal(tx, requestId, constant.LedgerEventFee, fee.TxType, fee.TxHash,
+				feeCollector(), payer, common.Address{}, debited); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*回滚区块内的手续费：退回付款地址主币余额、冲正记账，状态改为 fallback*/
+func (db *feesDB) HandleFallBackFees(requestId string, startBlock, endBlock *big.Int) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		var fees []*Fees
+		err := tx.Table("fees_"+requestId).
+			Where("block_number >= ? AND block_number <= ? AND status = ?", startBlock.String(), endBlock.String(), constant.TxStatusSuccess).
+			Find(&fees).Error
+		if err != nil {
+			return fmt.Errorf("query fallback fees failed: %w", err)
+		}
+		for _, fee := range fees {
+			refunded, err := adjustNativeBalance(tx, requestId, fee.Address, fee.Fee)
+			if err != nil {
+				return err
+			}
+			payer := LedgerAccount{Address: fee.Address, AddressType: fee.AddressType, Bucket: constant.LedgerBucketAvailable}
+			if err := storeJournal(tx, requestId, constant.LedgerEventFallback, fee.TxType, fee.TxHash,
+				payer, feeCollector(), common.Address{}, refunded); err != nil {
+				return err
+			}
+		}
+		if len(fees) == 0 {
+			return nil
+		}
+		result := tx.Table("fees_"+requestId).
+			Where("block_number >= ? AND block_number <= ? AND status = ?", startBlock.String(), endBlock.String(), constant.TxStatusSuccess).
+			Update("status", constant.TxStatusFallback)
+		if result.Error != nil {
+			return fmt.Errorf("update fallback fees failed: %w", result.Error)
+		}
+		log.Info("handle fallback fees success", "requestId", requestId, "count", result.RowsAffected)
+		return nil
+	})
+}
+
+/*按交易哈希查手续费*/
+func (db *feesDB) QueryFeesByTxHashes(requestId string, txHashes []common.Hash) (map[common.Hash]*Fees, error) {
+	feeMap := make(map[common.Hash]*Fees)
+	if len(txHashes) == 0 {
+		return feeMap, nil
+	}
+	hashList := make([]string, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		hashList = append(hashList, txHash.String())
+	}
+	var fees []*Fees
+	err := db.gorm.Table("fees_"+requestId).
+		Where("tx_hash IN ? AND status = ?", hashList, constant.TxStatusSuccess).
+		Find(&fees).Error
+	if err != nil {
+		return nil, fmt.Errorf("query fees failed: %w", err)
+	}
+	for _, fee := range fees {
+		feeMap[fee.TxHash] = fee
+	}
+	return feeMap, nil
+}
+
+/*手续费汇总，时间范围为秒级时间戳，endTime 为 0 则不限*/
+func (db *feesDB) QueryFeeSummary(requestId string, startTime, endTime uint64) ([]*FeeSummary, error) {
+	tx := db.gorm.Table("fees_"+requestId).
+		Select("tx_type, token_address, to_char(to_timestamp(timestamp) AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*) AS tx_count, SUM(fee)::text AS total_fee").
+		Where("status = ? AND timestamp >= ?", constant.TxStatusSuccess, startTime)
+	if endTime > 0 {
+		tx = tx.Where("timestamp <= ?", endTime)
+	}
+	var summaries []*FeeSummary
+	err := tx.Group("tx_type, token_address, day").
+		Order("day ASC, tx_type ASC, token_address ASC").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, fmt.Errorf("query fee summary failed: %w", err)
+	}
+	return summaries, nil
+}
+
+/*手续费付款地址类型*/
+func FeePayerType(txType constant.TransactionType) (constant.AddressType, error) {
+	switch txType {
+	case constant.TxTypeWithdraw, constant.TxTypeHot2Cold, constant.TxTypeGasFunding:
+		return constant.AddressTypeHot, nil
+	case constant.TxTypeCollection:
+		return constant.AddressTypeUser, nil
+	case constant.TxTypeCold2Hot:
+		return constant.AddressTypeCold, nil
+	default:
+		return "", fmt.Errorf("transaction type %s does not pay fee from wallet", txType)
+	}
+}
+
+/*手续费对手方（矿工/验证者）记为外部零地址*/
+func feeCollector() LedgerAccount {
+	return LedgerAccount{Address: common.Address{}, AddressType: constant.AddressTypeExternal, Bucket: constant.LedgerBucketAvailable}
+}
+
+/*
+调整地址主币可用余额，返回实际变动金额（绝对值）：
+扣减超过库内余额时只扣到 0 并告警，差额由余额对账发现；没有主币余额记录时不变动
+*/
+func adjustNativeBalance(tx *gorm.DB, requestId string, address common.Address, delta *big.Int) (*big.Int, error) {
+	var balance Balances
+	result := tx.Table("balances_"+requestId).
+		Where("address = ? AND token_address = ?", strings.ToLower(address.String()), strings.ToLower(common.Address{}.String())).
+		Take(&balance)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			log.Warn("native balance not found, skip fee adjustment", "requestId", requestId, "address", address, "delta", delta)
+			return big.NewInt(0), nil
+		}
+		return nil, fmt.Errorf("query native balance failed: %w", result.Error)
+	}
+	if balance.Balance == nil {
+		balance.Balance = big.NewInt(0)
+	}
+	if new(big.Int).Add(balance.Balance, delta).Sign() < 0 {
+		log.Error("native balance is not enough for fee, debit to zero", "requestId", requestId, "address", address, "balance", balance.Balance, "fee", new(big.Int).Neg(delta))
+		delta = new(big.Int).Neg(balance.Balance)
+	}
+	balance.Balance = new(big.Int).Add(balance.Balance, delta)
+	balance.Timestamp = uint64(time.Now().Unix())
+	if err := tx.Table("balances_" + requestId).Save(&balance).Error; err != nil {
+		return nil, fmt.Errorf("save native balance failed: %w", err)
+	}
+	return new(big.Int).Abs(delta), nil
+}
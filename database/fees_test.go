@@ -0,0 +1,104 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*fees 表 10 列*/
+const feeColumns = 10
+
+/*手续费入库：扣减付款地址主币余额，余额不足只扣到 0，账本按实际扣减金额记账*/
+func TestStoreFeesDebitNativeBalance(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	hotAddress := common.HexToAddress("0x00000000000000000000000000000000000000D1")
+	balanceSave, journal := &argCapture{}, &argCapture{}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "fees_biz"`).
+		WithArgs((&argCapture{}).args(feeColumns)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "balances_biz" WHERE address = \$1 AND token_address = \$2`).
+		WithArgs("0x00000000000000000000000000000000000000d1", "0x0000000000000000000000000000000000000000", 1).
+		WillReturnRows(balanceRow(uuid.New(), hotAddress, common.Address{}, "50", "0"))
+	mock.ExpectExec(`UPDATE "balances_biz"`).
+		WithArgs(balanceSave.args(balanceSaveArgs)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "ledger_biz"`).
+		WithArgs(journal.args(ledgerJournalArgs)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewFeesDB(gormDB)
+	err := db.StoreFees("biz", []*Fees{{
+		GUID:         uuid.New(),
+		TxHash:       common.HexToHash("0x01"),
+		TxType:       constant.TxTypeWithdraw,
+		Address:      hotAddress,
+		AddressType:  constant.AddressTypeHot,
+		TokenAddress: common.Address{},
+		Fee:          big.NewInt(80),
+		BlockNumber:  big.NewInt(1000),
+		Status:       constant.TxStatusSuccess,
+		Timestamp:    1,
+	}})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	balance, _ := savedBalance(t, balanceSave)
+	assert.Equal(t, "0", balance.String())
+	assert.Equal(t, string(constant.LedgerEventFee), fmt.Sprint(journal.values[2]))
+	assert.Equal(t, "50", numericValue(t, journal.values[10]).String())
+	/*贷方为付款热钱包*/
+	assert.Equal(t, string(constant.LedgerCredit), fmt.Sprint(journal.values[ledgerColumns+5]))
+	assert.Equal(t, "0x00000000000000000000000000000000000000d1", fmt.Sprint(journal.values[ledgerColumns+6]))
+}
+
+func TestQueryFeeSummary(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT tx_type, token_address, .* AS day, COUNT\(\*\) AS tx_count, SUM\(fee\)::text AS total_fee FROM "fees_biz" WHERE \(status = \$1 AND timestamp >= \$2\) AND timestamp <= \$3 GROUP BY tx_type, token_address, day ORDER BY day ASC, tx_type ASC, token_address ASC`).
+		WithArgs(constant.TxStatusSuccess, 100, 200).
+		WillReturnRows(sqlmock.NewRows([]string{"tx_type", "token_address", "day", "tx_count", "total_fee"}).
+			AddRow("withdraw", "0x0000000000000000000000000000000000000000", "2024-01-01", 3, "21000000000000"))
+
+	db := NewFeesDB(gormDB)
+	summaries, err := db.QueryFeeSummary("biz", 100, 200)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, constant.TxTypeWithdraw, summaries[0].TxType)
+	assert.Equal(t, "2024-01-01", summaries[0].Day)
+	assert.Equal(t, int64(3), summaries[0].TxCount)
+	assert.Equal(t, "21000000000000", summaries[0].TotalFee)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeePayerType(t *testing.T) {
+	payer, err := FeePayerType(constant.TxTypeCollection)
+	require.NoError(t, err)
+	assert.Equal(t, constant.AddressTypeUser, payer)
+
+	payer, err = FeePayerType(constant.TxTypeCold2Hot)
+	require.NoError(t, err)
+	assert.Equal(t, constant.AddressTypeCold, payer)
+
+	_, err = FeePayerType(constant.TxTypeDeposit)
+	assert.Error(t, err)
+}
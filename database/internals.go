@@ -41,6 +41,9 @@ type Internals struct {
 
 	// 交易签名
 	TxSignHex string `json:"tx_sign_hex" gorm:"column:tx_sign_hex"`
+
+	// 代币归集依赖的 gas 补充交易 guid，补充交易上链后才发送归集
+	GasFundingGuid string `json:"gas_funding_guid" gorm:"column:gas_funding_guid"`
 }
 
 type InternalsView interface {
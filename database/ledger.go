@@ -0,0 +1,195 @@
+package database
+
+import (
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+	"strings"
+	"time"
+)
+
+/*
+复式记账账本（只追加）：
+每次余额变动写一借一贷两条分录（同一 journal id，金额相等），引用来源交易哈希。
+钱包地址为资产账户，借增贷减；钱包外的对手方记为 external 地址
+*/
+type Ledger struct {
+	GUID uuid.UUID `gorm:"primary_key" json:"guid"`
+	/*同一笔变动的借贷分录 journal id 相同*/
+	JournalId    uuid.UUID                `gorm:"type:varchar;not null" json:"journal_id"`
+	Event        constant.LedgerEvent     `gorm:"type:varchar;not null" json:"event"`
+	TxType       constant.TransactionType `gorm:"type:varchar;not null" json:"tx_type"`
+	TxHash       common.Hash              `gorm:"type:varchar;not null;serializer:bytes" json:"tx_hash"`
+	Direction    constant.LedgerDirection `gorm:"type:varchar;not null" json:"direction"`
+	Address      common.Address           `gorm:"type:varchar;not null;serializer:bytes" json:"address"`
+	AddressType  constant.AddressType     `gorm:"type:varchar;not null" json:"address_type"`
+	Bucket       constant.LedgerBucket    `gorm:"type:varchar;not null" json:"bucket"`
+	TokenAddress common.Address           `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	Amount       *big.Int                 `gorm:"type:numeric;not null;serializer:u256" json:"amount"`
+	Timestamp    uint64                   `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+/*账本账户*/
+type LedgerAccount struct {
+	Address     common.Address
+	AddressType constant.AddressType
+	Bucket      constant.LedgerBucket
+}
+
+/*账本推导的地址余额*/
+type LedgerBalance struct {
+	Available *big.Int
+	Locked    *big.Int
+}
+
+type LedgerView interface {
+	QueryLedgerByTxHash(requestId string, txHash common.Hash) ([]*Ledger, error)
+	QueryLedgerBalance(requestId string, address, tokenAddress common.Address) (*LedgerBalance, error)
+}
+
+type LedgerDB interface {
+	LedgerView
+
+	StoreJournal(requestId string, event constant.LedgerEvent, txType constant.TransactionType, txHash common.Hash,
+		debit, credit LedgerAccount, tokenAddress common.Address, amount *big.Int) error
+}
+
+type ledgerDB struct {
+	gorm *gorm.DB
+}
+
+func NewLedgerDB(db *gorm.DB) LedgerDB {
+	return &ledgerDB{gorm: db}
+}
+
+/*记一笔借贷分录*/
+func (db *ledgerDB) StoreJournal(requestId string, event constant.LedgerEvent, txType constant.TransactionType, txHash common.Hash,
+	debit, credit LedgerAccount, tokenAddress common.Address, amount *big.Int) error {
+	return storeJournal(db.gorm, requestId, event, txType, txHash, debit, credit, tokenAddress, amount)
+}
+
+/*按交易哈希查分录*/
+func (db *ledgerDB) QueryLedgerByTxHash(requestId string, txHash common.Hash) ([]*Ledger, error) {
+	var entries []*Ledger
+	err := db.gorm.Table("ledger_"+requestId).
+		Where("tx_hash = ?", txHash.String()).
+		Order("timestamp ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("query ledger failed: %w", err)
+	}
+	return entries, nil
+}
+
+/*由账本推导地址余额：各分类借方合计 - 贷方合计（不含作为外部对手方的分录）*/
+func (db *ledgerDB) QueryLedgerBalance(requestId string, address, tokenAddress common.Address) (*LedgerBalance, error) {
+	var sums []struct {
+		Bucket constant.LedgerBucket
+		Total  string
+	}
+	err := db.gorm.Table("ledger_"+requestId).
+		Select("bucket, SUM(CASE WHEN direction = ? THEN amount ELSE -amount END)::text AS total", constant.LedgerDebit).
+		Where("address = ? AND token_address = ? AND address_type != ?",
+			strings.ToLower(address.String()), strings.ToLower(tokenAddress.String()), constant.AddressTypeExternal).
+		Group("bucket").
+		Scan(&sums).Error
+	if err != nil {
+		return nil, fmt.Errorf("query ledger balance failed: %w", err)
+	}
+	balance := &LedgerBalance{Available: big.NewInt(0), Locked: big.NewInt(0)}
+	for _, sum := range sums {
+		total, ok := new(big.Int).SetString(sum.Total, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid ledger sum: %s", sum.Total)
+		}
+		switch sum.Bucket {
+		case constant.LedgerBucketAvailable:
+			balance.Available = total
+		case constant.LedgerBucketLocked:
+			balance.Locked = total
+		}
+	}
+	return balance, nil
+}
+
+/*写入借贷两条分录，余额表变动与分录在同一事务中*/
+func storeJournal(tx *gorm.DB, requestId string, event constant.LedgerEvent, txType constant.TransactionType, txHash common.Hash,
+	debit, credit LedgerAccount, tokenAddress common.Address, amount *big.Int) error {
+	if amount == nil || amount.Sign() == 0 {
+		return nil
+	}
+	journalId := uuid.New()
+	timestamp := uint64(time.Now().Unix())
+	entries := make([]*Ledger, 0, 2)
+	for _, leg := range []struct {
+		direction constant.LedgerDirection
+		account   LedgerAccount
+	}{{constant.LedgerDebit, debit}, {constant.LedgerCredit, credit}} {
+		entries = append(entries, &Ledger{
+			GUID:         uuid.New(),
+			JournalId:    journalId,
+			Event:        event,
+			TxType:       txType,
+			TxHash:       txHash,
+			Direction:    leg.direction,
+			Address:      leg.account.Address,
+			AddressType:  leg.account.AddressType,
+			Bucket:       leg.account.Bucket,
+			TokenAddress: tokenAddress,
+			Amount:       amount,
+			Timestamp:    timestamp,
+		})
+	}
+	if err := tx.Table("ledger_" + requestId).Create(&entries).Error; err != nil {
+		return fmt.Errorf("store ledger journal failed: %w", err)
+	}
+	return nil
+}
+
+/*
+按交易类型确定借贷账户：
+* 充值：借 用户，贷 外部 from
+* 提现：借 外部 to，贷 热钱包
+* 归集：借 热钱包，贷 用户
+* 热转冷：借 冷钱包，贷 热钱包
+* 冷转热：借 热钱包，贷 冷钱包
+* gas 补充：借 用户，贷 热钱包
+*/
+func transferAccounts(balance *TokenBalance) (LedgerAccount, LedgerAccount, error) {
+	account := func(address common.Address, addressType constant.AddressType) LedgerAccount {
+		return LedgerAccount{Address: address, AddressType: addressType, Bucket: constant.LedgerBucketAvailable}
+	}
+	switch balance.TxType {
+	case constant.TxTypeDeposit:
+		return account(balance.ToAddress, constant.AddressTypeUser), account(balance.FromAddress, constant.AddressTypeExternal), nil
+	case constant.TxTypeWithdraw:
+		return account(balance.ToAddress, constant.AddressTypeExternal), account(balance.FromAddress, constant.AddressTypeHot), nil
+	case constant.TxTypeCollection:
+		return account(balance.ToAddress, constant.AddressTypeHot), account(balance.FromAddress, constant.AddressTypeUser), nil
+	case constant.TxTypeHot2Cold:
+		return account(balance.ToAddress, constant.AddressTypeCold), account(balance.FromAddress, constant.AddressTypeHot), nil
+	case constant.TxTypeCold2Hot:
+		return account(balance.ToAddress, constant.AddressTypeHot), account(balance.FromAddress, constant.AddressTypeCold), nil
+	case constant.TxTypeGasFunding:
+		return account(balance.ToAddress, constant.AddressTypeUser), account(balance.FromAddress, constant.AddressTypeHot), nil
+	default:
+		return LedgerAccount{}, LedgerAccount{}, fmt.Errorf("unsupported transaction type: %s", balance.TxType)
+	}
+}
+
+/*交易余额变动记账，回滚冲正时借贷互换*/
+func storeTransferJournal(tx *gorm.DB, requestId string, balance *TokenBalance, fallback bool) error {
+	debit, credit, err := transferAccounts(balance)
+	if err != nil {
+		return err
+	}
+	event := constant.LedgerEventTransfer
+	if fallback {
+		event = constant.LedgerEventFallback
+		debit, credit = credit, debit
+	}
+	return storeJournal(tx, requestId, event, balance.TxType, balance.TxHash, debit, credit, balance.TokenAddress, balance.Balance)
+}
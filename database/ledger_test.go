@@ -0,0 +1,120 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*借贷两条分录 journal id、金额相同，方向相反*/
+func TestStoreJournal(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	hotAddress := common.HexToAddress("0x00000000000000000000000000000000000000D1")
+	coldAddress := common.HexToAddress("0x00000000000000000000000000000000000000E1")
+	capture := &argCapture{}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "ledger_biz" .* VALUES \(.*\),\(.*\)`).
+		WithArgs(capture.args(ledgerJournalArgs)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewLedgerDB(gormDB)
+	err := db.StoreJournal("biz", constant.LedgerEventTransfer, constant.TxTypeHot2Cold, common.HexToHash("0x01"),
+		LedgerAccount{Address: coldAddress, AddressType: constant.AddressTypeCold, Bucket: constant.LedgerBucketAvailable},
+		LedgerAccount{Address: hotAddress, AddressType: constant.AddressTypeHot, Bucket: constant.LedgerBucketAvailable},
+		common.Address{}, big.NewInt(100))
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	debit, credit := capture.values[:ledgerColumns], capture.values[ledgerColumns:]
+	assert.Equal(t, debit[1], credit[1])
+	assert.Equal(t, string(constant.LedgerDebit), fmt.Sprint(debit[5]))
+	assert.Equal(t, "0x00000000000000000000000000000000000000e1", fmt.Sprint(debit[6]))
+	assert.Equal(t, string(constant.LedgerCredit), fmt.Sprint(credit[5]))
+	assert.Equal(t, "0x00000000000000000000000000000000000000d1", fmt.Sprint(credit[6]))
+	assert.Equal(t, "100", numericValue(t, debit[10]).String())
+	assert.Equal(t, "100", numericValue(t, credit[10]).String())
+}
+
+/*金额为 0 不记账*/
+func TestStoreJournalZeroAmount(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	db := NewLedgerDB(gormDB)
+	err := db.StoreJournal("biz", constant.LedgerEventTransfer, constant.TxTypeHot2Cold, common.HexToHash("0x01"),
+		LedgerAccount{}, LedgerAccount{}, common.Address{}, big.NewInt(0))
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*各分类借方合计 - 贷方合计，排除外部对手方分录*/
+func TestQueryLedgerBalance(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT bucket, SUM\(CASE WHEN direction = \$1 THEN amount ELSE -amount END\)::text AS total FROM "ledger_biz" WHERE address = \$2 AND token_address = \$3 AND address_type != \$4 GROUP BY "bucket"`).
+		WithArgs(constant.LedgerDebit, "0x00000000000000000000000000000000000000d1", "0x0000000000000000000000000000000000000000", constant.AddressTypeExternal).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "total"}).
+			AddRow(string(constant.LedgerBucketAvailable), "900").
+			AddRow(string(constant.LedgerBucketLocked), "100"))
+
+	db := NewLedgerDB(gormDB)
+	balance, err := db.QueryLedgerBalance("biz", common.HexToAddress("0x00000000000000000000000000000000000000D1"), common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, "900", balance.Available.String())
+	assert.Equal(t, "100", balance.Locked.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*没有分录时余额为 0*/
+func TestQueryLedgerBalanceEmpty(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`FROM "ledger_biz"`).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "total"}))
+
+	db := NewLedgerDB(gormDB)
+	balance, err := db.QueryLedgerBalance("biz", common.HexToAddress("0x00000000000000000000000000000000000000D1"), common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, "0", balance.Available.String())
+	assert.Equal(t, "0", balance.Locked.String())
+}
+
+func TestQueryLedgerBalanceInvalidSum(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`FROM "ledger_biz"`).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "total"}).
+			AddRow(string(constant.LedgerBucketAvailable), "1.5"))
+
+	db := NewLedgerDB(gormDB)
+	_, err := db.QueryLedgerBalance("biz", common.HexToAddress("0x00000000000000000000000000000000000000D1"), common.Address{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"exchange-wallet-service/metrics"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+/*各类 gorm 回调在执行前后记录数据库操作耗时与错误数*/
+func registerMetricsCallbacks(db *gorm.DB) error {
+	callback := db.Callback()
+	registrations := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+	for _, r := range registrations {
+		if err := r.before("metrics:before_"+r.operation, metricsBefore); err != nil {
+			return errors.Wrap(err, "failed to register metrics callback")
+		}
+		if err := r.after("metrics:after_"+r.operation, metricsAfter(r.operation)); err != nil {
+			return errors.Wrap(err, "failed to register metrics callback")
+		}
+	}
+	return nil
+}
+
+func metricsBefore(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func metricsAfter(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		/*未查到记录不算错误*/
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		metrics.RecordDBCall(operation, time.Since(start), err)
+	}
+}
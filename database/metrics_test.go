@@ -0,0 +1,48 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRegisterMetricsCallbacks(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer sqlDB.Close()
+
+	dialector := postgres.New(postgres.Config{
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open gorm db: %v", err)
+	}
+
+	if err := registerMetricsCallbacks(db); err != nil {
+		t.Fatalf("failed to register metrics callbacks: %v", err)
+	}
+	if db.Callback().Query().Get("metrics:after_query") == nil {
+		t.Fatal("expected metrics query callback to be registered")
+	}
+
+	mock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	var value int
+	if err := db.Raw("SELECT 1").Scan(&value).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if value != 1 {
+		t.Errorf("expected 1, got %d", value)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
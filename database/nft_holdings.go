@@ -0,0 +1,181 @@
+package database
+
+import (
+	"errors"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+	"strings"
+	"time"
+)
+
+/*NFT 持有表：每个地址持有的 ERC-721 / ERC-1155（合约 + token id 一行）*/
+type NftHoldings struct {
+	GUID         uuid.UUID          `gorm:"primary_key" json:"guid"`
+	Address      common.Address     `gorm:"type:varchar;not null;serializer:bytes" json:"address"`
+	TokenAddress common.Address     `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	TokenId      string             `gorm:"type:varchar;not null" json:"token_id"`
+	TokenType    constant.TokenType `gorm:"type:varchar;not null" json:"token_type"`
+	/*ERC-721 为 0 或 1，ERC-1155 为持有数量*/
+	Amount    *big.Int `gorm:"type:numeric;not null;default:0;serializer:u256" json:"amount"`
+	Timestamp uint64   `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+/*NFT 持有扣减超出已记录数量，持有数据与链上不一致*/
+var ErrNftHoldingInsufficient = errors.New("nft holding insufficient")
+
+/*更新 NFT 持有用*/
+type NftTransfer struct {
+	FromAddress  common.Address           `json:"from_address"`
+	ToAddress    common.Address           `json:"to_address"`
+	TokenAddress common.Address           `json:"token_address"`
+	TokenId      string                   `json:"token_id"`
+	TokenType    constant.TokenType       `json:"token_type"`
+	Amount       *big.Int                 `json:"amount"`
+	TxType       constant.TransactionType `json:"tx_type"`
+}
+
+type NftHoldingsView interface {
+	QueryNftHoldingsByAddress(requestId string, address common.Address) ([]*NftHoldings, error)
+}
+
+type NftHoldingsDB interface {
+	NftHoldingsView
+
+	UpdateNftHoldings(requestId string, transfers []*NftTransfer) error
+	HandleFallBackNftHoldings(requestId string, transfers []*NftTransfer) error
+}
+
+type nftHoldingsDB struct {
+	gorm *gorm.DB
+}
+
+func NewNftHoldingsDB(db *gorm.DB) NftHoldingsDB {
+	return &nftHoldingsDB{gorm: db}
+}
+
+/*查询地址持有的 NFT（数量为 0 的不返回）*/
+func (db *nftHoldingsDB) QueryNftHoldingsByAddress(requestId string, address common.Address) ([]*NftHoldings, error) {
+	var holdings []*NftHoldings
+	err := db.gorm.Table("nft_holdings_"+requestId).
+		Where("address = ? AND amount > 0", strings.ToLower(address.String())).
+		Find(&holdings).Error
+	if err != nil {
+		return nil, fmt.Errorf("query nft holdings failed: %w", err)
+	}
+	return holdings, nil
+}
+
+/*
+按交易类型更新 NFT 持有：
+* 充值：to 地址（用户）增加
+* 提现：from 地址（热钱包）减少
+* 归集、热转冷、冷转热：from 减少，to 增加
+*/
+func (db *nftHoldingsDB) UpdateNftHoldings(requestId string, transfers []*NftTransfer) error {
+	return db.applyTransfers(requestId, transfers, false)
+}
+
+/*回滚：按相反方向更新 NFT 持有*/
+func (db *nftHoldingsDB) HandleFallBackNftHoldings(requestId string, transfers []*NftTransfer) error {
+	return db.applyTransfers(requestId, transfers, true)
+}
+
+func (db *nftHoldingsDB) applyTransfers(requestId string, transfers []*NftTransfer, reverse bool) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, transfer := range transfers {
+			log.Info("Processing nft holding update",
+				"txType", transfer.TxType,
+				"from", transfer.FromAddress,
+				"to", transfer.ToAddress,
+				"token", transfer.TokenAddress,
+				"tokenId", transfer.TokenId,
+				"amount", transfer.Amount,
+				"reverse", reverse)
+
+			var debit, credit bool
+			switch transfer.TxType {
+			case constant.TxTypeDeposit:
+				credit = true
+			case constant.TxTypeWithdraw:
+				debit = true
+			case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot:
+				debit, credit = true, true
+			default:
+				return fmt.Errorf("unsupported transaction type: %s", transfer.TxType)
+			}
+
+			fromDelta := new(big.Int).Neg(transfer.Amount)
+			toDelta := new(big.Int).Set(transfer.Amount)
+			if reverse {
+				fromDelta, toDelta = toDelta, fromDelta
+			}
+			if debit {
+				if err := db.addHolding(tx, requestId, transfer, transfer.FromAddress, fromDelta); err != nil {
+					return err
+				}
+			}
+			if credit {
+				if err := db.addHolding(tx, requestId, transfer, transfer.ToAddress, toDelta); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+/*
+变更某地址持有数量，无记录则创建；
+未记录过的持有跳过扣减，已记录的持有扣减超出数量返回 ErrNftHoldingInsufficient，整批不更新
+*/
+func (db *nftHoldingsDB) addHolding(tx *gorm.DB, requestId string, transfer *NftTransfer, address common.Address, delta *big.Int) error {
+	var holding NftHoldings
+	err := tx.Table("nft_holdings_"+requestId).
+		Where("address = ? AND token_address = ? AND token_id = ?",
+			strings.ToLower(address.String()),
+			strings.ToLower(transfer.TokenAddress.String()),
+			transfer.TokenId,
+		).
+		Take(&holding).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("query nft holding failed: %w", err)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if delta.Sign() <= 0 {
+			log.Warn("nft holding not found, skip debit", "address", address, "token", transfer.TokenAddress, "tokenId", transfer.TokenId)
+			return nil
+		}
+		holding = NftHoldings{
+			GUID:         uuid.New(),
+			Address:      address,
+			TokenAddress: transfer.TokenAddress,
+			TokenId:      transfer.TokenId,
+			TokenType:    transfer.TokenType,
+			Amount:       delta,
+			Timestamp:    uint64(time.Now().Unix()),
+		}
+		return tx.Table("nft_holdings_" + requestId).Create(&holding).Error
+	}
+
+	amount := new(big.Int).Add(holding.Amount, delta)
+	if amount.Sign() < 0 {
+		log.Error("nft holding decrement exceeds stored amount", "address", address, "token", transfer.TokenAddress, "tokenId", transfer.TokenId, "amount", holding.Amount, "delta", delta)
+		return fmt.Errorf("%w: address %s token %s id %s holds %s, delta %s", ErrNftHoldingInsufficient,
+			address, transfer.TokenAddress, transfer.TokenId, holding.Amount, delta)
+	}
+	return tx.Table("nft_holdings_"+requestId).
+		Where("guid = ?", holding.GUID).
+		Updates(map[string]interface{}{
+			"amount":    amount.String(),
+			"timestamp": uint64(time.Now().Unix()),
+		}).Error
+}
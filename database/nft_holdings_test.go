@@ -0,0 +1,156 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var nftHoldingColumns = []string{"guid", "address", "token_address", "token_id", "token_type", "amount", "timestamp"}
+
+func nftHoldingRow(guid uuid.UUID, address, tokenAddress common.Address, tokenId string, amount string) *sqlmock.Rows {
+	return sqlmock.NewRows(nftHoldingColumns).
+		AddRow(guid.String(), fmt.Sprintf("0x%x", address.Bytes()), fmt.Sprintf("0x%x", tokenAddress.Bytes()), tokenId, "ERC1155", amount, 1)
+}
+
+func TestQueryNftHoldingsByAddress(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	address := common.HexToAddress("0xAbC0000000000000000000000000000000000001")
+	tokenAddress := common.HexToAddress("0x00000000000000000000000000000000000000A1")
+	mock.ExpectQuery(`SELECT \* FROM "nft_holdings_biz" WHERE address = \$1 AND amount > 0`).
+		WithArgs("0xabc0000000000000000000000000000000000001").
+		WillReturnRows(nftHoldingRow(uuid.New(), address, tokenAddress, "7", "3"))
+
+	db := NewNftHoldingsDB(gormDB)
+	holdings, err := db.QueryNftHoldingsByAddress("biz", address)
+	require.NoError(t, err)
+	require.Len(t, holdings, 1)
+	assert.Equal(t, address, holdings[0].Address)
+	assert.Equal(t, "7", holdings[0].TokenId)
+	assert.Equal(t, "3", holdings[0].Amount.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*归集：用户地址减少，热钱包无记录时新建*/
+func TestUpdateNftHoldingsCollection(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	userAddress := common.HexToAddress("0x00000000000000000000000000000000000000C1")
+	hotAddress := common.HexToAddress("0x00000000000000000000000000000000000000D1")
+	tokenAddress := common.HexToAddress("0x00000000000000000000000000000000000000A1")
+	userGuid := uuid.New()
+	create := &argCapture{}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "nft_holdings_biz" WHERE address = \$1 AND token_address = \$2 AND token_id = \$3`).
+		WithArgs("0x00000000000000000000000000000000000000c1", "0x00000000000000000000000000000000000000a1", "7", 1).
+		WillReturnRows(nftHoldingRow(userGuid, userAddress, tokenAddress, "7", "5"))
+	mock.ExpectExec(`UPDATE "nft_holdings_biz" SET "amount"=\$1,"timestamp"=\$2 WHERE guid = \$3`).
+		WithArgs("3", sqlmock.AnyArg(), userGuid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "nft_holdings_biz" WHERE address = \$1 AND token_address = \$2 AND token_id = \$3`).
+		WithArgs("0x00000000000000000000000000000000000000d1", "0x00000000000000000000000000000000000000a1", "7", 1).
+		WillReturnRows(sqlmock.NewRows(nftHoldingColumns))
+	mock.ExpectQuery(`INSERT INTO "nft_holdings_biz" .* RETURNING "amount"`).
+		WithArgs(create.args(len(nftHoldingColumns))...).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("2"))
+	mock.ExpectCommit()
+
+	db := NewNftHoldingsDB(gormDB)
+	err := db.UpdateNftHoldings("biz", []*NftTransfer{{
+		FromAddress:  userAddress,
+		ToAddress:    hotAddress,
+		TokenAddress: tokenAddress,
+		TokenId:      "7",
+		TokenType:    constant.TokenTypeERC1155,
+		Amount:       big.NewInt(2),
+		TxType:       constant.TxTypeCollection,
+	}})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, "0x00000000000000000000000000000000000000d1", fmt.Sprint(create.values[1]))
+	assert.Equal(t, "7", fmt.Sprint(create.values[3]))
+	assert.Equal(t, "2", numericValue(t, create.values[6]).String())
+}
+
+/*充值回滚：to 地址减少，不低于 0*/
+func TestHandleFallBackNftHoldingsDeposit(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	userAddress := common.HexToAddress("0x00000000000000000000000000000000000000C1")
+	tokenAddress := common.HexToAddress("0x00000000000000000000000000000000000000A1")
+	guid := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "nft_holdings_biz" WHERE address = \$1 AND token_address = \$2 AND token_id = \$3`).
+		WithArgs("0x00000000000000000000000000000000000000c1", "0x00000000000000000000000000000000000000a1", "7", 1).
+		WillReturnRows(nftHoldingRow(guid, userAddress, tokenAddress, "7", "1"))
+	mock.ExpectExec(`UPDATE "nft_holdings_biz" SET "amount"=\$1,"timestamp"=\$2 WHERE guid = \$3`).
+		WithArgs("0", sqlmock.AnyArg(), guid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewNftHoldingsDB(gormDB)
+	err := db.HandleFallBackNftHoldings("biz", []*NftTransfer{{
+		ToAddress:    userAddress,
+		TokenAddress: tokenAddress,
+		TokenId:      "7",
+		TokenType:    constant.TokenTypeERC1155,
+		Amount:       big.NewInt(1),
+		TxType:       constant.TxTypeDeposit,
+	}})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*回滚扣减超出已记录数量：返回错误，事务回滚，不更新持有*/
+func TestHandleFallBackNftHoldingsInsufficient(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	userAddress := common.HexToAddress("0x00000000000000000000000000000000000000C1")
+	tokenAddress := common.HexToAddress("0x00000000000000000000000000000000000000A1")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "nft_holdings_biz" WHERE address = \$1 AND token_address = \$2 AND token_id = \$3`).
+		WithArgs("0x00000000000000000000000000000000000000c1", "0x00000000000000000000000000000000000000a1", "7", 1).
+		WillReturnRows(nftHoldingRow(uuid.New(), userAddress, tokenAddress, "7", "1"))
+	mock.ExpectRollback()
+
+	db := NewNftHoldingsDB(gormDB)
+	err := db.HandleFallBackNftHoldings("biz", []*NftTransfer{{
+		ToAddress:    userAddress,
+		TokenAddress: tokenAddress,
+		TokenId:      "7",
+		TokenType:    constant.TokenTypeERC1155,
+		Amount:       big.NewInt(2),
+		TxType:       constant.TxTypeDeposit,
+	}})
+	require.ErrorIs(t, err, ErrNftHoldingInsufficient)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
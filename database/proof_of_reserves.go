@@ -0,0 +1,66 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+)
+
+/*
+储备证明报告：命令行 proof-of-reserves 离线生成（按区块逐地址查询链上余额，耗时较长），
+rpc 接口只读取已生成的报告
+*/
+type ProofOfReserves struct {
+	GUID uuid.UUID `gorm:"primary_key" json:"guid"`
+	/*报告对应的余额快照区块*/
+	BlockNumber *big.Int `gorm:"type:numeric;not null;serializer:u256" json:"block_number"`
+	MerkleRoot  string   `gorm:"type:varchar;not null" json:"merkle_root"`
+	/*JSON 数据集*/
+	Report    string `gorm:"type:text;not null" json:"report"`
+	Timestamp uint64 `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type ProofOfReservesView interface {
+	QueryLatestProofOfReserves(requestId string, blockNumber *big.Int) (*ProofOfReserves, error)
+}
+
+type ProofOfReservesDB interface {
+	ProofOfReservesView
+
+	StoreProofOfReserves(requestId string, report *ProofOfReserves) error
+}
+
+type proofOfReservesDB struct {
+	gorm *gorm.DB
+}
+
+func NewProofOfReservesDB(db *gorm.DB) ProofOfReservesDB {
+	return &proofOfReservesDB{gorm: db}
+}
+
+/*存储储备证明报告*/
+func (db *proofOfReservesDB) StoreProofOfReserves(requestId string, report *ProofOfReserves) error {
+	if err := db.gorm.Table("proof_of_reserves_" + requestId).Create(report).Error; err != nil {
+		return fmt.Errorf("store proof of reserves failed: %w", err)
+	}
+	return nil
+}
+
+/*最近一次报告，blockNumber 不为空时取该区块及之前的最近一次；没有报告返回 nil*/
+func (db *proofOfReservesDB) QueryLatestProofOfReserves(requestId string, blockNumber *big.Int) (*ProofOfReserves, error) {
+	tx := db.gorm.Table("proof_of_reserves_" + requestId)
+	if blockNumber != nil {
+		tx = tx.Where("block_number <= ?", blockNumber.String())
+	}
+	var report ProofOfReserves
+	err := tx.Order("block_number DESC, timestamp DESC").Take(&report).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query proof of reserves failed: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var proofOfReservesColumns = []string{"guid", "block_number", "merkle_root", "report", "timestamp"}
+
+func TestStoreProofOfReserves(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	report := &ProofOfReserves{GUID: uuid.New(), BlockNumber: big.NewInt(990), MerkleRoot: "0xroot", Report: `{"block_number":"990"}`, Timestamp: 1}
+	capture := &argCapture{}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "proof_of_reserves_biz"`).
+		WithArgs(capture.args(len(proofOfReservesColumns))...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewProofOfReservesDB(gormDB)
+	require.NoError(t, db.StoreProofOfReserves("biz", report))
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "990", numericValue(t, capture.values[1]).String())
+	assert.Equal(t, "0xroot", capture.values[2])
+}
+
+/*指定区块时取报告区块不晚于该区块的最近一次*/
+func TestQueryLatestProofOfReservesAtBlock(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "proof_of_reserves_biz" WHERE block_number <= \$1 ORDER BY block_number DESC, timestamp DESC LIMIT \$2`).
+		WithArgs("1000", 1).
+		WillReturnRows(sqlmock.NewRows(proofOfReservesColumns).
+			AddRow(uuid.New().String(), "990", "0xroot", `{"block_number":"990"}`, 1))
+
+	db := NewProofOfReservesDB(gormDB)
+	report, err := db.QueryLatestProofOfReserves("biz", big.NewInt(1000))
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, "990", report.BlockNumber.String())
+	assert.Equal(t, "0xroot", report.MerkleRoot)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*没有报告返回 nil*/
+func TestQueryLatestProofOfReservesNotFound(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "proof_of_reserves_biz" ORDER BY block_number DESC, timestamp DESC LIMIT \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(proofOfReservesColumns))
+
+	db := NewProofOfReservesDB(gormDB)
+	report, err := db.QueryLatestProofOfReserves("biz", nil)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*gRPC 接口限流配置（令牌桶）：business_uid、method 为 * 表示所有项目方、所有接口*/
+type RateLimits struct {
+	GUID        uuid.UUID `gorm:"primary_key" json:"guid"`
+	BusinessUid string    `gorm:"type:varchar;not null" json:"business_uid"`
+	/*proto 中的方法名，如 buildUnSignTransaction*/
+	Method string `gorm:"type:varchar;not null" json:"method"`
+	/*每秒补充的令牌数*/
+	Rate float64 `gorm:"type:double precision;not null" json:"rate"`
+	/*桶容量，即允许的突发请求数*/
+	Burst     int    `gorm:"type:integer;not null" json:"burst"`
+	Timestamp uint64 `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type RateLimitsView interface {
+	QueryRateLimits() ([]*RateLimits, error)
+}
+
+type RateLimitsDB interface {
+	RateLimitsView
+
+	StoreRateLimit(rateLimit *RateLimits) error
+}
+
+type rateLimitsDB struct {
+	gorm *gorm.DB
+}
+
+func NewRateLimitsDB(db *gorm.DB) RateLimitsDB {
+	return &rateLimitsDB{gorm: db}
+}
+
+/*查询所有限流配置*/
+func (db *rateLimitsDB) QueryRateLimits() ([]*RateLimits, error) {
+	var rateLimits []*RateLimits
+	if err := db.gorm.Table("rate_limits").Find(&rateLimits).Error; err != nil {
+		return nil, fmt.Errorf("query rate limits failed: %w", err)
+	}
+	return rateLimits, nil
+}
+
+/*新增或更新项目方某接口的限流配置*/
+func (db *rateLimitsDB) StoreRateLimit(rateLimit *RateLimits) error {
+	return db.gorm.Table("rate_limits").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "business_uid"}, {Name: "method"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "burst", "timestamp"}),
+	}).Create(rateLimit).Error
+}
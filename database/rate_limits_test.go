@@ -0,0 +1,54 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRateLimits(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "rate_limits"`).
+		WillReturnRows(sqlmock.NewRows([]string{"guid", "business_uid", "method", "rate", "burst", "timestamp"}).
+			AddRow(uuid.New().String(), "*", "*", 50.0, 100, 1).
+			AddRow(uuid.New().String(), "biz", "buildUnSignTransaction", 0.5, 2, 1))
+
+	db := NewRateLimitsDB(gormDB)
+	rateLimits, err := db.QueryRateLimits()
+	require.NoError(t, err)
+	require.Len(t, rateLimits, 2)
+	assert.Equal(t, "*", rateLimits[0].Method)
+	assert.Equal(t, 50.0, rateLimits[0].Rate)
+	assert.Equal(t, "buildUnSignTransaction", rateLimits[1].Method)
+	assert.Equal(t, 0.5, rateLimits[1].Rate)
+	assert.Equal(t, 2, rateLimits[1].Burst)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*同一项目方、接口重复设置时覆盖速率与桶容量*/
+func TestStoreRateLimitUpsert(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	rateLimit := &RateLimits{GUID: uuid.New(), BusinessUid: "biz", Method: "buildUnSignTransaction", Rate: 0.5, Burst: 2, Timestamp: 1}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "rate_limits" .* ON CONFLICT \("business_uid","method"\) DO UPDATE SET "rate"="excluded"\."rate","burst"="excluded"\."burst","timestamp"="excluded"\."timestamp"`).
+		WithArgs(rateLimit.GUID, "biz", "buildUnSignTransaction", 0.5, 2, uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewRateLimitsDB(gormDB)
+	require.NoError(t, db.StoreRateLimit(rateLimit))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+)
+
+/*对账差异表：每轮对账中库内余额与链上余额不一致的记录*/
+type Reconciliations struct {
+	GUID uuid.UUID `gorm:"primary_key" json:"guid"`
+	/*对账轮次，同一轮的差异 round id 相同*/
+	RoundId      string               `gorm:"type:varchar;not null" json:"round_id"`
+	Address      common.Address       `gorm:"type:varchar;not null;serializer:bytes" json:"address"`
+	AddressType  constant.AddressType `gorm:"type:varchar;not null" json:"address_type"`
+	TokenAddress common.Address       `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	/*库内总余额（balance + lock_balance）*/
+	DbBalance    *big.Int `gorm:"type:numeric;not null;serializer:u256" json:"db_balance"`
+	ChainBalance *big.Int `gorm:"type:numeric;not null;serializer:u256" json:"chain_balance"`
+	/*链上 - 库内，可为负*/
+	Difference  string   `gorm:"type:varchar;not null" json:"difference"`
+	BlockNumber *big.Int `gorm:"type:numeric;not null;serializer:u256" json:"block_number"`
+	Timestamp   uint64   `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type ReconciliationsView interface {
+	QueryReconciliationsByRound(requestId string, roundId string) ([]*Reconciliations, error)
+}
+
+type ReconciliationsDB interface {
+	ReconciliationsView
+
+	StoreReconciliations(requestId string, reconciliations []*Reconciliations) error
+}
+
+type reconciliationsDB struct {
+	gorm *gorm.DB
+}
+
+func NewReconciliationsDB(db *gorm.DB) ReconciliationsDB {
+	return &reconciliationsDB{gorm: db}
+}
+
+/*存储对账差异*/
+func (db *reconciliationsDB) StoreReconciliations(requestId string, reconciliations []*Reconciliations) error {
+	if len(reconciliations) == 0 {
+		return nil
+	}
+	return db.gorm.Table("reconciliations_"+requestId).CreateInBatches(reconciliations, len(reconciliations)).Error
+}
+
+/*查询某一轮对账差异*/
+func (db *reconciliationsDB) QueryReconciliationsByRound(requestId string, roundId string) ([]*Reconciliations, error) {
+	var reconciliations []*Reconciliations
+	err := db.gorm.Table("reconciliations_"+requestId).
+		Where("round_id = ?", roundId).
+		Find(&reconciliations).Error
+	if err != nil {
+		return nil, fmt.Errorf("query reconciliations failed: %w", err)
+	}
+	return reconciliations, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*reconciliations 表 10 列*/
+const reconciliationColumns = 10
+
+func TestStoreReconciliations(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	address := common.HexToAddress("0x00000000000000000000000000000000000000C1")
+	reconciliations := []*Reconciliations{
+		{
+			GUID:         uuid.New(),
+			RoundId:      "round-1",
+			Address:      address,
+			AddressType:  constant.AddressTypeUser,
+			TokenAddress: common.Address{},
+			DbBalance:    big.NewInt(100),
+			ChainBalance: big.NewInt(90),
+			Difference:   "-10",
+			BlockNumber:  big.NewInt(1000),
+			Timestamp:    1,
+		},
+		{
+			GUID:         uuid.New(),
+			RoundId:      "round-1",
+			Address:      address,
+			AddressType:  constant.AddressTypeUser,
+			TokenAddress: common.HexToAddress("0x00000000000000000000000000000000000000A1"),
+			DbBalance:    big.NewInt(0),
+			ChainBalance: big.NewInt(5),
+			Difference:   "5",
+			BlockNumber:  big.NewInt(1000),
+			Timestamp:    1,
+		},
+	}
+
+	/*同一轮差异一条语句批量写入*/
+	capture := &argCapture{}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "reconciliations_biz" .* VALUES \(.*\),\(.*\)`).
+		WithArgs(capture.args(2 * reconciliationColumns)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewReconciliationsDB(gormDB)
+	require.NoError(t, db.StoreReconciliations("biz", reconciliations))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, "round-1", fmt.Sprint(capture.values[1]))
+	assert.Equal(t, "0x00000000000000000000000000000000000000c1", fmt.Sprint(capture.values[2]))
+	assert.Equal(t, "-10", fmt.Sprint(capture.values[7]))
+	assert.Equal(t, "5", fmt.Sprint(capture.values[reconciliationColumns+7]))
+}
+
+/*无差异时不写库*/
+func TestStoreReconciliationsEmpty(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	db := NewReconciliationsDB(gormDB)
+	require.NoError(t, db.StoreReconciliations("biz", nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryReconciliationsByRound(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectQuery(`SELECT \* FROM "reconciliations_biz" WHERE round_id = \$1`).
+		WithArgs("round-1").
+		WillReturnRows(sqlmock.NewRows([]string{"guid", "round_id", "address", "address_type", "token_address", "db_balance", "chain_balance", "difference", "block_number", "timestamp"}).
+			AddRow(uuid.New().String(), "round-1", "0x00000000000000000000000000000000000000c1", "user", "0x0000000000000000000000000000000000000000", "100", "90", "-10", "1000", 1))
+
+	db := NewReconciliationsDB(gormDB)
+	reconciliations, err := db.QueryReconciliationsByRound("biz", "round-1")
+	require.NoError(t, err)
+	require.Len(t, reconciliations, 1)
+	assert.Equal(t, common.HexToAddress("0x00000000000000000000000000000000000000C1"), reconciliations[0].Address)
+	assert.Equal(t, "100", reconciliations[0].DbBalance.String())
+	assert.Equal(t, "90", reconciliations[0].ChainBalance.String())
+	assert.Equal(t, "-10", reconciliations[0].Difference)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"exchange-wallet-service/common/clock"
+	"exchange-wallet-service/metrics"
+	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
+	"sync/atomic"
+	"time"
+)
+
+/*从库复制延迟检查间隔*/
+const replicaCheckInterval = 5 * time.Second
+
+/*
+从库复制延迟（秒）：已回放到接收位置视为无延迟（主库空闲时回放时间戳不再前进），
+否则取距最后回放事务的时间；非从库（未处于恢复模式）为 0
+*/
+const replicaLagQuery = `SELECT CASE
+    WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+    ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+/*从库：定时检查复制延迟，延迟超过上限或检查失败时不可用，只读查询回退主库*/
+type replica struct {
+	gorm      *gorm.DB
+	maxLag    time.Duration
+	available atomic.Bool
+	worker    *clock.LoopFn
+}
+
+/*新建从库并立即检查一次延迟，之后定时检查*/
+func newReplica(gormDb *gorm.DB, maxLag time.Duration) *replica {
+	r := &replica{gorm: gormDb, maxLag: maxLag}
+	r.check(context.Background())
+	r.worker = clock.NewLoopFn(clock.SystemClock, r.check, nil, replicaCheckInterval)
+	return r
+}
+
+func (r *replica) check(ctx context.Context) {
+	var seconds float64
+	err := r.gorm.WithContext(ctx).Raw(replicaLagQuery).Scan(&seconds).Error
+	lag := time.Duration(seconds * float64(time.Second))
+	available := err == nil && lag <= r.maxLag
+	if available != r.available.Load() {
+		if available {
+			log.Info("slave database available, route reads to it", "lag", lag)
+		} else {
+			log.Warn("slave database unavailable, route reads to master", "lag", lag, "maxLag", r.maxLag, "err", err)
+		}
+	}
+	r.available.Store(available)
+	metrics.SetReplicaLag(lag, available)
+}
+
+func (r *replica) close() error {
+	if r == nil {
+		return nil
+	}
+	if err := r.worker.Close(); err != nil {
+		return err
+	}
+	sql, err := r.gorm.DB()
+	if err != nil {
+		return err
+	}
+	return sql.Close()
+}
+
+/*
+只读查询（余额、历史、对账等）使用：从库可用时返回从库连接（沿用当前上下文），否则返回主库；
+从库数据可能落后主库，先读后写的路径（如通知后更新状态）与事务内不要使用
+*/
+func (db *DB) Replica() *DB {
+	if db.replica == nil || !db.replica.available.Load() {
+		return db
+	}
+	return newDB(db.replica.gorm.WithContext(db.gorm.Statement.Context))
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+func TestReplicaRouting(t *testing.T) {
+	masterGorm, _ := setupMockDB(t)
+	replicaGorm, replicaMock := setupMockDB(t)
+	defer func() {
+		for _, gormDB := range []*gorm.DB{masterGorm, replicaGorm} {
+			db, _ := gormDB.DB()
+			db.Close()
+		}
+	}()
+
+	db := newDB(masterGorm)
+	if db.Replica() != db {
+		t.Fatal("reads should stay on master without replica")
+	}
+
+	db.replica = &replica{gorm: replicaGorm, maxLag: 5 * time.Second}
+	replicaMock.ExpectQuery("pg_is_in_recovery").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(1.5))
+	db.replica.check(db.gorm.Statement.Context)
+	if got := db.Replica(); got == db || got.gorm.ConnPool != replicaGorm.ConnPool {
+		t.Fatal("reads should go to replica when lag is below max lag")
+	}
+	if got := db.WithContext(t.Context()).Replica(); got.gorm.ConnPool != replicaGorm.ConnPool {
+		t.Fatal("context-bound db should keep the replica")
+	}
+
+	replicaMock.ExpectQuery("pg_is_in_recovery").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(30))
+	db.replica.check(db.gorm.Statement.Context)
+	if db.Replica() != db {
+		t.Fatal("reads should fall back to master when replica lags")
+	}
+
+	replicaMock.ExpectQuery("pg_is_in_recovery").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(0))
+	db.replica.check(db.gorm.Statement.Context)
+	replicaMock.ExpectQuery("pg_is_in_recovery").WillReturnError(errors.New("connection refused"))
+	db.replica.check(db.gorm.Statement.Context)
+	if db.Replica() != db {
+		t.Fatal("reads should fall back to master when lag check fails")
+	}
+	if err := replicaMock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,10 +1,13 @@
 package database
 
 import (
+	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"math/big"
+	"strings"
 )
 
 type Tokens struct {
@@ -14,11 +17,14 @@ type Tokens struct {
 	TokenName     string         `json:"tokens_name"`
 	CollectAmount *big.Int       `gorm:"serializer:u256" json:"collect_amount"`
 	ColdAmount    *big.Int       `gorm:"serializer:u256" json:"cold_amount"`
-	Timestamp     uint64         `json:"timestamp"`
+	/*最小充值金额，低于此金额的充值记为粉尘*/
+	MinDepositAmount *big.Int `gorm:"serializer:u256" json:"min_deposit_amount"`
+	Timestamp        uint64   `json:"timestamp"`
 }
 
 type TokensView interface {
-	/*todo*/
+	QueryTokensList(requestId string) ([]*Tokens, error)
+	QueryTokensByAddress(requestId string, tokenAddress common.Address) (*Tokens, error)
 }
 
 type TokensDB interface {
@@ -38,6 +44,30 @@ func (db *tokensDB) StoreTokens(requestId string, tokenList []Tokens) error {
 	return result.Error
 }
 
+/*查询项目方支持的代币列表（白名单）*/
+func (db *tokensDB) QueryTokensList(requestId string) ([]*Tokens, error) {
+	var tokenList []*Tokens
+	if err := db.gorm.Table("tokens_" + requestId).Find(&tokenList).Error; err != nil {
+		return nil, fmt.Errorf("query tokens failed: %w", err)
+	}
+	return tokenList, nil
+}
+
+/*根据合约地址查询代币，不存在返回 nil*/
+func (db *tokensDB) QueryTokensByAddress(requestId string, tokenAddress common.Address) (*Tokens, error) {
+	var token Tokens
+	err := db.gorm.Table("tokens_"+requestId).
+		Where("token_address = ?", strings.ToLower(tokenAddress.String())).
+		Take(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query token failed: %w", err)
+	}
+	return &token, nil
+}
+
 func NewTokensDB(db *gorm.DB) TokensDB {
 	return &tokensDB{gorm: db}
 }
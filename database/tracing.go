@@ -0,0 +1,78 @@
+package database
+
+import (
+	"exchange-wallet-service/tracing"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracingSpanKey = "tracing:span"
+
+/*
+各类 gorm 回调在执行前后开启、结束 span，
+只在上下文已有链路时（经 WithContext 绑定请求或任务上下文）记录，避免无归属的零散 span
+*/
+func registerTracingCallbacks(db *gorm.DB) error {
+	callback := db.Callback()
+	registrations := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+	for _, r := range registrations {
+		if err := r.before("tracing:before_"+r.operation, tracingBefore(r.operation)); err != nil {
+			return errors.Wrap(err, "failed to register tracing callback")
+		}
+		if err := r.after("tracing:after_"+r.operation, tracingAfter); err != nil {
+			return errors.Wrap(err, "failed to register tracing callback")
+		}
+	}
+	return nil
+}
+
+func tracingBefore(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+		ctx, span := tracing.Start(ctx, "gorm."+operation,
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+			attribute.String("db.sql.table", db.Statement.Table),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+func tracingAfter(db *gorm.DB) {
+	value, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	/*未查到记录不算错误*/
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
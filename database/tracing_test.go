@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRegisterTracingCallbacks(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer sqlDB.Close()
+
+	dialector := postgres.New(postgres.Config{
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open gorm db: %v", err)
+	}
+	if err := registerTracingCallbacks(db); err != nil {
+		t.Fatalf("failed to register tracing callbacks: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	var value int
+
+	/*无链路上下文不记录*/
+	if err := db.Raw("SELECT 1").Scan(&value).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(recorder.Ended()) != 0 {
+		t.Fatalf("expected no spans without parent, got %d", len(recorder.Ended()))
+	}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	if err := db.WithContext(ctx).Raw("SELECT 1").Scan(&value).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "gorm.row" {
+		t.Errorf("expected gorm.row span, got %s", spans[0].Name())
+	}
+	if spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected gorm span to be a child of the parent span")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
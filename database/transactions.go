@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"math/big"
+	"strings"
 )
 
 /*交易流水表*/
@@ -18,6 +19,7 @@ type Transactions struct {
 	Hash         common.Hash              `gorm:"column:hash;serializer:bytes"  db:"hash" json:"hash"`
 	FromAddress  common.Address           `json:"from_address" gorm:"serializer:bytes"`
 	ToAddress    common.Address           `json:"to_address" gorm:"serializer:bytes"`
+	TokenType    constant.TokenType       `json:"token_type" gorm:"column:token_type"`
 	TokenAddress common.Address           `json:"token_address" gorm:"serializer:bytes"`
 	TokenId      string                   `json:"token_id" gorm:"column:token_id"`
 	TokenMeta    string                   `json:"token_meta" gorm:"column:token_meta"`
@@ -30,6 +32,7 @@ type Transactions struct {
 
 type TransactionsView interface {
 	QueryFallBackTransactions(requestId string, startBlock, EndBlock *big.Int) ([]*Transactions, error)
+	QueryRecentCounterparties(requestId string, limit int) ([]common.Address, error)
 	// todo
 }
 
@@ -38,6 +41,8 @@ type TransactionsDB interface {
 
 	StoreTransactions(string, []*Transactions, uint64) error
 	HandleFallBackTransactions(requestId string, startBlock, EndBlock *big.Int) error
+	UpdateDepositFlowStatus(requestId string, deposit *Deposits, status constant.TxStatus) error
+	UpdateHoldDepositFlowStatus(requestId string, deposit *Deposits, status constant.TxStatus) (bool, error)
 	/*todo*/
 }
 
@@ -90,3 +95,50 @@ func (db *transactionsDB) HandleFallBackTransactions(requestId string, startBloc
 	}
 	return nil
 }
+
+/*更新充值对应的流水状态（风险挂起的充值冲正后不再参与回滚）*/
+func (db *transactionsDB) UpdateDepositFlowStatus(requestId string, deposit *Deposits, status constant.TxStatus) error {
+	return db.gorm.Table("transactions_"+requestId).
+		Where("hash = ? AND tx_type = ? AND to_address = ? AND token_address = ? AND token_id = ?",
+			deposit.TxHash.String(), constant.TxTypeDeposit,
+			strings.ToLower(deposit.ToAddress.String()), strings.ToLower(deposit.TokenAddress.String()), deposit.TokenId).
+		Update("status", status).Error
+}
+
+/*更新已挂起（hold）的充值流水状态，返回是否存在挂起的流水；扫链时即被筛查挂起的充值没有流水*/
+func (db *transactionsDB) UpdateHoldDepositFlowStatus(requestId string, deposit *Deposits, status constant.TxStatus) (bool, error) {
+	result := db.gorm.Table("transactions_"+requestId).
+		Where("hash = ? AND tx_type = ? AND to_address = ? AND token_address = ? AND token_id = ? AND status = ?",
+			deposit.TxHash.String(), constant.TxTypeDeposit,
+			strings.ToLower(deposit.ToAddress.String()), strings.ToLower(deposit.TokenAddress.String()), deposit.TokenId,
+			constant.TxStatusHold).
+		Update("status", status)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+/*近期交易对手地址（from/to 去重），供相似地址检测*/
+func (db *transactionsDB) QueryRecentCounterparties(requestId string, limit int) ([]common.Address, error) {
+	var recentTransactions []*Transactions
+	err := db.gorm.Table("transactions_"+requestId).
+		Select("from_address", "to_address").
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&recentTransactions).Error
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[common.Address]bool)
+	var counterparties []common.Address
+	for _, transaction := range recentTransactions {
+		for _, address := range []common.Address{transaction.FromAddress, transaction.ToAddress} {
+			if !seen[address] {
+				seen[address] = true
+				counterparties = append(counterparties, address)
+			}
+		}
+	}
+	return counterparties, nil
+}
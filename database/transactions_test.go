@@ -3,7 +3,12 @@ package database
 import (
 	"testing"
 
+	"exchange-wallet-service/database/constant"
+
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -32,3 +37,35 @@ func TestNewTransactionsDB(t *testing.T) {
 		t.Fatal("NewTransactionsDB returned nil")
 	}
 }
+
+/*评分挂起的充值有 hold 流水，扫链时即挂起的没有*/
+func TestUpdateHoldDepositFlowStatus(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	deposit := &Deposits{
+		TxHash:       common.HexToHash("0x01"),
+		ToAddress:    common.HexToAddress("0x000000000000000000000000000000000000000A"),
+		TokenAddress: common.HexToAddress("0x000000000000000000000000000000000000000B"),
+	}
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "transactions_biz" SET "status"=\$1 WHERE hash = \$2 AND tx_type = \$3 AND to_address = \$4 AND token_address = \$5 AND token_id = \$6 AND status = \$7`).
+			WithArgs(constant.TxStatusSuccess, deposit.TxHash.String(), constant.TxTypeDeposit,
+				"0x000000000000000000000000000000000000000a", "0x000000000000000000000000000000000000000b", "", constant.TxStatusHold).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	db := NewTransactionsDB(gormDB)
+	held, err := db.UpdateHoldDepositFlowStatus("biz", deposit, constant.TxStatusSuccess)
+	require.NoError(t, err)
+	assert.True(t, held)
+	held, err = db.UpdateHoldDepositFlowStatus("biz", deposit, constant.TxStatusSuccess)
+	require.NoError(t, err)
+	assert.False(t, held)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
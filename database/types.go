@@ -14,4 +14,6 @@ type TokenBalance struct {
 	TokenAddress common.Address           `json:"to_ken_address"`
 	Balance      *big.Int                 `json:"balance"`
 	TxType       constant.TransactionType `json:"tx_type"`
+	/*来源交易哈希，记账引用*/
+	TxHash common.Hash `json:"tx_hash"`
 }
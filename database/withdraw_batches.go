@@ -0,0 +1,175 @@
+package database
+
+import (
+	"errors"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+)
+
+/*
+批量提现批次：同一代币的多笔提现通过 disperse 合约合并为一笔交易，
+每笔出款仍为一条提现记录（batch_id 关联），批次交易上链后按交易哈希统一确认
+*/
+type WithdrawBatches struct {
+	GUID            uuid.UUID          `gorm:"primary_key" json:"guid"`
+	Timestamp       uint64             `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+	Status          constant.TxStatus  `gorm:"type:varchar;not null" json:"status"`
+	TxHash          common.Hash        `gorm:"column:hash;type:varchar;not null;serializer:bytes" json:"hash"`
+	FromAddress     common.Address     `gorm:"type:varchar;not null;serializer:bytes" json:"from_address"`
+	DisperseAddress common.Address     `gorm:"type:varchar;not null;serializer:bytes" json:"disperse_address"`
+	TokenType       constant.TokenType `gorm:"type:varchar;not null" json:"token_type"`
+	TokenAddress    common.Address     `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	TotalAmount     *big.Int           `gorm:"type:numeric;not null;serializer:u256" json:"total_amount"`
+	PayoutCount     int                `gorm:"not null" json:"payout_count"`
+
+	GasLimit             uint64 `gorm:"not null" json:"gas_limit"`
+	MaxFeePerGas         string `gorm:"type:varchar;not null" json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string `gorm:"type:varchar;not null" json:"max_priority_fee_per_gas"`
+
+	TxSignHex string `gorm:"type:varchar;not null" json:"tx_sign_hex"`
+}
+
+type WithdrawBatchesView interface {
+	QueryBatchById(requestId string, guid string) (*WithdrawBatches, error)
+	QueryBatchWithdraws(requestId string, batchId string) ([]*Withdraws, error)
+	UnSendBatchList(requestId string) ([]*WithdrawBatches, error)
+}
+
+type WithdrawBatchesDB interface {
+	WithdrawBatchesView
+
+	StoreBatch(requestId string, batch *WithdrawBatches, withdraws []*Withdraws) error
+	UpdateBatchById(requestId string, guid string, signedTx string, status constant.TxStatus) error
+	UpdateBatchBroadcasted(requestId string, batch *WithdrawBatches) error
+	UpdateBatchStatusByTxHash(requestId string, status constant.TxStatus, txHashes []common.Hash) error
+}
+
+type withdrawBatchesDB struct {
+	gorm *gorm.DB
+}
+
+func NewWithdrawBatchesDB(db *gorm.DB) WithdrawBatchesDB {
+	return &withdrawBatchesDB{gorm: db}
+}
+
+/*批次与批次内提现一并存储*/
+func (db *withdrawBatchesDB) StoreBatch(requestId string, batch *WithdrawBatches, withdraws []*Withdraws) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("withdraw_batches_" + requestId).Create(batch).Error; err != nil {
+			return fmt.Errorf("store withdraw batch failed: %w", err)
+		}
+		for _, withdraw := range withdraws {
+			withdraw.BatchId = batch.GUID.String()
+		}
+		if err := tx.Table("withdraws_" + requestId).Create(&withdraws).Error; err != nil {
+			return fmt.Errorf("store batch withdraws failed: %w", err)
+		}
+		return nil
+	})
+}
+
+/*根据 id 查批次*/
+func (db *withdrawBatchesDB) QueryBatchById(requestId string, guid string) (*WithdrawBatches, error) {
+	var batch WithdrawBatches
+	result := db.gorm.Table("withdraw_batches_"+requestId).
+		Where("guid = ?", guid).
+		Take(&batch)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &batch, nil
+}
+
+/*批次内提现，按 guid 排序（与 disperse 调用参数顺序一致）*/
+func (db *withdrawBatchesDB) QueryBatchWithdraws(requestId string, batchId string) ([]*Withdraws, error) {
+	var withdraws []*Withdraws
+	err := db.gorm.Table("withdraws_"+requestId).
+		Where("batch_id = ?", batchId).
+		Order(`guid COLLATE "C" ASC`).
+		Find(&withdraws).Error
+	if err != nil {
+		return nil, fmt.Errorf("query batch withdraws failed: %w", err)
+	}
+	return withdraws, nil
+}
+
+/*已签名未发送的批次*/
+func (db *withdrawBatchesDB) UnSendBatchList(requestId string) ([]*WithdrawBatches, error) {
+	var batches []*WithdrawBatches
+	err := db.gorm.Table("withdraw_batches_"+requestId).
+		Where("status = ?", constant.TxStatusSigned).
+		Find(&batches).Error
+	if err != nil {
+		return nil, fmt.Errorf("query unsend withdraw batches failed: %w", err)
+	}
+	return batches, nil
+}
+
+/*更新批次签名交易与状态，批次内提现同步状态*/
+func (db *withdrawBatchesDB) UpdateBatchById(requestId string, guid string, signedTx string, status constant.TxStatus) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("withdraw_batches_"+requestId).
+			Where("guid = ?", guid).
+			Updates(map[string]interface{}{
+				"tx_sign_hex": signedTx,
+				"status":      status,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("update withdraw batch failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("withdraw batch not found for GUID: %s", guid)
+		}
+		return tx.Table("withdraws_"+requestId).
+			Where("batch_id = ?", guid).
+			Update("status", status).Error
+	})
+}
+
+/*批次已广播：批次与批次内提现记录交易哈希与状态*/
+func (db *withdrawBatchesDB) UpdateBatchBroadcasted(requestId string, batch *WithdrawBatches) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("withdraw_batches_"+requestId).
+			Where("guid = ?", batch.GUID.String()).
+			Updates(map[string]interface{}{
+				"hash":   batch.TxHash.String(),
+				"status": constant.TxStatusBroadcasted,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("update withdraw batch failed: %w", err)
+		}
+		result := tx.Table("withdraws_"+requestId).
+			Where("batch_id = ?", batch.GUID.String()).
+			Updates(map[string]interface{}{
+				"hash":   batch.TxHash.String(),
+				"status": constant.TxStatusBroadcasted,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("update batch withdraws failed: %w", result.Error)
+		}
+		log.Info("withdraw batch broadcasted", "requestId", requestId, "batchId", batch.GUID, "txHash", batch.TxHash, "withdraws", result.RowsAffected)
+		return nil
+	})
+}
+
+/*按交易哈希更新批次状态（批次内提现由提现表按同一哈希更新）*/
+func (db *withdrawBatchesDB) UpdateBatchStatusByTxHash(requestId string, status constant.TxStatus, txHashes []common.Hash) error {
+	if len(txHashes) == 0 {
+		return nil
+	}
+	hashList := make([]string, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		hashList = append(hashList, txHash.String())
+	}
+	return db.gorm.Table("withdraw_batches_"+requestId).
+		Where("hash IN ?", hashList).
+		Update("status", status).Error
+}
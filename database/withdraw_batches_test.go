@@ -0,0 +1,155 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*批次与批次内提现同一事务写入，提现关联批次 id*/
+func TestStoreBatch(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	batch := &WithdrawBatches{
+		GUID:        uuid.New(),
+		Timestamp:   1,
+		Status:      constant.TxStatusCreateUnsigned,
+		TokenType:   constant.TokenTypeETH,
+		TotalAmount: big.NewInt(300),
+		PayoutCount: 2,
+	}
+	withdraws := []*Withdraws{
+		{GUID: uuid.New(), Amount: big.NewInt(100), BlockNumber: big.NewInt(1)},
+		{GUID: uuid.New(), Amount: big.NewInt(200), BlockNumber: big.NewInt(1)},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "withdraw_batches_biz"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "withdraws_biz" .* VALUES \(.*\),\(.*\)`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewWithdrawBatchesDB(gormDB)
+	require.NoError(t, db.StoreBatch("biz", batch, withdraws))
+	require.NoError(t, mock.ExpectationsWereMet())
+	for _, withdraw := range withdraws {
+		assert.Equal(t, batch.GUID.String(), withdraw.BatchId)
+	}
+}
+
+/*批次签名后批次内提现同步状态*/
+func TestUpdateBatchById(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	guid := uuid.New().String()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "withdraw_batches_biz" SET "status"=\$1,"tx_sign_hex"=\$2 WHERE guid = \$3`).
+		WithArgs(constant.TxStatusSigned, "0xsigned", guid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "withdraws_biz" SET "status"=\$1 WHERE batch_id = \$2`).
+		WithArgs(constant.TxStatusSigned, guid).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewWithdrawBatchesDB(gormDB)
+	require.NoError(t, db.UpdateBatchById("biz", guid, "0xsigned", constant.TxStatusSigned))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*批次不存在时报错并回滚，不更新提现*/
+func TestUpdateBatchByIdNotFound(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	guid := uuid.New().String()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "withdraw_batches_biz"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	db := NewWithdrawBatchesDB(gormDB)
+	err := db.UpdateBatchById("biz", guid, "0xsigned", constant.TxStatusSigned)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*批次已广播：批次与批次内提现写入同一交易哈希*/
+func TestUpdateBatchBroadcasted(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	batch := &WithdrawBatches{GUID: uuid.New(), TxHash: common.HexToHash("0x01")}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "withdraw_batches_biz" SET "hash"=\$1,"status"=\$2 WHERE guid = \$3`).
+		WithArgs(batch.TxHash.String(), constant.TxStatusBroadcasted, batch.GUID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "withdraws_biz" SET "hash"=\$1,"status"=\$2 WHERE batch_id = \$3`).
+		WithArgs(batch.TxHash.String(), constant.TxStatusBroadcasted, batch.GUID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	db := NewWithdrawBatchesDB(gormDB)
+	require.NoError(t, db.UpdateBatchBroadcasted("biz", batch))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*批次内提现按 guid 排序，与 disperse 调用参数顺序一致*/
+func TestQueryBatchWithdraws(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	batchId := uuid.New().String()
+	mock.ExpectQuery(`SELECT \* FROM "withdraws_biz" WHERE batch_id = \$1 ORDER BY guid COLLATE "C" ASC`).
+		WithArgs(batchId).
+		WillReturnRows(sqlmock.NewRows([]string{"guid", "batch_id", "amount"}).
+			AddRow(uuid.New().String(), batchId, "100").
+			AddRow(uuid.New().String(), batchId, "200"))
+
+	db := NewWithdrawBatchesDB(gormDB)
+	withdraws, err := db.QueryBatchWithdraws("biz", batchId)
+	require.NoError(t, err)
+	require.Len(t, withdraws, 2)
+	assert.Equal(t, "200", withdraws[1].Amount.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBatchStatusByTxHash(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	txHash := common.HexToHash("0x01")
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "withdraw_batches_biz" SET "status"=\$1 WHERE hash IN \(\$2\)`).
+		WithArgs(constant.TxStatusSuccess, txHash.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewWithdrawBatchesDB(gormDB)
+	require.NoError(t, db.UpdateBatchStatusByTxHash("biz", constant.TxStatusSuccess, []common.Hash{txHash}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -41,6 +41,16 @@ type Withdraws struct {
 
 	// 交易签名
 	TxSignHex string `json:"tx_sign_hex" gorm:"column:tx_sign_hex"`
+
+	// 地址筛查命中原因
+	RiskReason string `json:"risk_reason" gorm:"column:risk_reason"`
+
+	// 风险评分与处置动作
+	RiskScore  int                 `json:"risk_score" gorm:"column:risk_score"`
+	RiskAction constant.RiskAction `json:"risk_action" gorm:"column:risk_action"`
+
+	// 批量提现批次 id，批次交易统一签名、发送，不单独签名
+	BatchId string `json:"batch_id" gorm:"column:batch_id"`
 }
 
 type WithdrawsView interface {
@@ -58,11 +68,16 @@ type WithdrawDB interface {
 	UpdateWithdrawById(requestId string, guid string, signedTx string, status constant.TxStatus) error
 	UpdateWithdrawStatusByTxHash(requestId string, status constant.TxStatus, withdrawsList []*Withdraws) error
 	UpdateWithdrawListById(requestId string, withdrawsList []*Withdraws) error
+	UpdateWithdrawStatusById(requestId string, guid string, status constant.TxStatus) error
+	ReleaseHoldWithdraw(requestId string, guid string, status constant.TxStatus, riskAction constant.RiskAction) error
 	HandleFallBackWithdraw(requestId string, startBlock, EndBlock *big.Int) error
 
 	// todo
 }
 
+/*提现不存在或已不在挂起状态*/
+var ErrWithdrawNotHeld = errors.New("withdraw is not on hold")
+
 type withdrawsDB struct {
 	gorm *gorm.DB
 }
@@ -179,11 +194,11 @@ func (db *withdrawsDB) CheckWithdrawExistsById(tableName string, id string) erro
 	return nil
 }
 
-/*查询所有已签名未发送提现*/
+/*查询所有已签名未发送提现（批次内提现随批次发送）*/
 func (db *withdrawsDB) UnSendWithdrawsList(requestId string) ([]*Withdraws, error) {
 	var withdrawsList []*Withdraws
 	err := db.gorm.Table("withdraws_"+requestId).
-		Where("status = ?", constant.TxStatusSigned).
+		Where("status = ? AND batch_id = ''", constant.TxStatusSigned).
 		Find(&withdrawsList).Error
 
 	if err != nil {
@@ -252,11 +267,11 @@ func (db *withdrawsDB) UpdateWithdrawListById(requestId string, withdrawsList []
 	})
 }
 
-/*查询提现通知*/
+/*查询提现通知（已确认的提现与筛查挂起的提现）*/
 func (db *withdrawsDB) QueryNotifyWithdraws(requestId string) ([]*Withdraws, error) {
 	var notifyWithdraws []*Withdraws
 	result := db.gorm.Table("withdraws_"+requestId).
-		Where("status = ?", constant.TxStatusWalletDone).
+		Where("status IN ?", []constant.TxStatus{constant.TxStatusWalletDone, constant.TxStatusHold}).
 		Find(&notifyWithdraws)
 
 	if result.Error != nil {
@@ -265,3 +280,37 @@ func (db *withdrawsDB) QueryNotifyWithdraws(requestId string) ([]*Withdraws, err
 
 	return notifyWithdraws, nil
 }
+
+/*根据 id 更新提现状态*/
+func (db *withdrawsDB) UpdateWithdrawStatusById(requestId string, guid string, status constant.TxStatus) error {
+	result := db.gorm.Table("withdraws_"+requestId).
+		Where("guid = ?", guid).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("update withdraw status failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("withdraw not found for GUID: %s", guid)
+	}
+	return nil
+}
+
+/*
+挂起提现人工处理：仅当状态仍为 hold / hold_notified 时更新状态与处置动作，
+并发或重复处理时后到者不更新任何行，返回 ErrWithdrawNotHeld
+*/
+func (db *withdrawsDB) ReleaseHoldWithdraw(requestId string, guid string, status constant.TxStatus, riskAction constant.RiskAction) error {
+	result := db.gorm.Table("withdraws_"+requestId).
+		Where("guid = ? AND status IN ?", guid, []constant.TxStatus{constant.TxStatusHold, constant.TxStatusHoldNotified}).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"risk_action": riskAction,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("release hold withdraw failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWithdrawNotHeld
+	}
+	return nil
+}
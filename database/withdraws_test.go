@@ -3,7 +3,10 @@ package database
 import (
 	"testing"
 
+	"exchange-wallet-service/database/constant"
+
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -35,3 +38,42 @@ func TestNewWithdrawsDB(t *testing.T) {
 		t.Fatal("NewWithdrawsDB returned nil")
 	}
 }
+
+func TestReleaseHoldWithdraw(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "withdraws_biz-1" SET "risk_action"=\$1,"status"=\$2 WHERE guid = \$3 AND status IN \(\$4,\$5\)`).
+		WithArgs(constant.RiskActionAllow, constant.TxStatusCreateUnsigned, "withdraw-guid", constant.TxStatusHold, constant.TxStatusHoldNotified).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	db := NewWithdrawsDB(gormDB)
+	err := db.ReleaseHoldWithdraw("biz-1", "withdraw-guid", constant.TxStatusCreateUnsigned, constant.RiskActionAllow)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+/*已被并发或上一次处理，不更新任何行*/
+func TestReleaseHoldWithdrawAlreadyHandled(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	defer func() {
+		db, _ := gormDB.DB()
+		db.Close()
+	}()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "withdraws_biz-1" SET "risk_action"=\$1,"status"=\$2 WHERE guid = \$3 AND status IN \(\$4,\$5\)`).
+		WithArgs(constant.RiskActionReject, constant.TxStatusRejected, "withdraw-guid", constant.TxStatusHold, constant.TxStatusHoldNotified).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	db := NewWithdrawsDB(gormDB)
+	err := db.ReleaseHoldWithdraw("biz-1", "withdraw-guid", constant.TxStatusRejected, constant.RiskActionReject)
+	assert.ErrorIs(t, err, ErrWithdrawNotHeld)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,216 @@
+package fee
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/log"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/*费率档位*/
+type Level string
+
+const (
+	LevelSlow   Level = "slow"
+	LevelNormal Level = "normal"
+	LevelFast   Level = "fast"
+	/*自定义：请求直接指定 maxFeePerGas 与 maxPriorityFeePerGas*/
+	LevelCustom Level = "custom"
+)
+
+/*解析费率档位，未指定时为 fast（与原固定取 FastFee 一致）*/
+func ParseLevel(level string) (Level, error) {
+	switch Level(strings.ToLower(strings.TrimSpace(level))) {
+	case "", LevelFast:
+		return LevelFast, nil
+	case LevelSlow:
+		return LevelSlow, nil
+	case LevelNormal:
+		return LevelNormal, nil
+	case LevelCustom:
+		return LevelCustom, nil
+	default:
+		return "", fmt.Errorf("invalid fee level: %s", level)
+	}
+}
+
+/*链上费率建议（chains-union-rpc GetFee），每档格式 baseFee|tip|*multiplier*/
+type Suggestion struct {
+	Slow   string
+	Normal string
+	Fast   string
+}
+
+/*定价请求*/
+type Request struct {
+	Level Level
+	/*仅自定义档位使用*/
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+}
+
+/*定价结果*/
+type Quote struct {
+	Level      Level
+	BaseFee    *big.Int
+	TipCap     *big.Int
+	Multiplier int64
+	/*矿工优先费（legacy 定价时等于 gasPrice）*/
+	MaxPriorityFeePerGas *big.Int
+	/*每单位 gas 最高价格（legacy 定价时即 gasPrice）*/
+	MaxFeePerGas *big.Int
+	/*legacy（非 EIP-1559）定价*/
+	Legacy bool
+}
+
+/*
+费率策略：根据链上建议与请求档位给出 gas 价格，
+内置 EIP-1559 与 legacy 两种，接入其他链只需实现该接口
+*/
+type Strategy interface {
+	Quote(suggestion *Suggestion, request *Request) (*Quote, error)
+}
+
+/*按配置新建费率策略*/
+func NewStrategy(legacy bool) Strategy {
+	if legacy {
+		log.Info("new legacy fee strategy")
+		return &LegacyStrategy{}
+	}
+	log.Info("new eip-1559 fee strategy")
+	return &DynamicStrategy{}
+}
+
+/*EIP-1559 定价：tip = tipCap * multiplier，maxFee = baseFee + 2 * tip*/
+type DynamicStrategy struct{}
+
+func (s *DynamicStrategy) Quote(suggestion *Suggestion, request *Request) (*Quote, error) {
+	if request.Level == LevelCustom {
+		return customQuote(request, false)
+	}
+	quote, err := parseLevel(suggestion, request.Level)
+	if err != nil {
+		return nil, err
+	}
+	quote.MaxPriorityFeePerGas = new(big.Int).Mul(quote.TipCap, big.NewInt(quote.Multiplier))
+	quote.MaxFeePerGas = new(big.Int).Mul(quote.MaxPriorityFeePerGas, big.NewInt(2))
+	quote.MaxFeePerGas.Add(quote.MaxFeePerGas, quote.BaseFee)
+	return quote, nil
+}
+
+/*legacy 定价：gasPrice = baseFee + tipCap * multiplier，maxFee 与 tip 均为 gasPrice*/
+type LegacyStrategy struct{}
+
+func (s *LegacyStrategy) Quote(suggestion *Suggestion, request *Request) (*Quote, error) {
+	if request.Level == LevelCustom {
+		return customQuote(request, true)
+	}
+	quote, err := parseLevel(suggestion, request.Level)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := new(big.Int).Mul(quote.TipCap, big.NewInt(quote.Multiplier))
+	gasPrice.Add(gasPrice, quote.BaseFee)
+	quote.MaxFeePerGas = gasPrice
+	quote.MaxPriorityFeePerGas = new(big.Int).Set(gasPrice)
+	quote.Legacy = true
+	return quote, nil
+}
+
+/*取档位对应的链上建议并解析*/
+func parseLevel(suggestion *Suggestion, level Level) (*Quote, error) {
+	if suggestion == nil {
+		return nil, errors.New("fee suggestion is nil")
+	}
+	var raw string
+	switch level {
+	case LevelSlow:
+		raw = suggestion.Slow
+	case LevelNormal:
+		raw = suggestion.Normal
+	case LevelFast:
+		raw = suggestion.Fast
+	default:
+		return nil, fmt.Errorf("unsupported fee level: %s", level)
+	}
+	baseFee, tipCap, multiplier, err := ParseSuggestion(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{Level: level, BaseFee: baseFee, TipCap: tipCap, Multiplier: multiplier}, nil
+}
+
+/*自定义档位：legacy 定价只取 maxFeePerGas 作为 gasPrice*/
+func customQuote(request *Request, legacy bool) (*Quote, error) {
+	maxFeePerGas, ok := new(big.Int).SetString(request.MaxFeePerGas, 10)
+	if !ok || maxFeePerGas.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid custom max fee per gas: %s", request.MaxFeePerGas)
+	}
+	if legacy {
+		return &Quote{
+			Level:                LevelCustom,
+			MaxFeePerGas:         maxFeePerGas,
+			MaxPriorityFeePerGas: new(big.Int).Set(maxFeePerGas),
+			Legacy:               true,
+		}, nil
+	}
+	maxPriorityFeePerGas, ok := new(big.Int).SetString(request.MaxPriorityFeePerGas, 10)
+	if !ok || maxPriorityFeePerGas.Sign() < 0 {
+		return nil, fmt.Errorf("invalid custom max priority fee per gas: %s", request.MaxPriorityFeePerGas)
+	}
+	if maxPriorityFeePerGas.Cmp(maxFeePerGas) > 0 {
+		return nil, fmt.Errorf("max priority fee per gas %s exceeds max fee per gas %s", maxPriorityFeePerGas, maxFeePerGas)
+	}
+	return &Quote{
+		Level:                LevelCustom,
+		MaxFeePerGas:         maxFeePerGas,
+		MaxPriorityFeePerGas: maxPriorityFeePerGas,
+	}, nil
+}
+
+/*解析链上建议 baseFee|tip|*multiplier*/
+func ParseSuggestion(raw string) (*big.Int, *big.Int, int64, error) {
+	parts := strings.Split(raw, "|")
+	if len(parts) != 3 {
+		return nil, nil, 0, fmt.Errorf("invalid fee format: %s", raw)
+	}
+	baseFee, ok := new(big.Int).SetString(parts[0], 10)
+	if !ok {
+		return nil, nil, 0, fmt.Errorf("invalid base fee: %s", parts[0])
+	}
+	tipCap, ok := new(big.Int).SetString(parts[1], 10)
+	if !ok {
+		return nil, nil, 0, fmt.Errorf("invalid gas tip cap: %s", parts[1])
+	}
+	multiplier, err := strconv.ParseInt(strings.TrimPrefix(parts[2], "*"), 10, 64)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("invalid multiplier: %s", parts[2])
+	}
+	return baseFee, tipCap, multiplier, nil
+}
+
+/*
+项目方费率上限（每单位 gas，wei，nil 或 0 不限制）：
+maxFee 超过上限时压到上限，tip 不超过 maxFee；上限低于 baseFee 时交易无法上链，直接拒绝
+*/
+func ApplyCeiling(quote *Quote, ceiling *big.Int) error {
+	if ceiling == nil || ceiling.Sign() <= 0 || quote.MaxFeePerGas.Cmp(ceiling) <= 0 {
+		return nil
+	}
+	if quote.BaseFee != nil && quote.BaseFee.Cmp(ceiling) >= 0 {
+		return fmt.Errorf("fee ceiling %s is below base fee %s", ceiling, quote.BaseFee)
+	}
+	log.Warn("max fee per gas exceeds business ceiling, cap it", "maxFeePerGas", quote.MaxFeePerGas, "ceiling", ceiling)
+	quote.MaxFeePerGas = new(big.Int).Set(ceiling)
+	if quote.MaxPriorityFeePerGas.Cmp(ceiling) > 0 {
+		quote.MaxPriorityFeePerGas = new(big.Int).Set(ceiling)
+	}
+	return nil
+}
+
+/*gas 限制加安全余量（百分比）*/
+func WithMargin(gasLimit uint64, marginPercent uint64) uint64 {
+	return gasLimit + gasLimit*marginPercent/100
+}
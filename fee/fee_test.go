@@ -0,0 +1,76 @@
+package fee
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suggestion = &Suggestion{
+	Slow:   "100|1|*1",
+	Normal: "100|2|*2",
+	Fast:   "100|3|*3",
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, LevelFast, level)
+	level, err = ParseLevel("Slow")
+	require.NoError(t, err)
+	assert.Equal(t, LevelSlow, level)
+	_, err = ParseLevel("turbo")
+	assert.Error(t, err)
+}
+
+func TestDynamicStrategy(t *testing.T) {
+	strategy := NewStrategy(false)
+
+	fast, err := strategy.Quote(suggestion, &Request{Level: LevelFast})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(9), fast.MaxPriorityFeePerGas)
+	assert.Equal(t, big.NewInt(118), fast.MaxFeePerGas)
+	assert.False(t, fast.Legacy)
+
+	slow, err := strategy.Quote(suggestion, &Request{Level: LevelSlow})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(102), slow.MaxFeePerGas)
+
+	custom, err := strategy.Quote(suggestion, &Request{Level: LevelCustom, MaxFeePerGas: "500", MaxPriorityFeePerGas: "20"})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(500), custom.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(20), custom.MaxPriorityFeePerGas)
+
+	_, err = strategy.Quote(suggestion, &Request{Level: LevelCustom, MaxFeePerGas: "10", MaxPriorityFeePerGas: "20"})
+	assert.Error(t, err)
+}
+
+func TestLegacyStrategy(t *testing.T) {
+	strategy := NewStrategy(true)
+	normal, err := strategy.Quote(suggestion, &Request{Level: LevelNormal})
+	require.NoError(t, err)
+	assert.True(t, normal.Legacy)
+	assert.Equal(t, big.NewInt(104), normal.MaxFeePerGas)
+	assert.Equal(t, normal.MaxFeePerGas, normal.MaxPriorityFeePerGas)
+}
+
+func TestApplyCeiling(t *testing.T) {
+	quote, err := NewStrategy(false).Quote(suggestion, &Request{Level: LevelFast})
+	require.NoError(t, err)
+
+	require.NoError(t, ApplyCeiling(quote, nil))
+	assert.Equal(t, big.NewInt(118), quote.MaxFeePerGas)
+
+	require.NoError(t, ApplyCeiling(quote, big.NewInt(105)))
+	assert.Equal(t, big.NewInt(105), quote.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(9), quote.MaxPriorityFeePerGas)
+
+	assert.Error(t, ApplyCeiling(quote, big.NewInt(100)))
+}
+
+func TestWithMargin(t *testing.T) {
+	assert.Equal(t, uint64(60000), WithMargin(50000, 20))
+	assert.Equal(t, uint64(21000), WithMargin(21000, 0))
+}
@@ -71,6 +71,193 @@ var (
 		EnvVars: prefixEnvVars("BLOCKS_STEP"),
 		Value:   500,
 	}
+	TransferLogEnableFlag = &cli.BoolFlag{
+		Name:    "transfer-log-enable",
+		Usage:   "Whether to detect token transfers from Transfer event logs via rpc-url",
+		EnvVars: prefixEnvVars("TRANSFER_LOG_ENABLE"),
+	}
+	TraceEnableFlag = &cli.BoolFlag{
+		Name:    "trace-enable",
+		Usage:   "Whether to detect internal native transfers to user addresses by tracing blocks via rpc-url",
+		EnvVars: prefixEnvVars("TRACE_ENABLE"),
+	}
+
+	// DenyListFileFlag screening flags
+	DenyListFileFlag = &cli.StringFlag{
+		Name:    "deny-list-file",
+		Usage:   "Path of the deny list file (one address per line, optional label after a comma)",
+		EnvVars: prefixEnvVars("DENY_LIST_FILE"),
+	}
+	LookalikePrefixLengthFlag = &cli.IntFlag{
+		Name:    "lookalike-prefix-length",
+		Usage:   "Number of leading hex chars compared by the lookalike address detector",
+		EnvVars: prefixEnvVars("LOOKALIKE_PREFIX_LENGTH"),
+		Value:   4,
+	}
+	LookalikeSuffixLengthFlag = &cli.IntFlag{
+		Name:    "lookalike-suffix-length",
+		Usage:   "Number of trailing hex chars compared by the lookalike address detector",
+		EnvVars: prefixEnvVars("LOOKALIKE_SUFFIX_LENGTH"),
+		Value:   4,
+	}
+	CounterpartyLimitFlag = &cli.IntFlag{
+		Name:    "counterparty-limit",
+		Usage:   "Number of recent transactions whose counterparties are compared by the lookalike detector",
+		EnvVars: prefixEnvVars("COUNTERPARTY_LIMIT"),
+		Value:   1000,
+	}
+
+	// RiskScorerUrlFlag risk scoring flags
+	RiskScorerUrlFlag = &cli.StringFlag{
+		Name:    "risk-scorer-url",
+		Usage:   "URL of the external risk scoring service, the local rules scorer is used when empty",
+		EnvVars: prefixEnvVars("RISK_SCORER_URL"),
+	}
+	RiskScorerTimeoutFlag = &cli.DurationFlag{
+		Name:    "risk-scorer-timeout",
+		Usage:   "Timeout of a request to the external risk scoring service",
+		EnvVars: prefixEnvVars("RISK_SCORER_TIMEOUT"),
+		Value:   5 * time.Second,
+	}
+	RiskHoldScoreFlag = &cli.IntFlag{
+		Name:    "risk-hold-score",
+		Usage:   "Risk score (0-100) from which a deposit or withdraw is held",
+		EnvVars: prefixEnvVars("RISK_HOLD_SCORE"),
+		Value:   60,
+	}
+	RiskRejectScoreFlag = &cli.IntFlag{
+		Name:    "risk-reject-score",
+		Usage:   "Risk score (0-100) from which a deposit or withdraw is rejected",
+		EnvVars: prefixEnvVars("RISK_REJECT_SCORE"),
+		Value:   90,
+	}
+	RiskLargeAmountsFlag = &cli.StringSliceFlag{
+		Name:    "risk-large-amounts",
+		Usage:   "Large amount thresholds of the rules scorer, as token_address:amount in the smallest unit (zero address for native)",
+		EnvVars: prefixEnvVars("RISK_LARGE_AMOUNTS"),
+	}
+
+	// ReconcileIntervalFlag reconciliation flags
+	ReconcileIntervalFlag = &cli.DurationFlag{
+		Name:    "reconcile-interval",
+		Usage:   "Interval of the balance reconciliation against on-chain balances, 0 disables it in the worker",
+		EnvVars: prefixEnvVars("RECONCILE_INTERVAL"),
+		Value:   time.Hour,
+	}
+	ReconcileAlertEnableFlag = &cli.BoolFlag{
+		Name:    "reconcile-alert-enable",
+		Usage:   "Whether to alert the business notify url when reconciliation finds discrepancies",
+		EnvVars: prefixEnvVars("RECONCILE_ALERT_ENABLE"),
+	}
+
+	// SnapshotBlockIntervalFlag balance snapshot flags
+	SnapshotBlockIntervalFlag = &cli.Uint64Flag{
+		Name:    "snapshot-block-interval",
+		Usage:   "Take a balance snapshot every N blocks, 0 disables block based snapshots",
+		EnvVars: prefixEnvVars("SNAPSHOT_BLOCK_INTERVAL"),
+	}
+	SnapshotDailyFlag = &cli.BoolFlag{
+		Name:    "snapshot-daily",
+		Usage:   "Take a balance snapshot once per UTC day",
+		EnvVars: prefixEnvVars("SNAPSHOT_DAILY"),
+		Value:   true,
+	}
+
+	// DisperseContractFlag batch withdraw flags
+	DisperseContractFlag = &cli.StringFlag{
+		Name:    "disperse-contract",
+		Usage:   "Disperse contract address used by batched withdrawals, empty disables batch mode",
+		EnvVars: prefixEnvVars("DISPERSE_CONTRACT"),
+	}
+
+	// FeeLegacyFlag fee strategy flags
+	FeeLegacyFlag = &cli.BoolFlag{
+		Name:    "fee-legacy",
+		Usage:   "Use legacy (non EIP-1559) gas pricing and build type-0 transactions",
+		EnvVars: prefixEnvVars("FEE_LEGACY"),
+	}
+	GasEstimateEnableFlag = &cli.BoolFlag{
+		Name:    "gas-estimate-enable",
+		Usage:   "Estimate gas limit through the chain node rpc url instead of fixed gas limits",
+		EnvVars: prefixEnvVars("GAS_ESTIMATE_ENABLE"),
+	}
+	GasLimitMarginFlag = &cli.Uint64Flag{
+		Name:    "gas-limit-margin",
+		Usage:   "Safety margin in percent added to estimated gas limits",
+		EnvVars: prefixEnvVars("GAS_LIMIT_MARGIN"),
+		Value:   20,
+	}
+
+	// SyncStaleTimeoutFlag health flags
+	SyncStaleTimeoutFlag = &cli.DurationFlag{
+		Name:    "sync-stale-timeout",
+		Usage:   "Fail the liveness check when the synchronizer makes no progress for this long",
+		EnvVars: prefixEnvVars("SYNC_STALE_TIMEOUT"),
+		Value:   5 * time.Minute,
+	}
+
+	// TracingEndpointFlag tracing flags
+	TracingEndpointFlag = &cli.StringFlag{
+		Name:    "tracing-endpoint",
+		Usage:   "The OTLP gRPC collector endpoint to export traces to, empty disables tracing",
+		EnvVars: prefixEnvVars("TRACING_ENDPOINT"),
+	}
+	TracingSampleRatioFlag = &cli.Float64Flag{
+		Name:    "tracing-sample-ratio",
+		Usage:   "Fraction of root spans to sample, between 0 and 1",
+		EnvVars: prefixEnvVars("TRACING_SAMPLE_RATIO"),
+		Value:   1,
+	}
+
+	// RpcTLSCertFileFlag tls flags
+	RpcTLSCertFileFlag = &cli.StringFlag{
+		Name:    "rpc-tls-cert-file",
+		Usage:   "The TLS certificate file of the rpc server, empty serves plaintext",
+		EnvVars: prefixEnvVars("RPC_TLS_CERT_FILE"),
+	}
+	RpcTLSKeyFileFlag = &cli.StringFlag{
+		Name:    "rpc-tls-key-file",
+		Usage:   "The TLS private key file of the rpc server",
+		EnvVars: prefixEnvVars("RPC_TLS_KEY_FILE"),
+	}
+	RpcTLSClientCAFileFlag = &cli.StringFlag{
+		Name:    "rpc-tls-client-ca-file",
+		Usage:   "The CA file to verify client certificates, enables mutual TLS; the certificate common name is the business request id",
+		EnvVars: prefixEnvVars("RPC_TLS_CLIENT_CA_FILE"),
+	}
+	ChainsUnionTLSEnableFlag = &cli.BoolFlag{
+		Name:    "chains-union-tls-enable",
+		Usage:   "Connect to chains-union-rpc over TLS",
+		EnvVars: prefixEnvVars("CHAINS_UNION_TLS_ENABLE"),
+	}
+	ChainsUnionTLSCAFileFlag = &cli.StringFlag{
+		Name:    "chains-union-tls-ca-file",
+		Usage:   "The CA file to verify the chains-union-rpc certificate, empty uses system roots",
+		EnvVars: prefixEnvVars("CHAINS_UNION_TLS_CA_FILE"),
+	}
+	ChainsUnionTLSCertFileFlag = &cli.StringFlag{
+		Name:    "chains-union-tls-cert-file",
+		Usage:   "The client certificate file presented to chains-union-rpc",
+		EnvVars: prefixEnvVars("CHAINS_UNION_TLS_CERT_FILE"),
+	}
+	ChainsUnionTLSKeyFileFlag = &cli.StringFlag{
+		Name:    "chains-union-tls-key-file",
+		Usage:   "The client private key file presented to chains-union-rpc",
+		EnvVars: prefixEnvVars("CHAINS_UNION_TLS_KEY_FILE"),
+	}
+	ChainsUnionTLSServerNameFlag = &cli.StringFlag{
+		Name:    "chains-union-tls-server-name",
+		Usage:   "The server name to verify the chains-union-rpc certificate against, empty uses the dial address",
+		EnvVars: prefixEnvVars("CHAINS_UNION_TLS_SERVER_NAME"),
+	}
+
+	// RateLimitRefreshIntervalFlag rate limit flags
+	RateLimitRefreshIntervalFlag = &cli.DurationFlag{
+		Name:    "rate-limit-refresh-interval",
+		Usage:   "How often to reload rate limits and business quotas from the database",
+		EnvVars: prefixEnvVars("RATE_LIMIT_REFRESH_INTERVAL"),
+		Value:   30 * time.Second,
+	}
 
 	// RpcHostFlag rpc api flags
 	RpcHostFlag = &cli.StringFlag{
@@ -102,7 +289,7 @@ var (
 	}
 	MetricsPortFlag = &cli.IntFlag{
 		Name:     "metrics-port",
-		Usage:    "The port of the metrics",
+		Usage:    "The port of the metrics, 0 disables the metrics server",
 		EnvVars:  prefixEnvVars("METRICS_PORT"),
 		Value:    7214,
 		Required: true,
@@ -177,27 +364,35 @@ var (
 		Usage:   "The db name of the slave database",
 		EnvVars: prefixEnvVars("SLAVE_DB_NAME"),
 	}
+	SlaveDbMaxLagFlag = &cli.DurationFlag{
+		Name:    "slave-db-max-lag",
+		Usage:   "The max replication lag of the slave database, reads fall back to the master beyond it",
+		EnvVars: prefixEnvVars("SLAVE_DB_MAX_LAG"),
+		Value:   10 * time.Second,
+	}
 
 	// cache flags
 	ApiCacheListSizeFlag = &cli.UintFlag{
 		Name:    "api-cache-list-size",
 		Usage:   "The size of the api cache list",
 		EnvVars: prefixEnvVars("API_CACHE_LIST_SIZE"),
+		Value:   100000,
 	}
 	ApiCacheDetailSizeFlag = &cli.UintFlag{
 		Name:    "api-cache-detail-size",
 		Usage:   "The size of the api cache detail",
 		EnvVars: prefixEnvVars("API_CACHE_LIST_DETAIL"),
+		Value:   100000,
 	}
 	ApiCacheListExpireTimeFlag = &cli.DurationFlag{
 		Name:    "api-cache-list-expire-time",
-		Usage:   "The interval of collect wallet funding",
+		Usage:   "The expire time of the api cache list",
 		EnvVars: prefixEnvVars("API_CACHE_LIST_EXPIRE_TIME"),
 		Value:   time.Minute * 30,
 	}
 	ApiCacheDetailExpireTimeFlag = &cli.DurationFlag{
 		Name:    "api-cache-detail-expire-time",
-		Usage:   "The interval of collect wallet funding",
+		Usage:   "The expire time of the api cache detail",
 		EnvVars: prefixEnvVars("API_CACHE_DETAIL_EXPIRE_TIME"),
 		Value:   time.Minute * 30,
 	}
@@ -227,11 +422,43 @@ var requireFlags = []cli.Flag{
 }
 
 var optionalFlags = []cli.Flag{
+	TransferLogEnableFlag,
+	TraceEnableFlag,
+	DenyListFileFlag,
+	LookalikePrefixLengthFlag,
+	LookalikeSuffixLengthFlag,
+	CounterpartyLimitFlag,
+	RiskScorerUrlFlag,
+	RiskScorerTimeoutFlag,
+	RiskHoldScoreFlag,
+	RiskRejectScoreFlag,
+	RiskLargeAmountsFlag,
+	ReconcileIntervalFlag,
+	ReconcileAlertEnableFlag,
+	SnapshotBlockIntervalFlag,
+	SnapshotDailyFlag,
+	DisperseContractFlag,
+	FeeLegacyFlag,
+	GasEstimateEnableFlag,
+	GasLimitMarginFlag,
+	SyncStaleTimeoutFlag,
+	TracingEndpointFlag,
+	TracingSampleRatioFlag,
+	RpcTLSCertFileFlag,
+	RpcTLSKeyFileFlag,
+	RpcTLSClientCAFileFlag,
+	ChainsUnionTLSEnableFlag,
+	ChainsUnionTLSCAFileFlag,
+	ChainsUnionTLSCertFileFlag,
+	ChainsUnionTLSKeyFileFlag,
+	ChainsUnionTLSServerNameFlag,
+	RateLimitRefreshIntervalFlag,
 	SlaveDbHostFlag,
 	SlaveDbPortFlag,
 	SlaveDbUserFlag,
 	SlaveDbPasswordFlag,
 	SlaveDbNameFlag,
+	SlaveDbMaxLagFlag,
 	ApiCacheListSizeFlag,
 	ApiCacheDetailSizeFlag,
 	ApiCacheListExpireTimeFlag,
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"exchange-wallet-service/common/clock"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+/*gRPC 健康状态刷新间隔*/
+const GRPCRefreshInterval = 10 * time.Second
+
+/*gRPC 健康协议中表示存活检查的服务名，空服务名与业务服务名表示就绪*/
+const LivenessService = "liveness"
+
+/*
+gRPC 健康协议（grpc.health.v1.Health）：
+定时运行检查并更新各服务状态，Check 与 Watch 直接读取最新状态
+*/
+type GRPCServer struct {
+	server   *grpchealth.Server
+	checker  *Checker
+	services []string
+	worker   *clock.LoopFn
+}
+
+/*新建 gRPC 健康服务，services 为就绪检查对应的服务名*/
+func NewGRPCServer(checker *Checker, services ...string) *GRPCServer {
+	return &GRPCServer{
+		server:   grpchealth.NewServer(),
+		checker:  checker,
+		services: append([]string{""}, services...),
+	}
+}
+
+/*注册到 gRPC 服务*/
+func (s *GRPCServer) Register(gs *grpc.Server) {
+	healthpb.RegisterHealthServer(gs, s.server)
+}
+
+/*先刷新一次状态，再定时刷新*/
+func (s *GRPCServer) Start() {
+	s.refresh(context.Background())
+	s.worker = clock.NewLoopFn(clock.SystemClock, s.refresh, func() error {
+		s.server.Shutdown()
+		return nil
+	}, GRPCRefreshInterval)
+}
+
+/*停止刷新，所有服务置为 NOT_SERVING*/
+func (s *GRPCServer) Stop() error {
+	if s == nil || s.worker == nil {
+		return nil
+	}
+	return s.worker.Close()
+}
+
+func (s *GRPCServer) refresh(ctx context.Context) {
+	ready := servingStatus(s.checker.Ready(ctx))
+	for _, service := range s.services {
+		s.server.SetServingStatus(service, ready)
+	}
+	s.server.SetServingStatus(LivenessService, servingStatus(s.checker.Live(ctx)))
+	log.Debug("refresh grpc health status", "ready", ready)
+}
+
+func servingStatus(report *Report) healthpb.HealthCheckResponse_ServingStatus {
+	if report.Healthy() {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
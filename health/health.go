@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+/*单项检查超时*/
+const CheckTimeout = 5 * time.Second
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+/*单项检查，返回错误即不健康*/
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	/*存活检查：失败说明进程卡死，编排系统应重启；否则只影响就绪*/
+	liveness bool
+	fn       CheckFunc
+}
+
+/*单项检查结果*/
+type Result struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+/*检查报告*/
+type Report struct {
+	Status string    `json:"status"`
+	Checks []*Result `json:"checks"`
+}
+
+func (r *Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+/*
+健康检查器：
+* 存活（liveness）只运行存活检查
+* 就绪（readiness）运行全部检查
+*/
+type Checker struct {
+	mu     sync.RWMutex
+	checks []check
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+/*添加存活检查（同时计入就绪）*/
+func (c *Checker) AddLiveness(name string, fn CheckFunc) {
+	c.add(check{name: name, liveness: true, fn: fn})
+}
+
+/*添加就绪检查*/
+func (c *Checker) AddReadiness(name string, fn CheckFunc) {
+	c.add(check{name: name, fn: fn})
+}
+
+func (c *Checker) add(item check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, item)
+}
+
+/*存活检查*/
+func (c *Checker) Live(ctx context.Context) *Report {
+	return c.run(ctx, true)
+}
+
+/*就绪检查*/
+func (c *Checker) Ready(ctx context.Context) *Report {
+	return c.run(ctx, false)
+}
+
+/*并发运行检查，任一失败整体失败*/
+func (c *Checker) run(ctx context.Context, livenessOnly bool) *Report {
+	c.mu.RLock()
+	var checks []check
+	for _, item := range c.checks {
+		if !livenessOnly || item.liveness {
+			checks = append(checks, item)
+		}
+	}
+	c.mu.RUnlock()
+
+	report := &Report{Status: StatusOK, Checks: make([]*Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, item := range checks {
+		wg.Add(1)
+		go func(i int, item check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+			defer cancel()
+			result := &Result{Name: item.name, Status: StatusOK}
+			if err := item.fn(checkCtx); err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+			report.Checks[i] = result
+		}(i, item)
+	}
+	wg.Wait()
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			log.Warn("health check fail", "name", result.Name, "err", result.Error)
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+/*HTTP 存活与就绪路由：/healthz、/readyz，健康 200，不健康 503*/
+func (c *Checker) Routes() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/healthz": reportHandler(c.Live),
+		"/readyz":  reportHandler(c.Ready),
+	}
+}
+
+func reportHandler(run func(ctx context.Context) *Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := run(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			log.Error("failed to write health report", "err", err)
+		}
+	})
+}
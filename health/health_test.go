@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func okCheck(context.Context) error {
+	return nil
+}
+
+func failCheck(context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestCheckerLivenessAndReadiness(t *testing.T) {
+	checker := NewChecker()
+	checker.AddLiveness("synchronizer", okCheck)
+	checker.AddReadiness("database", failCheck)
+
+	live := checker.Live(context.Background())
+	assert.True(t, live.Healthy())
+	assert.Len(t, live.Checks, 1)
+
+	ready := checker.Ready(context.Background())
+	assert.False(t, ready.Healthy())
+	require.Len(t, ready.Checks, 2)
+	assert.Equal(t, StatusOK, ready.Checks[0].Status)
+	assert.Equal(t, StatusFail, ready.Checks[1].Status)
+	assert.Equal(t, "connection refused", ready.Checks[1].Error)
+}
+
+func TestRoutes(t *testing.T) {
+	checker := NewChecker()
+	checker.AddLiveness("synchronizer", okCheck)
+	checker.AddReadiness("chainsunion", failCheck)
+	routes := checker.Routes()
+
+	recorder := httptest.NewRecorder()
+	routes["/healthz"].ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	routes["/readyz"].ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	var report Report
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, StatusFail, report.Status)
+}
+
+func TestGRPCServerRefresh(t *testing.T) {
+	checker := NewChecker()
+	checker.AddLiveness("synchronizer", okCheck)
+	checker.AddReadiness("database", failCheck)
+	server := NewGRPCServer(checker, "services.WalletBusinessServices")
+	server.refresh(context.Background())
+
+	for service, want := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":                                healthpb.HealthCheckResponse_NOT_SERVING,
+		"services.WalletBusinessServices": healthpb.HealthCheckResponse_NOT_SERVING,
+		LivenessService:                   healthpb.HealthCheckResponse_SERVING,
+	} {
+		resp, err := server.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, want, resp.Status, service)
+	}
+}
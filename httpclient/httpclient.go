@@ -1,8 +1,10 @@
 package httpclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"exchange-wallet-service/tracing"
 	"fmt"
 	"github.com/ethereum/go-ethereum/log"
 	gresty "github.com/go-resty/resty/v2"
@@ -38,14 +40,17 @@ func NewNotifyClient(baseUrl string) (*NotifyClient, error) {
 }
 
 /*通知方法封装*/
-func (nc *NotifyClient) BusinessNotify(notifyData *NotifyRequest) (bool, error) {
+func (nc *NotifyClient) BusinessNotify(ctx context.Context, notifyData *NotifyRequest) (bool, error) {
 	body, err := json.Marshal(notifyData)
 	if err != nil {
 		log.Error("fail to marshal notifyRequest data", "err", err)
 		return false, err
 	}
 
-	res, err := nc.client.R().
+	request := nc.client.R().SetContext(ctx)
+	/*链路头（traceparent），项目方可据此关联通知与钱包处理链路*/
+	tracing.InjectHeader(ctx, request.Header)
+	res, err := request.
 		SetHeader("Content-Type", "application/json").
 		SetBody(body).
 		SetResult(&NotifyResponse{}).Post("/exchange-wallet/notify")
@@ -59,3 +64,26 @@ func (nc *NotifyClient) BusinessNotify(notifyData *NotifyRequest) (bool, error)
 	}
 	return spt.Success, nil
 }
+
+/*告警方法封装*/
+func (nc *NotifyClient) BusinessAlert(alertData *AlertRequest) (bool, error) {
+	body, err := json.Marshal(alertData)
+	if err != nil {
+		log.Error("fail to marshal alertRequest data", "err", err)
+		return false, err
+	}
+
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/exchange-wallet/alert")
+	if err != nil {
+		log.Error("fail to send alertRequest", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, errors.New("response is not a NotifyResponse")
+	}
+	return spt.Success, nil
+}
@@ -21,8 +21,30 @@ type Transaction struct {
 	TokenAddress string                   `json:"token_address"`
 	TokenId      string                   `json:"token_id"`
 	TokenMeta    string                   `json:"token_meta"`
+	/*地址筛查命中时：是否挂起（未入账/未签名）与风险原因*/
+	Hold       bool   `json:"hold,omitempty"`
+	RiskReason string `json:"risk_reason,omitempty"`
 }
 
 type NotifyResponse struct {
 	Success bool `json:"success"`
 }
+
+/*http 告警请求*/
+type AlertRequest struct {
+	/*告警类型，如 reconciliation*/
+	Type    string `json:"type"`
+	RoundId string `json:"round_id"`
+	/*对账差异明细*/
+	Discrepancies []*Discrepancy `json:"discrepancies"`
+}
+
+/*对账差异*/
+type Discrepancy struct {
+	Address      string `json:"address"`
+	AddressType  string `json:"address_type"`
+	TokenAddress string `json:"token_address"`
+	DbBalance    string `json:"db_balance"`
+	ChainBalance string `json:"chain_balance"`
+	Difference   string `json:"difference"`
+}
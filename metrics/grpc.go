@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+/*gRPC 完整方法名取最后一段，如 /pkg.Service/method -> method*/
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+/*gRPC 服务端拦截器：记录各方法请求数、耗时与错误数*/
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	RecordGrpcRequest(methodName(info.FullMethod), start, err)
+	return resp, err
+}
+
+/*chains-union-rpc 客户端拦截器：记录各方法调用耗时与错误数*/
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	RecordChainsUnionCall(methodName(method), start, err)
+	return err
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+)
+
+/*
+服务指标：基于 go-ethereum metrics，单独的注册表，由指标服务以 Prometheus 文本格式导出。
+指标不带标签，维度（交易类型、gRPC 方法等）编码进指标名，导出时 "/" 转为 "_"
+*/
+var registry = gethmetrics.NewRegistry()
+
+func init() {
+	/*go-ethereum metrics 默认关闭，关闭时构造的都是空实现*/
+	gethmetrics.Enabled = true
+}
+
+/*直方图采样*/
+func newSample() gethmetrics.Sample {
+	return gethmetrics.NewExpDecaySample(1028, 0.015)
+}
+
+func counter(name string) gethmetrics.Counter {
+	return gethmetrics.GetOrRegisterCounter(name, registry)
+}
+
+func gauge(name string) gethmetrics.Gauge {
+	return gethmetrics.GetOrRegisterGauge(name, registry)
+}
+
+func timer(name string) gethmetrics.Timer {
+	return gethmetrics.GetOrRegisterTimer(name, registry)
+}
+
+func histogram(name string) gethmetrics.Histogram {
+	return gethmetrics.GetOrRegisterHistogramLazy(name, registry, newSample)
+}
+
+/*指标名片段：小写，非字母数字替换为下划线*/
+func sanitize(part string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '_'
+		}
+	}, part)
+}
+
+/*同步落后区块数：链上最新高度 - 已遍历高度*/
+func SetSyncLag(chainHead, lastTraversed uint64) {
+	gauge("synchronizer/chain_head").Update(int64(chainHead))
+	gauge("synchronizer/last_traversed").Update(int64(lastTraversed))
+	lag := int64(0)
+	if chainHead > lastTraversed {
+		lag = int64(chainHead - lastTraversed)
+	}
+	gauge("synchronizer/lag").Update(lag)
+}
+
+/*已处理区块数*/
+func AddBlocksProcessed(count int) {
+	counter("synchronizer/blocks_processed").Inc(int64(count))
+}
+
+/*发现的交易数（按交易类型）*/
+func AddTransactions(txType string, count int) {
+	counter("finder/transactions/" + sanitize(txType)).Inc(int64(count))
+}
+
+/*链重组次数与深度*/
+func RecordReorg(depth int) {
+	counter("fallback/reorgs").Inc(1)
+	histogram("fallback/reorg_depth").Update(int64(depth))
+}
+
+/*交易广播结果（kind：withdraw、internal、withdraw_batch）*/
+func RecordBroadcast(kind string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	counter("broadcast/" + sanitize(kind) + "/" + result).Inc(1)
+}
+
+/*项目方通知耗时与失败数（请求出错或项目方未确认均为失败）*/
+func RecordNotify(start time.Time, success bool) {
+	timer("notifier/latency").UpdateSince(start)
+	if !success {
+		counter("notifier/failures").Inc(1)
+	}
+}
+
+/*gRPC 服务请求耗时（计数即请求数）与错误数（按方法）*/
+func RecordGrpcRequest(method string, start time.Time, err error) {
+	name := "grpc/server/" + sanitize(method)
+	timer(name + "/latency").UpdateSince(start)
+	if err != nil {
+		counter(name + "/errors").Inc(1)
+	}
+}
+
+/*从库复制延迟（毫秒）与是否承接只读查询*/
+func SetReplicaLag(lag time.Duration, available bool) {
+	gauge("db/replica/lag_ms").Update(lag.Milliseconds())
+	value := int64(0)
+	if available {
+		value = 1
+	}
+	gauge("db/replica/available").Update(value)
+}
+
+/*被限流拒绝的请求数（按方法）*/
+func RecordRateLimited(method string) {
+	counter("ratelimit/" + sanitize(method) + "/rejected").Inc(1)
+}
+
+/*查询接口缓存命中与未命中数（按方法）*/
+func RecordApiCache(method string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	counter("apicache/" + sanitize(method) + "/" + result).Inc(1)
+}
+
+/*chains-union-rpc 调用耗时与错误数（按方法）*/
+func RecordChainsUnionCall(method string, start time.Time, err error) {
+	name := "chainsunion/" + sanitize(method)
+	timer(name + "/latency").UpdateSince(start)
+	if err != nil {
+		counter(name + "/errors").Inc(1)
+	}
+}
+
+/*数据库操作耗时与错误数（按操作：query、create、update、delete、raw、row）*/
+func RecordDBCall(operation string, duration time.Duration, err error) {
+	name := "db/" + sanitize(operation)
+	timer(name + "/latency").Update(duration)
+	if err != nil {
+		counter(name + "/errors").Inc(1)
+	}
+}
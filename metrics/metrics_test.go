@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T) string {
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestHandlerExportsMetrics(t *testing.T) {
+	SetSyncLag(120, 100)
+	AddBlocksProcessed(3)
+	AddTransactions("withdraw", 2)
+	RecordReorg(4)
+	RecordBroadcast("withdraw", errors.New("nonce too low"))
+	RecordNotify(time.Now(), false)
+	RecordGrpcRequest("buildUnSignTransaction", time.Now(), nil)
+	RecordDBCall("query", time.Millisecond, nil)
+
+	body := scrape(t)
+	assert.Contains(t, body, "synchronizer_lag 20")
+	assert.Contains(t, body, "synchronizer_blocks_processed 3")
+	assert.Contains(t, body, "finder_transactions_withdraw 2")
+	assert.Contains(t, body, "fallback_reorgs 1")
+	assert.Contains(t, body, "broadcast_withdraw_failure 1")
+	assert.Contains(t, body, "notifier_failures 1")
+	assert.Contains(t, body, "grpc_server_buildunsigntransaction_latency")
+	assert.Contains(t, body, "db_query_latency")
+}
+
+func TestSyncLagNeverNegative(t *testing.T) {
+	SetSyncLag(10, 12)
+	assert.Contains(t, scrape(t), "synchronizer_lag 0")
+}
+
+func TestServerDisabled(t *testing.T) {
+	server, err := StartServer("127.0.0.1", 0, nil)
+	require.NoError(t, err)
+	assert.Nil(t, server)
+	assert.NoError(t, server.Stop(context.Background()))
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+/*指标 HTTP 服务，GET /metrics 导出 Prometheus 文本格式*/
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+/*监听 host:port 并在后台提供服务，routes 为同端口上的其他路由（如健康检查），port 为 0 时不启动返回 nil*/
+func StartServer(host string, port int, routes map[string]http.Handler) (*Server, error) {
+	if port == 0 {
+		log.Info("metrics server disabled")
+		return nil, nil
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen metrics server: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	for pattern, handler := range routes {
+		mux.Handle(pattern, handler)
+	}
+	s := &Server{
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+		listener: listener,
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("metrics server stopped", "err", err)
+		}
+	}()
+	log.Info("metrics server started", "addr", listener.Addr())
+	return s, nil
+}
+
+/*指标导出 handler*/
+func Handler() http.Handler {
+	return prometheus.Handler(registry)
+}
+
+/*实际监听地址*/
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+/*关闭指标服务，未启动时直接返回*/
+func (s *Server) Stop(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
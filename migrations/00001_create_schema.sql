@@ -17,11 +17,54 @@ CREATE TABLE IF NOT EXISTS business
     guid         VARCHAR PRIMARY KEY,
     business_uid VARCHAR NOT NULL,
     notify_url   VARCHAR NOT NULL,
-    timestamp    INTEGER NOT NULL CHECK (timestamp > 0)
+    timestamp    INTEGER NOT NULL CHECK (timestamp > 0),
+    fee_ceiling  UINT256 NOT NULL DEFAULT 0
 );
 CREATE INDEX IF NOT EXISTS tokens_timestamp ON business (timestamp);
 CREATE UNIQUE INDEX IF NOT EXISTS business_uid ON business (business_uid);
 
+/*gRPC 接口限流：business_uid、method 为 * 表示所有项目方、所有接口，精确配置优先*/
+CREATE TABLE IF NOT EXISTS rate_limits
+(
+    guid         VARCHAR PRIMARY KEY,
+    business_uid VARCHAR          NOT NULL,
+    method       VARCHAR          NOT NULL,
+    rate         DOUBLE PRECISION NOT NULL CHECK (rate > 0),
+    burst        INTEGER          NOT NULL CHECK (burst > 0),
+    timestamp    BIGINT           NOT NULL CHECK (timestamp > 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS rate_limits_business_method ON rate_limits (business_uid, method);
+
+/*项目方每日配额（UTC 自然日），0 表示不限制；business_uid 为 * 表示默认配额*/
+CREATE TABLE IF NOT EXISTS business_quotas
+(
+    guid            VARCHAR PRIMARY KEY,
+    business_uid    VARCHAR NOT NULL,
+    daily_addresses BIGINT  NOT NULL DEFAULT 0,
+    daily_withdraws BIGINT  NOT NULL DEFAULT 0,
+    timestamp       BIGINT  NOT NULL CHECK (timestamp > 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS business_quotas_business_uid ON business_quotas (business_uid);
+
+/*项目方每日配额用量：按 用量 + 本次 <= 配额 条件递增，并发请求不会超出配额*/
+CREATE TABLE IF NOT EXISTS business_quota_usages
+(
+    business_uid VARCHAR NOT NULL,
+    day          BIGINT  NOT NULL,
+    addresses    BIGINT  NOT NULL DEFAULT 0,
+    withdraws    BIGINT  NOT NULL DEFAULT 0,
+    timestamp    BIGINT  NOT NULL CHECK (timestamp > 0),
+    PRIMARY KEY (business_uid, day)
+);
+
+/*查询接口缓存版本：worker 变更项目方数据时递增，版本变化即缓存失效*/
+CREATE TABLE IF NOT EXISTS cache_versions
+(
+    business_uid VARCHAR PRIMARY KEY,
+    version      BIGINT NOT NULL DEFAULT 0,
+    timestamp    BIGINT NOT NULL CHECK (timestamp > 0)
+);
+
 CREATE TABLE IF NOT EXISTS blocks
 (
     hash        VARCHAR PRIMARY KEY,
@@ -50,6 +93,7 @@ CREATE TABLE IF NOT EXISTS transactions
     hash          VARCHAR NOT NULL,
     from_address  VARCHAR NOT NULL,
     to_address    VARCHAR NOT NULL,
+    token_type    VARCHAR NOT NULL DEFAULT 'ETH',
     token_address VARCHAR NOT NULL,
     token_id      VARCHAR NOT NULL,
     token_meta    VARCHAR NOT NULL,
@@ -77,13 +121,14 @@ CREATE INDEX IF NOT EXISTS idx_addresses_address_type ON addresses (address_type
 
 CREATE TABLE IF NOT EXISTS tokens
 (
-    guid           VARCHAR PRIMARY KEY,
-    token_address  VARCHAR  NOT NULL,
-    decimals       SMALLINT NOT NULL DEFAULT 18,
-    token_name     VARCHAR  NOT NULL,
-    collect_amount UINT256  NOT NULL,
-    cold_amount    UINT256  NOT NULL,
-    timestamp      INTEGER  NOT NULL CHECK (timestamp > 0)
+    guid               VARCHAR PRIMARY KEY,
+    token_address      VARCHAR  NOT NULL,
+    decimals           SMALLINT NOT NULL DEFAULT 18,
+    token_name         VARCHAR  NOT NULL,
+    collect_amount     UINT256  NOT NULL,
+    cold_amount        UINT256  NOT NULL,
+    min_deposit_amount UINT256  NOT NULL DEFAULT 0,
+    timestamp          INTEGER  NOT NULL CHECK (timestamp > 0)
 );
 CREATE INDEX IF NOT EXISTS tokens_timestamp ON tokens (timestamp);
 CREATE INDEX IF NOT EXISTS tokens_token_address ON tokens (token_address);
@@ -104,6 +149,21 @@ CREATE INDEX IF NOT EXISTS idx_balances_address ON balances (address);
 CREATE INDEX IF NOT EXISTS idx_balances_token_address ON balances (token_address);
 CREATE INDEX IF NOT EXISTS idx_balances_address_type ON balances (address_type);
 
+CREATE TABLE IF NOT EXISTS nft_holdings
+(
+    guid          VARCHAR PRIMARY KEY,
+    address       VARCHAR NOT NULL,
+    token_address VARCHAR NOT NULL,
+    token_id      VARCHAR NOT NULL,
+    token_type    VARCHAR NOT NULL,
+    amount        UINT256 NOT NULL DEFAULT 0,
+    timestamp     BIGINT  NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0),
+    CONSTRAINT check_token_type CHECK (token_type IN ('ERC721', 'ERC1155'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_nft_holdings_address_token ON nft_holdings (address, token_address, token_id);
+CREATE INDEX IF NOT EXISTS idx_nft_holdings_token_address ON nft_holdings (token_address);
+
 CREATE TABLE IF NOT EXISTS deposits
 (
     guid                     VARCHAR PRIMARY KEY,
@@ -119,6 +179,7 @@ CREATE TABLE IF NOT EXISTS deposits
     from_address             VARCHAR  NOT NULL,
     to_address               VARCHAR  NOT NULL,
     amount                   UINT256  NOT NULL,
+    fee                      UINT256  NOT NULL DEFAULT 0,
 
     gas_limit                INTEGER  NOT NULL,
     max_fee_per_gas          VARCHAR  NOT NULL,
@@ -129,7 +190,10 @@ CREATE TABLE IF NOT EXISTS deposits
     token_id                 VARCHAR  NOT NULL,
     token_meta               VARCHAR  NOT NULL,
 
-    tx_sign_hex              VARCHAR  NOT NULL
+    tx_sign_hex              VARCHAR  NOT NULL,
+    risk_reason              VARCHAR  NOT NULL DEFAULT '',
+    risk_score               INTEGER  NOT NULL DEFAULT 0,
+    risk_action              VARCHAR  NOT NULL DEFAULT ''
 );
 CREATE INDEX IF NOT EXISTS deposits_hash ON deposits (hash);
 CREATE INDEX IF NOT EXISTS deposits_timestamp ON deposits (timestamp);
@@ -160,10 +224,15 @@ CREATE TABLE IF NOT EXISTS withdraws
     token_id                 VARCHAR NOT NULL,
     token_meta               VARCHAR NOT NULL,
 
-    tx_sign_hex              VARCHAR NOT NULL
+    tx_sign_hex              VARCHAR NOT NULL,
+    risk_reason              VARCHAR NOT NULL DEFAULT '',
+    risk_score               INTEGER NOT NULL DEFAULT 0,
+    risk_action              VARCHAR NOT NULL DEFAULT '',
+    batch_id                 VARCHAR NOT NULL DEFAULT ''
 );
 
 CREATE INDEX IF NOT EXISTS withdraws_hash ON withdraws (hash);
+CREATE INDEX IF NOT EXISTS withdraws_batch_id ON withdraws (batch_id);
 CREATE INDEX IF NOT EXISTS withdraws_timestamp ON withdraws (timestamp);
 CREATE INDEX IF NOT EXISTS withdraws_from_address ON withdraws (from_address);
 CREATE INDEX IF NOT EXISTS withdraws_to_address ON withdraws (to_address);
@@ -192,7 +261,8 @@ CREATE TABLE IF NOT EXISTS internals
     token_id                 VARCHAR NOT NULL,
     token_meta               VARCHAR NOT NULL,
 
-    tx_sign_hex              VARCHAR NOT NULL
+    tx_sign_hex              VARCHAR NOT NULL,
+    gas_funding_guid         VARCHAR NOT NULL DEFAULT ''
 );
 
 CREATE INDEX IF NOT EXISTS internals_hash ON internals (hash);
@@ -200,3 +270,110 @@ CREATE INDEX IF NOT EXISTS internals_timestamp ON internals (timestamp);
 CREATE INDEX IF NOT EXISTS internals_from_address ON internals (from_address);
 CREATE INDEX IF NOT EXISTS internals_to_address ON internals (to_address);
 
+CREATE TABLE IF NOT EXISTS reconciliations
+(
+    guid          VARCHAR PRIMARY KEY,
+    round_id      VARCHAR NOT NULL,
+    address       VARCHAR NOT NULL,
+    address_type  VARCHAR NOT NULL,
+    token_address VARCHAR NOT NULL,
+    db_balance    UINT256 NOT NULL,
+    chain_balance UINT256 NOT NULL,
+    difference    VARCHAR NOT NULL,
+    block_number  UINT256 NOT NULL,
+    timestamp     BIGINT  NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_round_id ON reconciliations (round_id);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_address ON reconciliations (address);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_timestamp ON reconciliations (timestamp);
+
+CREATE TABLE IF NOT EXISTS ledger
+(
+    guid          VARCHAR PRIMARY KEY,
+    journal_id    VARCHAR NOT NULL,
+    event         VARCHAR NOT NULL,
+    tx_type       VARCHAR NOT NULL,
+    tx_hash       VARCHAR NOT NULL,
+    direction     VARCHAR NOT NULL,
+    address       VARCHAR NOT NULL,
+    address_type  VARCHAR NOT NULL,
+    bucket        VARCHAR NOT NULL,
+    token_address VARCHAR NOT NULL,
+    amount        UINT256 NOT NULL,
+    timestamp     BIGINT  NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0),
+    CONSTRAINT check_direction CHECK (direction IN ('debit', 'credit')),
+    CONSTRAINT check_bucket CHECK (bucket IN ('available', 'locked'))
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_address_token ON ledger (address, token_address);
+CREATE INDEX IF NOT EXISTS idx_ledger_journal_id ON ledger (journal_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_tx_hash ON ledger (tx_hash);
+
+CREATE TABLE IF NOT EXISTS balance_snapshots
+(
+    guid          VARCHAR PRIMARY KEY,
+    block_number  UINT256 NOT NULL,
+    snapshot_time BIGINT  NOT NULL,
+    address       VARCHAR NOT NULL,
+    address_type  VARCHAR NOT NULL,
+    token_address VARCHAR NOT NULL,
+    balance       UINT256 NOT NULL DEFAULT 0,
+    lock_balance  UINT256 NOT NULL DEFAULT 0,
+    timestamp     BIGINT  NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_address_token_block ON balance_snapshots (address, token_address, block_number);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_block_number ON balance_snapshots (block_number);
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_snapshot_time ON balance_snapshots (snapshot_time);
+
+CREATE TABLE IF NOT EXISTS fees
+(
+    guid          VARCHAR PRIMARY KEY,
+    tx_hash       VARCHAR NOT NULL,
+    tx_type       VARCHAR NOT NULL,
+    address       VARCHAR NOT NULL,
+    address_type  VARCHAR NOT NULL,
+    token_address VARCHAR NOT NULL,
+    fee           UINT256 NOT NULL,
+    block_number  UINT256 NOT NULL,
+    status        VARCHAR NOT NULL,
+    timestamp     BIGINT  NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_fees_tx_hash ON fees (tx_hash);
+CREATE INDEX IF NOT EXISTS idx_fees_block_number ON fees (block_number);
+CREATE INDEX IF NOT EXISTS idx_fees_timestamp ON fees (timestamp);
+
+CREATE TABLE IF NOT EXISTS withdraw_batches
+(
+    guid                     VARCHAR PRIMARY KEY,
+    timestamp                BIGINT  NOT NULL,
+    status                   VARCHAR NOT NULL,
+    hash                     VARCHAR NOT NULL,
+    from_address             VARCHAR NOT NULL,
+    disperse_address         VARCHAR NOT NULL,
+    token_type               VARCHAR NOT NULL,
+    token_address            VARCHAR NOT NULL,
+    total_amount             UINT256 NOT NULL,
+    payout_count             INTEGER NOT NULL,
+    gas_limit                INTEGER NOT NULL,
+    max_fee_per_gas          VARCHAR NOT NULL,
+    max_priority_fee_per_gas VARCHAR NOT NULL,
+    tx_sign_hex              VARCHAR NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_withdraw_batches_hash ON withdraw_batches (hash);
+CREATE INDEX IF NOT EXISTS idx_withdraw_batches_status ON withdraw_batches (status);
+
+CREATE TABLE IF NOT EXISTS proof_of_reserves
+(
+    guid         VARCHAR PRIMARY KEY,
+    block_number UINT256 NOT NULL,
+    merkle_root  VARCHAR NOT NULL,
+    report       TEXT    NOT NULL,
+    timestamp    BIGINT  NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_proof_of_reserves_block_number ON proof_of_reserves (block_number);
+
@@ -26,6 +26,18 @@ type ReturnCode int32
 const (
 	ReturnCode_ERROR   ReturnCode = 0
 	ReturnCode_SUCCESS ReturnCode = 1
+	//地址筛查或风险评分命中，交易已挂起
+	ReturnCode_RISK_HOLD ReturnCode = 2
+	//风险评分拒绝，交易不予签名
+	ReturnCode_RISK_REJECT ReturnCode = 3
+	//地址格式或校验和错误
+	ReturnCode_INVALID_ADDRESS ReturnCode = 4
+	//目标地址为零地址
+	ReturnCode_ZERO_ADDRESS ReturnCode = 5
+	//提现目标为本项目方钱包地址（应走内部交易）
+	ReturnCode_OWN_ADDRESS ReturnCode = 6
+	//提现目标为代币合约地址
+	ReturnCode_CONTRACT_ADDRESS ReturnCode = 7
 )
 
 // Enum value maps for ReturnCode.
@@ -33,10 +45,22 @@ var (
 	ReturnCode_name = map[int32]string{
 		0: "ERROR",
 		1: "SUCCESS",
+		2: "RISK_HOLD",
+		3: "RISK_REJECT",
+		4: "INVALID_ADDRESS",
+		5: "ZERO_ADDRESS",
+		6: "OWN_ADDRESS",
+		7: "CONTRACT_ADDRESS",
 	}
 	ReturnCode_value = map[string]int32{
-		"ERROR":   0,
-		"SUCCESS": 1,
+		"ERROR":            0,
+		"SUCCESS":          1,
+		"RISK_HOLD":        2,
+		"RISK_REJECT":      3,
+		"INVALID_ADDRESS":  4,
+		"ZERO_ADDRESS":     5,
+		"OWN_ADDRESS":      6,
+		"CONTRACT_ADDRESS": 7,
 	}
 )
 
@@ -67,6 +91,56 @@ func (ReturnCode) EnumDescriptor() ([]byte, []int) {
 	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{0}
 }
 
+// 隔离充值处理方式
+type QuarantineAction int32
+
+const (
+	QuarantineAction_UNKNOWN_ACTION QuarantineAction = 0
+	QuarantineAction_ACCEPT         QuarantineAction = 1
+	QuarantineAction_IGNORE         QuarantineAction = 2
+)
+
+// Enum value maps for QuarantineAction.
+var (
+	QuarantineAction_name = map[int32]string{
+		0: "UNKNOWN_ACTION",
+		1: "ACCEPT",
+		2: "IGNORE",
+	}
+	QuarantineAction_value = map[string]int32{
+		"UNKNOWN_ACTION": 0,
+		"ACCEPT":         1,
+		"IGNORE":         2,
+	}
+)
+
+func (x QuarantineAction) Enum() *QuarantineAction {
+	p := new(QuarantineAction)
+	*p = x
+	return p
+}
+
+func (x QuarantineAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QuarantineAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_protobuf_exchange_wallet_proto_enumTypes[1].Descriptor()
+}
+
+func (QuarantineAction) Type() protoreflect.EnumType {
+	return &file_protobuf_exchange_wallet_proto_enumTypes[1]
+}
+
+func (x QuarantineAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QuarantineAction.Descriptor instead.
+func (QuarantineAction) EnumDescriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{1}
+}
+
 // EOA 账户热钱包公钥
 type PublicKey struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -181,8 +255,10 @@ type Token struct {
 	TokenName     string                 `protobuf:"bytes,3,opt,name=token_name,json=tokenName,proto3" json:"token_name,omitempty"`
 	CollectAmount string                 `protobuf:"bytes,4,opt,name=collect_amount,json=collectAmount,proto3" json:"collect_amount,omitempty"`
 	ColdAmount    string                 `protobuf:"bytes,5,opt,name=cold_amount,json=coldAmount,proto3" json:"cold_amount,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	//最小充值金额，为空则不限制
+	MinDepositAmount string `protobuf:"bytes,6,opt,name=min_deposit_amount,json=minDepositAmount,proto3" json:"min_deposit_amount,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Token) Reset() {
@@ -250,12 +326,21 @@ func (x *Token) GetColdAmount() string {
 	return ""
 }
 
+func (x *Token) GetMinDepositAmount() string {
+	if x != nil {
+		return x.MinDepositAmount
+	}
+	return ""
+}
+
 // 项目方注册请求
 type BusinessRegisterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
 	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	NotifyUrl     string                 `protobuf:"bytes,3,opt,name=notify_url,json=notifyUrl,proto3" json:"notify_url,omitempty"`
+	//每单位 gas 最高价格（wei），为空或 0 不限制
+	FeeCeiling    string `protobuf:"bytes,4,opt,name=fee_ceiling,json=feeCeiling,proto3" json:"fee_ceiling,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -311,6 +396,13 @@ func (x *BusinessRegisterRequest) GetNotifyUrl() string {
 	return ""
 }
 
+func (x *BusinessRegisterRequest) GetFeeCeiling() string {
+	if x != nil {
+		return x.FeeCeiling
+	}
+	return ""
+}
+
 // 项目方注册响应
 type BusinessRegisterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -500,8 +592,17 @@ type UnSignTransactionRequest struct {
 	TokenId         string                 `protobuf:"bytes,9,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
 	TokenMeta       string                 `protobuf:"bytes,10,opt,name=token_meta,json=tokenMeta,proto3" json:"token_meta,omitempty"`
 	TxType          string                 `protobuf:"bytes,11,opt,name=tx_type,json=txType,proto3" json:"tx_type,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	//代币类型：ETH/ERC20/ERC721/ERC1155，为空时按 contract_address 区分 ETH 与 ERC20
+	TokenType string `protobuf:"bytes,12,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	//批量提现：非空时忽略 to、value，同一代币的多笔出款合并为一笔 disperse 合约调用
+	Payouts []*BatchPayout `protobuf:"bytes,13,rep,name=payouts,proto3" json:"payouts,omitempty"`
+	//费率档位：slow/normal/fast/custom，为空为 fast
+	FeeLevel string `protobuf:"bytes,14,opt,name=fee_level,json=feeLevel,proto3" json:"fee_level,omitempty"`
+	//custom 档位指定的费率（wei），legacy 定价只取 max_fee_per_gas 作为 gasPrice
+	MaxFeePerGas         string `protobuf:"bytes,15,opt,name=max_fee_per_gas,json=maxFeePerGas,proto3" json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `protobuf:"bytes,16,opt,name=max_priority_fee_per_gas,json=maxPriorityFeePerGas,proto3" json:"max_priority_fee_per_gas,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *UnSignTransactionRequest) Reset() {
@@ -611,31 +712,64 @@ func (x *UnSignTransactionRequest) GetTxType() string {
 	return ""
 }
 
-// 未签名交易响应
-type UnSignTransactionResponse struct {
+func (x *UnSignTransactionRequest) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *UnSignTransactionRequest) GetPayouts() []*BatchPayout {
+	if x != nil {
+		return x.Payouts
+	}
+	return nil
+}
+
+func (x *UnSignTransactionRequest) GetFeeLevel() string {
+	if x != nil {
+		return x.FeeLevel
+	}
+	return ""
+}
+
+func (x *UnSignTransactionRequest) GetMaxFeePerGas() string {
+	if x != nil {
+		return x.MaxFeePerGas
+	}
+	return ""
+}
+
+func (x *UnSignTransactionRequest) GetMaxPriorityFeePerGas() string {
+	if x != nil {
+		return x.MaxPriorityFeePerGas
+	}
+	return ""
+}
+
+// 批量提现单笔出款
+type BatchPayout struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
-	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
-	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	UnSignTx      string                 `protobuf:"bytes,4,opt,name=un_sign_tx,json=unSignTx,proto3" json:"un_sign_tx,omitempty"`
+	To            string                 `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnSignTransactionResponse) Reset() {
-	*x = UnSignTransactionResponse{}
+func (x *BatchPayout) Reset() {
+	*x = BatchPayout{}
 	mi := &file_protobuf_exchange_wallet_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnSignTransactionResponse) String() string {
+func (x *BatchPayout) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnSignTransactionResponse) ProtoMessage() {}
+func (*BatchPayout) ProtoMessage() {}
 
-func (x *UnSignTransactionResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchPayout) ProtoReflect() protoreflect.Message {
 	mi := &file_protobuf_exchange_wallet_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -647,67 +781,51 @@ func (x *UnSignTransactionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnSignTransactionResponse.ProtoReflect.Descriptor instead.
-func (*UnSignTransactionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchPayout.ProtoReflect.Descriptor instead.
+func (*BatchPayout) Descriptor() ([]byte, []int) {
 	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *UnSignTransactionResponse) GetCode() ReturnCode {
-	if x != nil {
-		return x.Code
-	}
-	return ReturnCode_ERROR
-}
-
-func (x *UnSignTransactionResponse) GetMsg() string {
-	if x != nil {
-		return x.Msg
-	}
-	return ""
-}
-
-func (x *UnSignTransactionResponse) GetTransactionId() string {
+func (x *BatchPayout) GetTo() string {
 	if x != nil {
-		return x.TransactionId
+		return x.To
 	}
 	return ""
 }
 
-func (x *UnSignTransactionResponse) GetUnSignTx() string {
+func (x *BatchPayout) GetValue() string {
 	if x != nil {
-		return x.UnSignTx
+		return x.Value
 	}
 	return ""
 }
 
-// 已签名交易请求
-type SignedTransactionRequest struct {
+// 批量提现单笔出款结果，挂起或拒绝的出款不进入批次
+type BatchPayoutResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
-	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
-	Chain         string                 `protobuf:"bytes,3,opt,name=chain,proto3" json:"chain,omitempty"`
-	ChainId       string                 `protobuf:"bytes,4,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
-	TransactionId string                 `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	Signature     string                 `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
-	TxType        string                 `protobuf:"bytes,7,opt,name=tx_type,json=txType,proto3" json:"tx_type,omitempty"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Code          ReturnCode             `protobuf:"varint,4,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	RiskReason    string                 `protobuf:"bytes,5,opt,name=risk_reason,json=riskReason,proto3" json:"risk_reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SignedTransactionRequest) Reset() {
-	*x = SignedTransactionRequest{}
+func (x *BatchPayoutResult) Reset() {
+	*x = BatchPayoutResult{}
 	mi := &file_protobuf_exchange_wallet_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SignedTransactionRequest) String() string {
+func (x *BatchPayoutResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignedTransactionRequest) ProtoMessage() {}
+func (*BatchPayoutResult) ProtoMessage() {}
 
-func (x *SignedTransactionRequest) ProtoReflect() protoreflect.Message {
+func (x *BatchPayoutResult) ProtoReflect() protoreflect.Message {
 	mi := &file_protobuf_exchange_wallet_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -719,84 +837,83 @@ func (x *SignedTransactionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignedTransactionRequest.ProtoReflect.Descriptor instead.
-func (*SignedTransactionRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchPayoutResult.ProtoReflect.Descriptor instead.
+func (*BatchPayoutResult) Descriptor() ([]byte, []int) {
 	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SignedTransactionRequest) GetConsumerToken() string {
-	if x != nil {
-		return x.ConsumerToken
-	}
-	return ""
-}
-
-func (x *SignedTransactionRequest) GetRequestId() string {
+func (x *BatchPayoutResult) GetTransactionId() string {
 	if x != nil {
-		return x.RequestId
-	}
-	return ""
-}
-
-func (x *SignedTransactionRequest) GetChain() string {
-	if x != nil {
-		return x.Chain
+		return x.TransactionId
 	}
 	return ""
 }
 
-func (x *SignedTransactionRequest) GetChainId() string {
+func (x *BatchPayoutResult) GetTo() string {
 	if x != nil {
-		return x.ChainId
+		return x.To
 	}
 	return ""
 }
 
-func (x *SignedTransactionRequest) GetTransactionId() string {
+func (x *BatchPayoutResult) GetValue() string {
 	if x != nil {
-		return x.TransactionId
+		return x.Value
 	}
 	return ""
 }
 
-func (x *SignedTransactionRequest) GetSignature() string {
+func (x *BatchPayoutResult) GetCode() ReturnCode {
 	if x != nil {
-		return x.Signature
+		return x.Code
 	}
-	return ""
+	return ReturnCode_ERROR
 }
 
-func (x *SignedTransactionRequest) GetTxType() string {
+func (x *BatchPayoutResult) GetRiskReason() string {
 	if x != nil {
-		return x.TxType
+		return x.RiskReason
 	}
 	return ""
 }
 
-// 已签名交易响应
-type SignedTransactionResponse struct {
+// 未签名交易响应
+type UnSignTransactionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
 	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
-	SignedTx      string                 `protobuf:"bytes,3,opt,name=signed_tx,json=signedTx,proto3" json:"signed_tx,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	UnSignTx      string                 `protobuf:"bytes,4,opt,name=un_sign_tx,json=unSignTx,proto3" json:"un_sign_tx,omitempty"`
+	//地址筛查或风险评分命中原因
+	RiskReason string `protobuf:"bytes,5,opt,name=risk_reason,json=riskReason,proto3" json:"risk_reason,omitempty"`
+	//代币归集时用户地址主币不足以支付 gas，需先签名发送的 gas 补充交易（热钱包 -> 用户地址）
+	GasFundingTransactionId string `protobuf:"bytes,6,opt,name=gas_funding_transaction_id,json=gasFundingTransactionId,proto3" json:"gas_funding_transaction_id,omitempty"`
+	GasFundingUnSignTx      string `protobuf:"bytes,7,opt,name=gas_funding_un_sign_tx,json=gasFundingUnSignTx,proto3" json:"gas_funding_un_sign_tx,omitempty"`
+	//批量提现批次 id，签名时传入 batch_id
+	BatchId string               `protobuf:"bytes,8,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Payouts []*BatchPayoutResult `protobuf:"bytes,9,rep,name=payouts,proto3" json:"payouts,omitempty"`
+	//实际使用的 gas 定价与 gasLimit
+	MaxFeePerGas         string `protobuf:"bytes,10,opt,name=max_fee_per_gas,json=maxFeePerGas,proto3" json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `protobuf:"bytes,11,opt,name=max_priority_fee_per_gas,json=maxPriorityFeePerGas,proto3" json:"max_priority_fee_per_gas,omitempty"`
+	GasLimit             uint64 `protobuf:"varint,12,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
-func (x *SignedTransactionResponse) Reset() {
-	*x = SignedTransactionResponse{}
+func (x *UnSignTransactionResponse) Reset() {
+	*x = UnSignTransactionResponse{}
 	mi := &file_protobuf_exchange_wallet_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SignedTransactionResponse) String() string {
+func (x *UnSignTransactionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignedTransactionResponse) ProtoMessage() {}
+func (*UnSignTransactionResponse) ProtoMessage() {}
 
-func (x *SignedTransactionResponse) ProtoReflect() protoreflect.Message {
+func (x *UnSignTransactionResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_protobuf_exchange_wallet_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -808,107 +925,130 @@ func (x *SignedTransactionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignedTransactionResponse.ProtoReflect.Descriptor instead.
-func (*SignedTransactionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UnSignTransactionResponse.ProtoReflect.Descriptor instead.
+func (*UnSignTransactionResponse) Descriptor() ([]byte, []int) {
 	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *SignedTransactionResponse) GetCode() ReturnCode {
+func (x *UnSignTransactionResponse) GetCode() ReturnCode {
 	if x != nil {
 		return x.Code
 	}
 	return ReturnCode_ERROR
 }
 
-func (x *SignedTransactionResponse) GetMsg() string {
+func (x *UnSignTransactionResponse) GetMsg() string {
 	if x != nil {
 		return x.Msg
 	}
 	return ""
 }
 
-func (x *SignedTransactionResponse) GetSignedTx() string {
+func (x *UnSignTransactionResponse) GetTransactionId() string {
 	if x != nil {
-		return x.SignedTx
+		return x.TransactionId
 	}
 	return ""
 }
 
-type SetTokenAddressRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
-	TokenList     []*Token               `protobuf:"bytes,2,rep,name=token_list,json=tokenList,proto3" json:"token_list,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *UnSignTransactionResponse) GetUnSignTx() string {
+	if x != nil {
+		return x.UnSignTx
+	}
+	return ""
 }
 
-func (x *SetTokenAddressRequest) Reset() {
-	*x = SetTokenAddressRequest{}
-	mi := &file_protobuf_exchange_wallet_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *UnSignTransactionResponse) GetRiskReason() string {
+	if x != nil {
+		return x.RiskReason
+	}
+	return ""
 }
 
-func (x *SetTokenAddressRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *UnSignTransactionResponse) GetGasFundingTransactionId() string {
+	if x != nil {
+		return x.GasFundingTransactionId
+	}
+	return ""
 }
 
-func (*SetTokenAddressRequest) ProtoMessage() {}
+func (x *UnSignTransactionResponse) GetGasFundingUnSignTx() string {
+	if x != nil {
+		return x.GasFundingUnSignTx
+	}
+	return ""
+}
 
-func (x *SetTokenAddressRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_exchange_wallet_proto_msgTypes[11]
+func (x *UnSignTransactionResponse) GetBatchId() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.BatchId
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use SetTokenAddressRequest.ProtoReflect.Descriptor instead.
-func (*SetTokenAddressRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{11}
+func (x *UnSignTransactionResponse) GetPayouts() []*BatchPayoutResult {
+	if x != nil {
+		return x.Payouts
+	}
+	return nil
 }
 
-func (x *SetTokenAddressRequest) GetRequestId() string {
+func (x *UnSignTransactionResponse) GetMaxFeePerGas() string {
 	if x != nil {
-		return x.RequestId
+		return x.MaxFeePerGas
 	}
 	return ""
 }
 
-func (x *SetTokenAddressRequest) GetTokenList() []*Token {
+func (x *UnSignTransactionResponse) GetMaxPriorityFeePerGas() string {
 	if x != nil {
-		return x.TokenList
+		return x.MaxPriorityFeePerGas
 	}
-	return nil
+	return ""
 }
 
-type SetTokenAddressResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
-	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *UnSignTransactionResponse) GetGasLimit() uint64 {
+	if x != nil {
+		return x.GasLimit
+	}
+	return 0
 }
 
-func (x *SetTokenAddressResponse) Reset() {
-	*x = SetTokenAddressResponse{}
-	mi := &file_protobuf_exchange_wallet_proto_msgTypes[12]
+// 手续费预估请求：与构建未签名交易相同的定价与 gasLimit 逻辑，不落库
+type EstimateFeeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken   string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId       string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	From            string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To              string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Value           string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	ContractAddress string                 `protobuf:"bytes,6,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	TokenId         string                 `protobuf:"bytes,7,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	//代币类型：ETH/ERC20/ERC721/ERC1155，为空时按 contract_address 区分 ETH 与 ERC20
+	TokenType string `protobuf:"bytes,8,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	//费率档位：slow/normal/fast/custom，为空为 fast
+	FeeLevel             string `protobuf:"bytes,9,opt,name=fee_level,json=feeLevel,proto3" json:"fee_level,omitempty"`
+	MaxFeePerGas         string `protobuf:"bytes,10,opt,name=max_fee_per_gas,json=maxFeePerGas,proto3" json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `protobuf:"bytes,11,opt,name=max_priority_fee_per_gas,json=maxPriorityFeePerGas,proto3" json:"max_priority_fee_per_gas,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *EstimateFeeRequest) Reset() {
+	*x = EstimateFeeRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetTokenAddressResponse) String() string {
+func (x *EstimateFeeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetTokenAddressResponse) ProtoMessage() {}
+func (*EstimateFeeRequest) ProtoMessage() {}
 
-func (x *SetTokenAddressResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_exchange_wallet_proto_msgTypes[12]
+func (x *EstimateFeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -919,25 +1059,1507 @@ func (x *SetTokenAddressResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetTokenAddressResponse.ProtoReflect.Descriptor instead.
-func (*SetTokenAddressResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use EstimateFeeRequest.ProtoReflect.Descriptor instead.
+func (*EstimateFeeRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *SetTokenAddressResponse) GetCode() ReturnCode {
+func (x *EstimateFeeRequest) GetConsumerToken() string {
 	if x != nil {
-		return x.Code
+		return x.ConsumerToken
 	}
-	return ReturnCode_ERROR
+	return ""
 }
 
-func (x *SetTokenAddressResponse) GetMsg() string {
+func (x *EstimateFeeRequest) GetRequestId() string {
 	if x != nil {
-		return x.Msg
+		return x.RequestId
 	}
 	return ""
 }
 
+func (x *EstimateFeeRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetContractAddress() string {
+	if x != nil {
+		return x.ContractAddress
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetFeeLevel() string {
+	if x != nil {
+		return x.FeeLevel
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetMaxFeePerGas() string {
+	if x != nil {
+		return x.MaxFeePerGas
+	}
+	return ""
+}
+
+func (x *EstimateFeeRequest) GetMaxPriorityFeePerGas() string {
+	if x != nil {
+		return x.MaxPriorityFeePerGas
+	}
+	return ""
+}
+
+// 手续费预估响应：total_fee = gas_limit * max_fee_per_gas，为主币最小单位的最高花费
+type EstimateFeeResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Code                 ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg                  string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	GasLimit             uint64                 `protobuf:"varint,3,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	MaxFeePerGas         string                 `protobuf:"bytes,4,opt,name=max_fee_per_gas,json=maxFeePerGas,proto3" json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string                 `protobuf:"bytes,5,opt,name=max_priority_fee_per_gas,json=maxPriorityFeePerGas,proto3" json:"max_priority_fee_per_gas,omitempty"`
+	TotalFee             string                 `protobuf:"bytes,6,opt,name=total_fee,json=totalFee,proto3" json:"total_fee,omitempty"`
+	FeeLevel             string                 `protobuf:"bytes,7,opt,name=fee_level,json=feeLevel,proto3" json:"fee_level,omitempty"`
+	Legacy               bool                   `protobuf:"varint,8,opt,name=legacy,proto3" json:"legacy,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *EstimateFeeResponse) Reset() {
+	*x = EstimateFeeResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateFeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateFeeResponse) ProtoMessage() {}
+
+func (x *EstimateFeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateFeeResponse.ProtoReflect.Descriptor instead.
+func (*EstimateFeeResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EstimateFeeResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *EstimateFeeResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *EstimateFeeResponse) GetGasLimit() uint64 {
+	if x != nil {
+		return x.GasLimit
+	}
+	return 0
+}
+
+func (x *EstimateFeeResponse) GetMaxFeePerGas() string {
+	if x != nil {
+		return x.MaxFeePerGas
+	}
+	return ""
+}
+
+func (x *EstimateFeeResponse) GetMaxPriorityFeePerGas() string {
+	if x != nil {
+		return x.MaxPriorityFeePerGas
+	}
+	return ""
+}
+
+func (x *EstimateFeeResponse) GetTotalFee() string {
+	if x != nil {
+		return x.TotalFee
+	}
+	return ""
+}
+
+func (x *EstimateFeeResponse) GetFeeLevel() string {
+	if x != nil {
+		return x.FeeLevel
+	}
+	return ""
+}
+
+func (x *EstimateFeeResponse) GetLegacy() bool {
+	if x != nil {
+		return x.Legacy
+	}
+	return false
+}
+
+// 已签名交易请求
+type SignedTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Chain         string                 `protobuf:"bytes,3,opt,name=chain,proto3" json:"chain,omitempty"`
+	ChainId       string                 `protobuf:"bytes,4,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	TransactionId string                 `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Signature     string                 `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	TxType        string                 `protobuf:"bytes,7,opt,name=tx_type,json=txType,proto3" json:"tx_type,omitempty"`
+	//批量提现批次 id，非空时签名整个批次交易
+	BatchId       string `protobuf:"bytes,8,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedTransactionRequest) Reset() {
+	*x = SignedTransactionRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedTransactionRequest) ProtoMessage() {}
+
+func (x *SignedTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedTransactionRequest.ProtoReflect.Descriptor instead.
+func (*SignedTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SignedTransactionRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetChainId() string {
+	if x != nil {
+		return x.ChainId
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetTxType() string {
+	if x != nil {
+		return x.TxType
+	}
+	return ""
+}
+
+func (x *SignedTransactionRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+// 已签名交易响应
+type SignedTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	SignedTx      string                 `protobuf:"bytes,3,opt,name=signed_tx,json=signedTx,proto3" json:"signed_tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedTransactionResponse) Reset() {
+	*x = SignedTransactionResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedTransactionResponse) ProtoMessage() {}
+
+func (x *SignedTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedTransactionResponse.ProtoReflect.Descriptor instead.
+func (*SignedTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SignedTransactionResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *SignedTransactionResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *SignedTransactionResponse) GetSignedTx() string {
+	if x != nil {
+		return x.SignedTx
+	}
+	return ""
+}
+
+type SetTokenAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	TokenList     []*Token               `protobuf:"bytes,2,rep,name=token_list,json=tokenList,proto3" json:"token_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTokenAddressRequest) Reset() {
+	*x = SetTokenAddressRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTokenAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTokenAddressRequest) ProtoMessage() {}
+
+func (x *SetTokenAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTokenAddressRequest.ProtoReflect.Descriptor instead.
+func (*SetTokenAddressRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetTokenAddressRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SetTokenAddressRequest) GetTokenList() []*Token {
+	if x != nil {
+		return x.TokenList
+	}
+	return nil
+}
+
+type SetTokenAddressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTokenAddressResponse) Reset() {
+	*x = SetTokenAddressResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTokenAddressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTokenAddressResponse) ProtoMessage() {}
+
+func (x *SetTokenAddressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTokenAddressResponse.ProtoReflect.Descriptor instead.
+func (*SetTokenAddressResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetTokenAddressResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *SetTokenAddressResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 设置项目方 gas 费率上限
+type SetFeeCeilingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	//每单位 gas 最高价格（wei），0 不限制
+	FeeCeiling    string `protobuf:"bytes,3,opt,name=fee_ceiling,json=feeCeiling,proto3" json:"fee_ceiling,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFeeCeilingRequest) Reset() {
+	*x = SetFeeCeilingRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeeCeilingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeeCeilingRequest) ProtoMessage() {}
+
+func (x *SetFeeCeilingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFeeCeilingRequest.ProtoReflect.Descriptor instead.
+func (*SetFeeCeilingRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetFeeCeilingRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *SetFeeCeilingRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SetFeeCeilingRequest) GetFeeCeiling() string {
+	if x != nil {
+		return x.FeeCeiling
+	}
+	return ""
+}
+
+type SetFeeCeilingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFeeCeilingResponse) Reset() {
+	*x = SetFeeCeilingResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFeeCeilingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFeeCeilingResponse) ProtoMessage() {}
+
+func (x *SetFeeCeilingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFeeCeilingResponse.ProtoReflect.Descriptor instead.
+func (*SetFeeCeilingResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetFeeCeilingResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *SetFeeCeilingResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 隔离充值（非白名单代币）
+type QuarantineDeposit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	TxHash        string                 `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	BlockNumber   string                 `protobuf:"bytes,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	From          string                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	TokenType     string                 `protobuf:"bytes,6,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	TokenAddress  string                 `protobuf:"bytes,7,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	TokenId       string                 `protobuf:"bytes,8,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Amount        string                 `protobuf:"bytes,9,opt,name=amount,proto3" json:"amount,omitempty"`
+	Timestamp     uint64                 `protobuf:"varint,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantineDeposit) Reset() {
+	*x = QuarantineDeposit{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantineDeposit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineDeposit) ProtoMessage() {}
+
+func (x *QuarantineDeposit) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineDeposit.ProtoReflect.Descriptor instead.
+func (*QuarantineDeposit) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *QuarantineDeposit) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetBlockNumber() string {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *QuarantineDeposit) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// 隔离充值列表请求
+type QuarantineDepositsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantineDepositsRequest) Reset() {
+	*x = QuarantineDepositsRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantineDepositsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineDepositsRequest) ProtoMessage() {}
+
+func (x *QuarantineDepositsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineDepositsRequest.ProtoReflect.Descriptor instead.
+func (*QuarantineDepositsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *QuarantineDepositsRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *QuarantineDepositsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// 隔离充值列表响应
+type QuarantineDepositsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Deposits      []*QuarantineDeposit   `protobuf:"bytes,3,rep,name=deposits,proto3" json:"deposits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantineDepositsResponse) Reset() {
+	*x = QuarantineDepositsResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantineDepositsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantineDepositsResponse) ProtoMessage() {}
+
+func (x *QuarantineDepositsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantineDepositsResponse.ProtoReflect.Descriptor instead.
+func (*QuarantineDepositsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *QuarantineDepositsResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *QuarantineDepositsResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *QuarantineDepositsResponse) GetDeposits() []*QuarantineDeposit {
+	if x != nil {
+		return x.Deposits
+	}
+	return nil
+}
+
+// 处理隔离充值请求
+type HandleQuarantineDepositRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Action        QuarantineAction       `protobuf:"varint,4,opt,name=action,proto3,enum=syncs.QuarantineAction" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleQuarantineDepositRequest) Reset() {
+	*x = HandleQuarantineDepositRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleQuarantineDepositRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleQuarantineDepositRequest) ProtoMessage() {}
+
+func (x *HandleQuarantineDepositRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleQuarantineDepositRequest.ProtoReflect.Descriptor instead.
+func (*HandleQuarantineDepositRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HandleQuarantineDepositRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *HandleQuarantineDepositRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *HandleQuarantineDepositRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *HandleQuarantineDepositRequest) GetAction() QuarantineAction {
+	if x != nil {
+		return x.Action
+	}
+	return QuarantineAction_UNKNOWN_ACTION
+}
+
+// 处理隔离充值响应
+type HandleQuarantineDepositResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleQuarantineDepositResponse) Reset() {
+	*x = HandleQuarantineDepositResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleQuarantineDepositResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleQuarantineDepositResponse) ProtoMessage() {}
+
+func (x *HandleQuarantineDepositResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleQuarantineDepositResponse.ProtoReflect.Descriptor instead.
+func (*HandleQuarantineDepositResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *HandleQuarantineDepositResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *HandleQuarantineDepositResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 余额快照
+type BalanceSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	AddressType   string                 `protobuf:"bytes,2,opt,name=address_type,json=addressType,proto3" json:"address_type,omitempty"`
+	TokenAddress  string                 `protobuf:"bytes,3,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	Balance       string                 `protobuf:"bytes,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	LockBalance   string                 `protobuf:"bytes,5,opt,name=lock_balance,json=lockBalance,proto3" json:"lock_balance,omitempty"`
+	BlockNumber   string                 `protobuf:"bytes,6,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	SnapshotTime  uint64                 `protobuf:"varint,7,opt,name=snapshot_time,json=snapshotTime,proto3" json:"snapshot_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalanceSnapshot) Reset() {
+	*x = BalanceSnapshot{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceSnapshot) ProtoMessage() {}
+
+func (x *BalanceSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceSnapshot.ProtoReflect.Descriptor instead.
+func (*BalanceSnapshot) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BalanceSnapshot) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *BalanceSnapshot) GetAddressType() string {
+	if x != nil {
+		return x.AddressType
+	}
+	return ""
+}
+
+func (x *BalanceSnapshot) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *BalanceSnapshot) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *BalanceSnapshot) GetLockBalance() string {
+	if x != nil {
+		return x.LockBalance
+	}
+	return ""
+}
+
+func (x *BalanceSnapshot) GetBlockNumber() string {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return ""
+}
+
+func (x *BalanceSnapshot) GetSnapshotTime() uint64 {
+	if x != nil {
+		return x.SnapshotTime
+	}
+	return 0
+}
+
+// 时间点余额请求：block_number 与 timestamp 二选一，address、token_address 为空则不限
+type GetBalanceAtRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	TokenAddress  string                 `protobuf:"bytes,4,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	BlockNumber   string                 `protobuf:"bytes,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Timestamp     uint64                 `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceAtRequest) Reset() {
+	*x = GetBalanceAtRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceAtRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceAtRequest) ProtoMessage() {}
+
+func (x *GetBalanceAtRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceAtRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceAtRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetBalanceAtRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *GetBalanceAtRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *GetBalanceAtRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *GetBalanceAtRequest) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *GetBalanceAtRequest) GetBlockNumber() string {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return ""
+}
+
+func (x *GetBalanceAtRequest) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// 时间点余额响应：每个地址、代币不晚于指定时间点的最近一次快照
+type GetBalanceAtResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Balances      []*BalanceSnapshot     `protobuf:"bytes,3,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceAtResponse) Reset() {
+	*x = GetBalanceAtResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceAtResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceAtResponse) ProtoMessage() {}
+
+func (x *GetBalanceAtResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceAtResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceAtResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetBalanceAtResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *GetBalanceAtResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GetBalanceAtResponse) GetBalances() []*BalanceSnapshot {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+// 储备证明请求：返回命令行已生成的最近一次报告，block_number 不为空时取报告区块不晚于该区块的最近一次
+type ProofOfReservesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	BlockNumber   string                 `protobuf:"bytes,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProofOfReservesRequest) Reset() {
+	*x = ProofOfReservesRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProofOfReservesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofOfReservesRequest) ProtoMessage() {}
+
+func (x *ProofOfReservesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofOfReservesRequest.ProtoReflect.Descriptor instead.
+func (*ProofOfReservesRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ProofOfReservesRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ProofOfReservesRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ProofOfReservesRequest) GetBlockNumber() string {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return ""
+}
+
+// 储备证明响应：data 为 JSON 数据集（链上储备、用户余额 Merkle 树及包含证明）
+type ProofOfReservesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	MerkleRoot    string                 `protobuf:"bytes,3,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Data          string                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProofOfReservesResponse) Reset() {
+	*x = ProofOfReservesResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProofOfReservesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofOfReservesResponse) ProtoMessage() {}
+
+func (x *ProofOfReservesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofOfReservesResponse.ProtoReflect.Descriptor instead.
+func (*ProofOfReservesResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ProofOfReservesResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ProofOfReservesResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ProofOfReservesResponse) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *ProofOfReservesResponse) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+// 手续费汇总项
+type FeeReportItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BusinessId    string                 `protobuf:"bytes,1,opt,name=business_id,json=businessId,proto3" json:"business_id,omitempty"`
+	TxType        string                 `protobuf:"bytes,2,opt,name=tx_type,json=txType,proto3" json:"tx_type,omitempty"`
+	TokenAddress  string                 `protobuf:"bytes,3,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	Day           string                 `protobuf:"bytes,4,opt,name=day,proto3" json:"day,omitempty"`
+	TxCount       int64                  `protobuf:"varint,5,opt,name=tx_count,json=txCount,proto3" json:"tx_count,omitempty"`
+	TotalFee      string                 `protobuf:"bytes,6,opt,name=total_fee,json=totalFee,proto3" json:"total_fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeeReportItem) Reset() {
+	*x = FeeReportItem{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeeReportItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeReportItem) ProtoMessage() {}
+
+func (x *FeeReportItem) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeReportItem.ProtoReflect.Descriptor instead.
+func (*FeeReportItem) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *FeeReportItem) GetBusinessId() string {
+	if x != nil {
+		return x.BusinessId
+	}
+	return ""
+}
+
+func (x *FeeReportItem) GetTxType() string {
+	if x != nil {
+		return x.TxType
+	}
+	return ""
+}
+
+func (x *FeeReportItem) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *FeeReportItem) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *FeeReportItem) GetTxCount() int64 {
+	if x != nil {
+		return x.TxCount
+	}
+	return 0
+}
+
+func (x *FeeReportItem) GetTotalFee() string {
+	if x != nil {
+		return x.TotalFee
+	}
+	return ""
+}
+
+// 手续费报表请求：request_id 必填，时间为秒级时间戳，end_time 为 0 则不限
+type FeeReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	StartTime     uint64                 `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       uint64                 `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeeReportRequest) Reset() {
+	*x = FeeReportRequest{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeeReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeReportRequest) ProtoMessage() {}
+
+func (x *FeeReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeReportRequest.ProtoReflect.Descriptor instead.
+func (*FeeReportRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FeeReportRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *FeeReportRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *FeeReportRequest) GetStartTime() uint64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *FeeReportRequest) GetEndTime() uint64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+// 手续费报表响应：按项目方、交易类型、代币、日期（UTC）汇总
+type FeeReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=syncs.ReturnCode" json:"code,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Items         []*FeeReportItem       `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeeReportResponse) Reset() {
+	*x = FeeReportResponse{}
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeeReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeReportResponse) ProtoMessage() {}
+
+func (x *FeeReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_exchange_wallet_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeReportResponse.ProtoReflect.Descriptor instead.
+func (*FeeReportResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_exchange_wallet_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *FeeReportResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *FeeReportResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *FeeReportResponse) GetItems() []*FeeReportItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
 var File_protobuf_exchange_wallet_proto protoreflect.FileDescriptor
 
 const file_protobuf_exchange_wallet_proto_rawDesc = "" +
@@ -949,7 +2571,7 @@ const file_protobuf_exchange_wallet_proto_rawDesc = "" +
 	"public_key\x18\x02 \x01(\tR\tpublicKey\"7\n" +
 	"\aAddress\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
-	"\aaddress\x18\x02 \x01(\tR\aaddress\"\xa4\x01\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\xd2\x01\n" +
 	"\x05Token\x12\x1a\n" +
 	"\bdecimals\x18\x01 \x01(\rR\bdecimals\x12\x18\n" +
 	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x1d\n" +
@@ -957,13 +2579,16 @@ const file_protobuf_exchange_wallet_proto_rawDesc = "" +
 	"token_name\x18\x03 \x01(\tR\ttokenName\x12%\n" +
 	"\x0ecollect_amount\x18\x04 \x01(\tR\rcollectAmount\x12\x1f\n" +
 	"\vcold_amount\x18\x05 \x01(\tR\n" +
-	"coldAmount\"~\n" +
+	"coldAmount\x12,\n" +
+	"\x12min_deposit_amount\x18\x06 \x01(\tR\x10minDepositAmount\"\x9f\x01\n" +
 	"\x17BusinessRegisterRequest\x12%\n" +
 	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x02 \x01(\tR\trequestId\x12\x1d\n" +
 	"\n" +
-	"notify_url\x18\x03 \x01(\tR\tnotifyUrl\"S\n" +
+	"notify_url\x18\x03 \x01(\tR\tnotifyUrl\x12\x1f\n" +
+	"\vfee_ceiling\x18\x04 \x01(\tR\n" +
+	"feeCeiling\"S\n" +
 	"\x18BusinessRegisterResponse\x12%\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
 	"\x03msg\x18\x02 \x01(\tR\x03msg\"\x8f\x01\n" +
@@ -976,7 +2601,7 @@ const file_protobuf_exchange_wallet_proto_rawDesc = "" +
 	"\x15ExportAddressResponse\x12%\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
 	"\x03msg\x18\x02 \x01(\tR\x03msg\x12,\n" +
-	"\taddresses\x18\x03 \x03(\v2\x0e.syncs.AddressR\taddresses\"\xc9\x02\n" +
+	"\taddresses\x18\x03 \x03(\v2\x0e.syncs.AddressR\taddresses\"\x92\x04\n" +
 	"\x18UnSignTransactionRequest\x12%\n" +
 	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
 	"\n" +
@@ -991,13 +2616,63 @@ const file_protobuf_exchange_wallet_proto_rawDesc = "" +
 	"\n" +
 	"token_meta\x18\n" +
 	" \x01(\tR\ttokenMeta\x12\x17\n" +
-	"\atx_type\x18\v \x01(\tR\x06txType\"\x99\x01\n" +
+	"\atx_type\x18\v \x01(\tR\x06txType\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\f \x01(\tR\ttokenType\x12,\n" +
+	"\apayouts\x18\r \x03(\v2\x12.syncs.BatchPayoutR\apayouts\x12\x1b\n" +
+	"\tfee_level\x18\x0e \x01(\tR\bfeeLevel\x12%\n" +
+	"\x0fmax_fee_per_gas\x18\x0f \x01(\tR\fmaxFeePerGas\x126\n" +
+	"\x18max_priority_fee_per_gas\x18\x10 \x01(\tR\x14maxPriorityFeePerGas\"3\n" +
+	"\vBatchPayout\x12\x0e\n" +
+	"\x02to\x18\x01 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xa8\x01\n" +
+	"\x11BatchPayoutResult\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12%\n" +
+	"\x04code\x18\x04 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x1f\n" +
+	"\vrisk_reason\x18\x05 \x01(\tR\n" +
+	"riskReason\"\xf6\x03\n" +
 	"\x19UnSignTransactionResponse\x12%\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
 	"\x03msg\x18\x02 \x01(\tR\x03msg\x12%\n" +
 	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\x12\x1c\n" +
 	"\n" +
-	"un_sign_tx\x18\x04 \x01(\tR\bunSignTx\"\xef\x01\n" +
+	"un_sign_tx\x18\x04 \x01(\tR\bunSignTx\x12\x1f\n" +
+	"\vrisk_reason\x18\x05 \x01(\tR\n" +
+	"riskReason\x12;\n" +
+	"\x1agas_funding_transaction_id\x18\x06 \x01(\tR\x17gasFundingTransactionId\x122\n" +
+	"\x16gas_funding_un_sign_tx\x18\a \x01(\tR\x12gasFundingUnSignTx\x12\x19\n" +
+	"\bbatch_id\x18\b \x01(\tR\abatchId\x122\n" +
+	"\apayouts\x18\t \x03(\v2\x18.syncs.BatchPayoutResultR\apayouts\x12%\n" +
+	"\x0fmax_fee_per_gas\x18\n" +
+	" \x01(\tR\fmaxFeePerGas\x126\n" +
+	"\x18max_priority_fee_per_gas\x18\v \x01(\tR\x14maxPriorityFeePerGas\x12\x1b\n" +
+	"\tgas_limit\x18\f \x01(\x04R\bgasLimit\"\xf5\x02\n" +
+	"\x12EstimateFeeRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\tR\x05value\x12)\n" +
+	"\x10contract_address\x18\x06 \x01(\tR\x0fcontractAddress\x12\x19\n" +
+	"\btoken_id\x18\a \x01(\tR\atokenId\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\b \x01(\tR\ttokenType\x12\x1b\n" +
+	"\tfee_level\x18\t \x01(\tR\bfeeLevel\x12%\n" +
+	"\x0fmax_fee_per_gas\x18\n" +
+	" \x01(\tR\fmaxFeePerGas\x126\n" +
+	"\x18max_priority_fee_per_gas\x18\v \x01(\tR\x14maxPriorityFeePerGas\"\x9c\x02\n" +
+	"\x13EstimateFeeResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x1b\n" +
+	"\tgas_limit\x18\x03 \x01(\x04R\bgasLimit\x12%\n" +
+	"\x0fmax_fee_per_gas\x18\x04 \x01(\tR\fmaxFeePerGas\x126\n" +
+	"\x18max_priority_fee_per_gas\x18\x05 \x01(\tR\x14maxPriorityFeePerGas\x12\x1b\n" +
+	"\ttotal_fee\x18\x06 \x01(\tR\btotalFee\x12\x1b\n" +
+	"\tfee_level\x18\a \x01(\tR\bfeeLevel\x12\x16\n" +
+	"\x06legacy\x18\b \x01(\bR\x06legacy\"\x8a\x02\n" +
 	"\x18SignedTransactionRequest\x12%\n" +
 	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
 	"\n" +
@@ -1006,7 +2681,8 @@ const file_protobuf_exchange_wallet_proto_rawDesc = "" +
 	"\bchain_id\x18\x04 \x01(\tR\achainId\x12%\n" +
 	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\x12\x1c\n" +
 	"\tsignature\x18\x06 \x01(\tR\tsignature\x12\x17\n" +
-	"\atx_type\x18\a \x01(\tR\x06txType\"q\n" +
+	"\atx_type\x18\a \x01(\tR\x06txType\x12\x19\n" +
+	"\bbatch_id\x18\b \x01(\tR\abatchId\"q\n" +
 	"\x19SignedTransactionResponse\x12%\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
 	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x1b\n" +
@@ -1018,17 +2694,125 @@ const file_protobuf_exchange_wallet_proto_rawDesc = "" +
 	"token_list\x18\x02 \x03(\v2\f.syncs.TokenR\ttokenList\"R\n" +
 	"\x17SetTokenAddressResponse\x12%\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
-	"\x03msg\x18\x02 \x01(\tR\x03msg*$\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\"}\n" +
+	"\x14SetFeeCeilingRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x1f\n" +
+	"\vfee_ceiling\x18\x03 \x01(\tR\n" +
+	"feeCeiling\"P\n" +
+	"\x15SetFeeCeilingResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\"\xaf\x02\n" +
+	"\x11QuarantineDeposit\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x17\n" +
+	"\atx_hash\x18\x02 \x01(\tR\x06txHash\x12!\n" +
+	"\fblock_number\x18\x03 \x01(\tR\vblockNumber\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\tR\x02to\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x06 \x01(\tR\ttokenType\x12#\n" +
+	"\rtoken_address\x18\a \x01(\tR\ftokenAddress\x12\x19\n" +
+	"\btoken_id\x18\b \x01(\tR\atokenId\x12\x16\n" +
+	"\x06amount\x18\t \x01(\tR\x06amount\x12\x1c\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\x04R\ttimestamp\"a\n" +
+	"\x19QuarantineDepositsRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"\x8b\x01\n" +
+	"\x1aQuarantineDepositsResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x124\n" +
+	"\bdeposits\x18\x03 \x03(\v2\x18.syncs.QuarantineDepositR\bdeposits\"\xbe\x01\n" +
+	"\x1eHandleQuarantineDepositRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\x12/\n" +
+	"\x06action\x18\x04 \x01(\x0e2\x17.syncs.QuarantineActionR\x06action\"Z\n" +
+	"\x1fHandleQuarantineDepositResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\"\xf8\x01\n" +
+	"\x0fBalanceSnapshot\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12!\n" +
+	"\faddress_type\x18\x02 \x01(\tR\vaddressType\x12#\n" +
+	"\rtoken_address\x18\x03 \x01(\tR\ftokenAddress\x12\x18\n" +
+	"\abalance\x18\x04 \x01(\tR\abalance\x12!\n" +
+	"\flock_balance\x18\x05 \x01(\tR\vlockBalance\x12!\n" +
+	"\fblock_number\x18\x06 \x01(\tR\vblockNumber\x12#\n" +
+	"\rsnapshot_time\x18\a \x01(\x04R\fsnapshotTime\"\xdb\x01\n" +
+	"\x13GetBalanceAtRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12#\n" +
+	"\rtoken_address\x18\x04 \x01(\tR\ftokenAddress\x12!\n" +
+	"\fblock_number\x18\x05 \x01(\tR\vblockNumber\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x04R\ttimestamp\"\x83\x01\n" +
+	"\x14GetBalanceAtResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x122\n" +
+	"\bbalances\x18\x03 \x03(\v2\x16.syncs.BalanceSnapshotR\bbalances\"\x81\x01\n" +
+	"\x16ProofOfReservesRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12!\n" +
+	"\fblock_number\x18\x03 \x01(\tR\vblockNumber\"\x87\x01\n" +
+	"\x17ProofOfReservesResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12\x1f\n" +
+	"\vmerkle_root\x18\x03 \x01(\tR\n" +
+	"merkleRoot\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\tR\x04data\"\xb8\x01\n" +
+	"\rFeeReportItem\x12\x1f\n" +
+	"\vbusiness_id\x18\x01 \x01(\tR\n" +
+	"businessId\x12\x17\n" +
+	"\atx_type\x18\x02 \x01(\tR\x06txType\x12#\n" +
+	"\rtoken_address\x18\x03 \x01(\tR\ftokenAddress\x12\x10\n" +
+	"\x03day\x18\x04 \x01(\tR\x03day\x12\x19\n" +
+	"\btx_count\x18\x05 \x01(\x03R\atxCount\x12\x1b\n" +
+	"\ttotal_fee\x18\x06 \x01(\tR\btotalFee\"\x92\x01\n" +
+	"\x10FeeReportRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x04R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x04R\aendTime\"x\n" +
+	"\x11FeeReportResponse\x12%\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x11.syncs.ReturnCodeR\x04code\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x12*\n" +
+	"\x05items\x18\x03 \x03(\v2\x14.syncs.FeeReportItemR\x05items*\x92\x01\n" +
 	"\n" +
 	"ReturnCode\x12\t\n" +
 	"\x05ERROR\x10\x00\x12\v\n" +
-	"\aSUCCESS\x10\x012\xd1\x03\n" +
+	"\aSUCCESS\x10\x01\x12\r\n" +
+	"\tRISK_HOLD\x10\x02\x12\x0f\n" +
+	"\vRISK_REJECT\x10\x03\x12\x13\n" +
+	"\x0fINVALID_ADDRESS\x10\x04\x12\x10\n" +
+	"\fZERO_ADDRESS\x10\x05\x12\x0f\n" +
+	"\vOWN_ADDRESS\x10\x06\x12\x14\n" +
+	"\x10CONTRACT_ADDRESS\x10\a*>\n" +
+	"\x10QuarantineAction\x12\x12\n" +
+	"\x0eUNKNOWN_ACTION\x10\x00\x12\n" +
+	"\n" +
+	"\x06ACCEPT\x10\x01\x12\n" +
+	"\n" +
+	"\x06IGNORE\x10\x022\x8d\b\n" +
 	"\x16WalletBusinessServices\x12S\n" +
 	"\x10businessRegister\x12\x1e.syncs.BusinessRegisterRequest\x1a\x1f.syncs.BusinessRegisterResponse\x12V\n" +
 	"\x19exportAddressByPublicKeys\x12\x1b.syncs.ExportAddressRequest\x1a\x1c.syncs.ExportAddressResponse\x12[\n" +
-	"\x16buildUnSignTransaction\x12\x1f.syncs.UnSignTransactionRequest\x1a .syncs.UnSignTransactionResponse\x12[\n" +
+	"\x16buildUnSignTransaction\x12\x1f.syncs.UnSignTransactionRequest\x1a .syncs.UnSignTransactionResponse\x12D\n" +
+	"\vestimateFee\x12\x19.syncs.EstimateFeeRequest\x1a\x1a.syncs.EstimateFeeResponse\x12[\n" +
 	"\x16buildSignedTransaction\x12\x1f.syncs.SignedTransactionRequest\x1a .syncs.SignedTransactionResponse\x12P\n" +
-	"\x0fsetTokenAddress\x12\x1d.syncs.SetTokenAddressRequest\x1a\x1e.syncs.SetTokenAddressResponseB\x1fZ\x1d./protobuf/exchange-wallet-gob\x06proto3"
+	"\x0fsetTokenAddress\x12\x1d.syncs.SetTokenAddressRequest\x1a\x1e.syncs.SetTokenAddressResponse\x12J\n" +
+	"\rsetFeeCeiling\x12\x1b.syncs.SetFeeCeilingRequest\x1a\x1c.syncs.SetFeeCeilingResponse\x12]\n" +
+	"\x16listQuarantineDeposits\x12 .syncs.QuarantineDepositsRequest\x1a!.syncs.QuarantineDepositsResponse\x12h\n" +
+	"\x17handleQuarantineDeposit\x12%.syncs.HandleQuarantineDepositRequest\x1a&.syncs.HandleQuarantineDepositResponse\x12G\n" +
+	"\fgetBalanceAt\x12\x1a.syncs.GetBalanceAtRequest\x1a\x1b.syncs.GetBalanceAtResponse\x12S\n" +
+	"\x12getProofOfReserves\x12\x1d.syncs.ProofOfReservesRequest\x1a\x1e.syncs.ProofOfReservesResponse\x12A\n" +
+	"\fgetFeeReport\x12\x17.syncs.FeeReportRequest\x1a\x18.syncs.FeeReportResponseB\x1fZ\x1d./protobuf/exchange-wallet-gob\x06proto3"
 
 var (
 	file_protobuf_exchange_wallet_proto_rawDescOnce sync.Once
@@ -1042,48 +2826,96 @@ func file_protobuf_exchange_wallet_proto_rawDescGZIP() []byte {
 	return file_protobuf_exchange_wallet_proto_rawDescData
 }
 
-var file_protobuf_exchange_wallet_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_protobuf_exchange_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_protobuf_exchange_wallet_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_protobuf_exchange_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_protobuf_exchange_wallet_proto_goTypes = []any{
-	(ReturnCode)(0),                   // 0: syncs.ReturnCode
-	(*PublicKey)(nil),                 // 1: syncs.PublicKey
-	(*Address)(nil),                   // 2: syncs.Address
-	(*Token)(nil),                     // 3: syncs.Token
-	(*BusinessRegisterRequest)(nil),   // 4: syncs.BusinessRegisterRequest
-	(*BusinessRegisterResponse)(nil),  // 5: syncs.BusinessRegisterResponse
-	(*ExportAddressRequest)(nil),      // 6: syncs.ExportAddressRequest
-	(*ExportAddressResponse)(nil),     // 7: syncs.ExportAddressResponse
-	(*UnSignTransactionRequest)(nil),  // 8: syncs.UnSignTransactionRequest
-	(*UnSignTransactionResponse)(nil), // 9: syncs.UnSignTransactionResponse
-	(*SignedTransactionRequest)(nil),  // 10: syncs.SignedTransactionRequest
-	(*SignedTransactionResponse)(nil), // 11: syncs.SignedTransactionResponse
-	(*SetTokenAddressRequest)(nil),    // 12: syncs.SetTokenAddressRequest
-	(*SetTokenAddressResponse)(nil),   // 13: syncs.SetTokenAddressResponse
+	(ReturnCode)(0),                         // 0: syncs.ReturnCode
+	(QuarantineAction)(0),                   // 1: syncs.QuarantineAction
+	(*PublicKey)(nil),                       // 2: syncs.PublicKey
+	(*Address)(nil),                         // 3: syncs.Address
+	(*Token)(nil),                           // 4: syncs.Token
+	(*BusinessRegisterRequest)(nil),         // 5: syncs.BusinessRegisterRequest
+	(*BusinessRegisterResponse)(nil),        // 6: syncs.BusinessRegisterResponse
+	(*ExportAddressRequest)(nil),            // 7: syncs.ExportAddressRequest
+	(*ExportAddressResponse)(nil),           // 8: syncs.ExportAddressResponse
+	(*UnSignTransactionRequest)(nil),        // 9: syncs.UnSignTransactionRequest
+	(*BatchPayout)(nil),                     // 10: syncs.BatchPayout
+	(*BatchPayoutResult)(nil),               // 11: syncs.BatchPayoutResult
+	(*UnSignTransactionResponse)(nil),       // 12: syncs.UnSignTransactionResponse
+	(*EstimateFeeRequest)(nil),              // 13: syncs.EstimateFeeRequest
+	(*EstimateFeeResponse)(nil),             // 14: syncs.EstimateFeeResponse
+	(*SignedTransactionRequest)(nil),        // 15: syncs.SignedTransactionRequest
+	(*SignedTransactionResponse)(nil),       // 16: syncs.SignedTransactionResponse
+	(*SetTokenAddressRequest)(nil),          // 17: syncs.SetTokenAddressRequest
+	(*SetTokenAddressResponse)(nil),         // 18: syncs.SetTokenAddressResponse
+	(*SetFeeCeilingRequest)(nil),            // 19: syncs.SetFeeCeilingRequest
+	(*SetFeeCeilingResponse)(nil),           // 20: syncs.SetFeeCeilingResponse
+	(*QuarantineDeposit)(nil),               // 21: syncs.QuarantineDeposit
+	(*QuarantineDepositsRequest)(nil),       // 22: syncs.QuarantineDepositsRequest
+	(*QuarantineDepositsResponse)(nil),      // 23: syncs.QuarantineDepositsResponse
+	(*HandleQuarantineDepositRequest)(nil),  // 24: syncs.HandleQuarantineDepositRequest
+	(*HandleQuarantineDepositResponse)(nil), // 25: syncs.HandleQuarantineDepositResponse
+	(*BalanceSnapshot)(nil),                 // 26: syncs.BalanceSnapshot
+	(*GetBalanceAtRequest)(nil),             // 27: syncs.GetBalanceAtRequest
+	(*GetBalanceAtResponse)(nil),            // 28: syncs.GetBalanceAtResponse
+	(*ProofOfReservesRequest)(nil),          // 29: syncs.ProofOfReservesRequest
+	(*ProofOfReservesResponse)(nil),         // 30: syncs.ProofOfReservesResponse
+	(*FeeReportItem)(nil),                   // 31: syncs.FeeReportItem
+	(*FeeReportRequest)(nil),                // 32: syncs.FeeReportRequest
+	(*FeeReportResponse)(nil),               // 33: syncs.FeeReportResponse
 }
 var file_protobuf_exchange_wallet_proto_depIdxs = []int32{
 	0,  // 0: syncs.BusinessRegisterResponse.code:type_name -> syncs.ReturnCode
-	1,  // 1: syncs.ExportAddressRequest.public_keys:type_name -> syncs.PublicKey
+	2,  // 1: syncs.ExportAddressRequest.public_keys:type_name -> syncs.PublicKey
 	0,  // 2: syncs.ExportAddressResponse.code:type_name -> syncs.ReturnCode
-	2,  // 3: syncs.ExportAddressResponse.addresses:type_name -> syncs.Address
-	0,  // 4: syncs.UnSignTransactionResponse.code:type_name -> syncs.ReturnCode
-	0,  // 5: syncs.SignedTransactionResponse.code:type_name -> syncs.ReturnCode
-	3,  // 6: syncs.SetTokenAddressRequest.token_list:type_name -> syncs.Token
-	0,  // 7: syncs.SetTokenAddressResponse.code:type_name -> syncs.ReturnCode
-	4,  // 8: syncs.WalletBusinessServices.businessRegister:input_type -> syncs.BusinessRegisterRequest
-	6,  // 9: syncs.WalletBusinessServices.exportAddressByPublicKeys:input_type -> syncs.ExportAddressRequest
-	8,  // 10: syncs.WalletBusinessServices.buildUnSignTransaction:input_type -> syncs.UnSignTransactionRequest
-	10, // 11: syncs.WalletBusinessServices.buildSignedTransaction:input_type -> syncs.SignedTransactionRequest
-	12, // 12: syncs.WalletBusinessServices.setTokenAddress:input_type -> syncs.SetTokenAddressRequest
-	5,  // 13: syncs.WalletBusinessServices.businessRegister:output_type -> syncs.BusinessRegisterResponse
-	7,  // 14: syncs.WalletBusinessServices.exportAddressByPublicKeys:output_type -> syncs.ExportAddressResponse
-	9,  // 15: syncs.WalletBusinessServices.buildUnSignTransaction:output_type -> syncs.UnSignTransactionResponse
-	11, // 16: syncs.WalletBusinessServices.buildSignedTransaction:output_type -> syncs.SignedTransactionResponse
-	13, // 17: syncs.WalletBusinessServices.setTokenAddress:output_type -> syncs.SetTokenAddressResponse
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	3,  // 3: syncs.ExportAddressResponse.addresses:type_name -> syncs.Address
+	10, // 4: syncs.UnSignTransactionRequest.payouts:type_name -> syncs.BatchPayout
+	0,  // 5: syncs.BatchPayoutResult.code:type_name -> syncs.ReturnCode
+	0,  // 6: syncs.UnSignTransactionResponse.code:type_name -> syncs.ReturnCode
+	11, // 7: syncs.UnSignTransactionResponse.payouts:type_name -> syncs.BatchPayoutResult
+	0,  // 8: syncs.EstimateFeeResponse.code:type_name -> syncs.ReturnCode
+	0,  // 9: syncs.SignedTransactionResponse.code:type_name -> syncs.ReturnCode
+	4,  // 10: syncs.SetTokenAddressRequest.token_list:type_name -> syncs.Token
+	0,  // 11: syncs.SetTokenAddressResponse.code:type_name -> syncs.ReturnCode
+	0,  // 12: syncs.SetFeeCeilingResponse.code:type_name -> syncs.ReturnCode
+	0,  // 13: syncs.QuarantineDepositsResponse.code:type_name -> syncs.ReturnCode
+	21, // 14: syncs.QuarantineDepositsResponse.deposits:type_name -> syncs.QuarantineDeposit
+	1,  // 15: syncs.HandleQuarantineDepositRequest.action:type_name -> syncs.QuarantineAction
+	0,  // 16: syncs.HandleQuarantineDepositResponse.code:type_name -> syncs.ReturnCode
+	0,  // 17: syncs.GetBalanceAtResponse.code:type_name -> syncs.ReturnCode
+	26, // 18: syncs.GetBalanceAtResponse.balances:type_name -> syncs.BalanceSnapshot
+	0,  // 19: syncs.ProofOfReservesResponse.code:type_name -> syncs.ReturnCode
+	0,  // 20: syncs.FeeReportResponse.code:type_name -> syncs.ReturnCode
+	31, // 21: syncs.FeeReportResponse.items:type_name -> syncs.FeeReportItem
+	5,  // 22: syncs.WalletBusinessServices.businessRegister:input_type -> syncs.BusinessRegisterRequest
+	7,  // 23: syncs.WalletBusinessServices.exportAddressByPublicKeys:input_type -> syncs.ExportAddressRequest
+	9,  // 24: syncs.WalletBusinessServices.buildUnSignTransaction:input_type -> syncs.UnSignTransactionRequest
+	13, // 25: syncs.WalletBusinessServices.estimateFee:input_type -> syncs.EstimateFeeRequest
+	15, // 26: syncs.WalletBusinessServices.buildSignedTransaction:input_type -> syncs.SignedTransactionRequest
+	17, // 27: syncs.WalletBusinessServices.setTokenAddress:input_type -> syncs.SetTokenAddressRequest
+	19, // 28: syncs.WalletBusinessServices.setFeeCeiling:input_type -> syncs.SetFeeCeilingRequest
+	22, // 29: syncs.WalletBusinessServices.listQuarantineDeposits:input_type -> syncs.QuarantineDepositsRequest
+	24, // 30: syncs.WalletBusinessServices.handleQuarantineDeposit:input_type -> syncs.HandleQuarantineDepositRequest
+	27, // 31: syncs.WalletBusinessServices.getBalanceAt:input_type -> syncs.GetBalanceAtRequest
+	29, // 32: syncs.WalletBusinessServices.getProofOfReserves:input_type -> syncs.ProofOfReservesRequest
+	32, // 33: syncs.WalletBusinessServices.getFeeReport:input_type -> syncs.FeeReportRequest
+	6,  // 34: syncs.WalletBusinessServices.businessRegister:output_type -> syncs.BusinessRegisterResponse
+	8,  // 35: syncs.WalletBusinessServices.exportAddressByPublicKeys:output_type -> syncs.ExportAddressResponse
+	12, // 36: syncs.WalletBusinessServices.buildUnSignTransaction:output_type -> syncs.UnSignTransactionResponse
+	14, // 37: syncs.WalletBusinessServices.estimateFee:output_type -> syncs.EstimateFeeResponse
+	16, // 38: syncs.WalletBusinessServices.buildSignedTransaction:output_type -> syncs.SignedTransactionResponse
+	18, // 39: syncs.WalletBusinessServices.setTokenAddress:output_type -> syncs.SetTokenAddressResponse
+	20, // 40: syncs.WalletBusinessServices.setFeeCeiling:output_type -> syncs.SetFeeCeilingResponse
+	23, // 41: syncs.WalletBusinessServices.listQuarantineDeposits:output_type -> syncs.QuarantineDepositsResponse
+	25, // 42: syncs.WalletBusinessServices.handleQuarantineDeposit:output_type -> syncs.HandleQuarantineDepositResponse
+	28, // 43: syncs.WalletBusinessServices.getBalanceAt:output_type -> syncs.GetBalanceAtResponse
+	30, // 44: syncs.WalletBusinessServices.getProofOfReserves:output_type -> syncs.ProofOfReservesResponse
+	33, // 45: syncs.WalletBusinessServices.getFeeReport:output_type -> syncs.FeeReportResponse
+	34, // [34:46] is the sub-list for method output_type
+	22, // [22:34] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_exchange_wallet_proto_init() }
@@ -1096,8 +2928,8 @@ func file_protobuf_exchange_wallet_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protobuf_exchange_wallet_proto_rawDesc), len(file_protobuf_exchange_wallet_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   13,
+			NumEnums:      2,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
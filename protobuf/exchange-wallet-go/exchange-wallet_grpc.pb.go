@@ -22,24 +22,45 @@ const (
 	WalletBusinessServices_BusinessRegister_FullMethodName          = "/syncs.WalletBusinessServices/businessRegister"
 	WalletBusinessServices_ExportAddressByPublicKeys_FullMethodName = "/syncs.WalletBusinessServices/exportAddressByPublicKeys"
 	WalletBusinessServices_BuildUnSignTransaction_FullMethodName    = "/syncs.WalletBusinessServices/buildUnSignTransaction"
+	WalletBusinessServices_EstimateFee_FullMethodName               = "/syncs.WalletBusinessServices/estimateFee"
 	WalletBusinessServices_BuildSignedTransaction_FullMethodName    = "/syncs.WalletBusinessServices/buildSignedTransaction"
 	WalletBusinessServices_SetTokenAddress_FullMethodName           = "/syncs.WalletBusinessServices/setTokenAddress"
+	WalletBusinessServices_SetFeeCeiling_FullMethodName             = "/syncs.WalletBusinessServices/setFeeCeiling"
+	WalletBusinessServices_ListQuarantineDeposits_FullMethodName    = "/syncs.WalletBusinessServices/listQuarantineDeposits"
+	WalletBusinessServices_HandleQuarantineDeposit_FullMethodName   = "/syncs.WalletBusinessServices/handleQuarantineDeposit"
+	WalletBusinessServices_GetBalanceAt_FullMethodName              = "/syncs.WalletBusinessServices/getBalanceAt"
+	WalletBusinessServices_GetProofOfReserves_FullMethodName        = "/syncs.WalletBusinessServices/getProofOfReserves"
+	WalletBusinessServices_GetFeeReport_FullMethodName              = "/syncs.WalletBusinessServices/getFeeReport"
 )
 
 // WalletBusinessServicesClient is the client API for WalletBusinessServices service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type WalletBusinessServicesClient interface {
-	// 业务方注册
+	//业务方注册
 	BusinessRegister(ctx context.Context, in *BusinessRegisterRequest, opts ...grpc.CallOption) (*BusinessRegisterResponse, error)
-	// 公钥导出
+	//地址导出
 	ExportAddressByPublicKeys(ctx context.Context, in *ExportAddressRequest, opts ...grpc.CallOption) (*ExportAddressResponse, error)
-	// 构建未签名交易
+	//构建未签名交易
 	BuildUnSignTransaction(ctx context.Context, in *UnSignTransactionRequest, opts ...grpc.CallOption) (*UnSignTransactionResponse, error)
-	// 构建已签名交易
+	//手续费预估
+	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	//构建已签名交易
 	BuildSignedTransaction(ctx context.Context, in *SignedTransactionRequest, opts ...grpc.CallOption) (*SignedTransactionResponse, error)
-	// 设置 token 地址
+	//设置 token 地址
 	SetTokenAddress(ctx context.Context, in *SetTokenAddressRequest, opts ...grpc.CallOption) (*SetTokenAddressResponse, error)
+	//设置项目方 gas 费率上限
+	SetFeeCeiling(ctx context.Context, in *SetFeeCeilingRequest, opts ...grpc.CallOption) (*SetFeeCeilingResponse, error)
+	//隔离充值列表
+	ListQuarantineDeposits(ctx context.Context, in *QuarantineDepositsRequest, opts ...grpc.CallOption) (*QuarantineDepositsResponse, error)
+	//处理隔离充值：入账或忽略
+	HandleQuarantineDeposit(ctx context.Context, in *HandleQuarantineDepositRequest, opts ...grpc.CallOption) (*HandleQuarantineDepositResponse, error)
+	//时间点余额查询
+	GetBalanceAt(ctx context.Context, in *GetBalanceAtRequest, opts ...grpc.CallOption) (*GetBalanceAtResponse, error)
+	//储备证明查询（由命令行 proof-of-reserves 生成）
+	GetProofOfReserves(ctx context.Context, in *ProofOfReservesRequest, opts ...grpc.CallOption) (*ProofOfReservesResponse, error)
+	//手续费报表
+	GetFeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
 }
 
 type walletBusinessServicesClient struct {
@@ -80,6 +101,16 @@ func (c *walletBusinessServicesClient) BuildUnSignTransaction(ctx context.Contex
 	return out, nil
 }
 
+func (c *walletBusinessServicesClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateFeeResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_EstimateFee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletBusinessServicesClient) BuildSignedTransaction(ctx context.Context, in *SignedTransactionRequest, opts ...grpc.CallOption) (*SignedTransactionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SignedTransactionResponse)
@@ -100,20 +131,94 @@ func (c *walletBusinessServicesClient) SetTokenAddress(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *walletBusinessServicesClient) SetFeeCeiling(ctx context.Context, in *SetFeeCeilingRequest, opts ...grpc.CallOption) (*SetFeeCeilingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetFeeCeilingResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_SetFeeCeiling_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletBusinessServicesClient) ListQuarantineDeposits(ctx context.Context, in *QuarantineDepositsRequest, opts ...grpc.CallOption) (*QuarantineDepositsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuarantineDepositsResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_ListQuarantineDeposits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletBusinessServicesClient) HandleQuarantineDeposit(ctx context.Context, in *HandleQuarantineDepositRequest, opts ...grpc.CallOption) (*HandleQuarantineDepositResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandleQuarantineDepositResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_HandleQuarantineDeposit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletBusinessServicesClient) GetBalanceAt(ctx context.Context, in *GetBalanceAtRequest, opts ...grpc.CallOption) (*GetBalanceAtResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalanceAtResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_GetBalanceAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletBusinessServicesClient) GetProofOfReserves(ctx context.Context, in *ProofOfReservesRequest, opts ...grpc.CallOption) (*ProofOfReservesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProofOfReservesResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_GetProofOfReserves_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletBusinessServicesClient) GetFeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeeReportResponse)
+	err := c.cc.Invoke(ctx, WalletBusinessServices_GetFeeReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletBusinessServicesServer is the server API for WalletBusinessServices service.
 // All implementations should embed UnimplementedWalletBusinessServicesServer
 // for forward compatibility.
 type WalletBusinessServicesServer interface {
-	// 业务方注册
+	//业务方注册
 	BusinessRegister(context.Context, *BusinessRegisterRequest) (*BusinessRegisterResponse, error)
-	// 公钥导出
+	//地址导出
 	ExportAddressByPublicKeys(context.Context, *ExportAddressRequest) (*ExportAddressResponse, error)
-	// 构建未签名交易
+	//构建未签名交易
 	BuildUnSignTransaction(context.Context, *UnSignTransactionRequest) (*UnSignTransactionResponse, error)
-	// 构建已签名交易
+	//手续费预估
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	//构建已签名交易
 	BuildSignedTransaction(context.Context, *SignedTransactionRequest) (*SignedTransactionResponse, error)
-	// 设置 token 地址
+	//设置 token 地址
 	SetTokenAddress(context.Context, *SetTokenAddressRequest) (*SetTokenAddressResponse, error)
+	//设置项目方 gas 费率上限
+	SetFeeCeiling(context.Context, *SetFeeCeilingRequest) (*SetFeeCeilingResponse, error)
+	//隔离充值列表
+	ListQuarantineDeposits(context.Context, *QuarantineDepositsRequest) (*QuarantineDepositsResponse, error)
+	//处理隔离充值：入账或忽略
+	HandleQuarantineDeposit(context.Context, *HandleQuarantineDepositRequest) (*HandleQuarantineDepositResponse, error)
+	//时间点余额查询
+	GetBalanceAt(context.Context, *GetBalanceAtRequest) (*GetBalanceAtResponse, error)
+	//储备证明查询（由命令行 proof-of-reserves 生成）
+	GetProofOfReserves(context.Context, *ProofOfReservesRequest) (*ProofOfReservesResponse, error)
+	//手续费报表
+	GetFeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
 }
 
 // UnimplementedWalletBusinessServicesServer should be embedded to have
@@ -132,12 +237,33 @@ func (UnimplementedWalletBusinessServicesServer) ExportAddressByPublicKeys(conte
 func (UnimplementedWalletBusinessServicesServer) BuildUnSignTransaction(context.Context, *UnSignTransactionRequest) (*UnSignTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildUnSignTransaction not implemented")
 }
+func (UnimplementedWalletBusinessServicesServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
+}
 func (UnimplementedWalletBusinessServicesServer) BuildSignedTransaction(context.Context, *SignedTransactionRequest) (*SignedTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildSignedTransaction not implemented")
 }
 func (UnimplementedWalletBusinessServicesServer) SetTokenAddress(context.Context, *SetTokenAddressRequest) (*SetTokenAddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetTokenAddress not implemented")
 }
+func (UnimplementedWalletBusinessServicesServer) SetFeeCeiling(context.Context, *SetFeeCeilingRequest) (*SetFeeCeilingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFeeCeiling not implemented")
+}
+func (UnimplementedWalletBusinessServicesServer) ListQuarantineDeposits(context.Context, *QuarantineDepositsRequest) (*QuarantineDepositsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuarantineDeposits not implemented")
+}
+func (UnimplementedWalletBusinessServicesServer) HandleQuarantineDeposit(context.Context, *HandleQuarantineDepositRequest) (*HandleQuarantineDepositResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleQuarantineDeposit not implemented")
+}
+func (UnimplementedWalletBusinessServicesServer) GetBalanceAt(context.Context, *GetBalanceAtRequest) (*GetBalanceAtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalanceAt not implemented")
+}
+func (UnimplementedWalletBusinessServicesServer) GetProofOfReserves(context.Context, *ProofOfReservesRequest) (*ProofOfReservesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProofOfReserves not implemented")
+}
+func (UnimplementedWalletBusinessServicesServer) GetFeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeReport not implemented")
+}
 func (UnimplementedWalletBusinessServicesServer) testEmbeddedByValue() {}
 
 // UnsafeWalletBusinessServicesServer may be embedded to opt out of forward compatibility for this service.
@@ -212,6 +338,24 @@ func _WalletBusinessServices_BuildUnSignTransaction_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletBusinessServices_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_EstimateFee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletBusinessServices_BuildSignedTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SignedTransactionRequest)
 	if err := dec(in); err != nil {
@@ -248,6 +392,114 @@ func _WalletBusinessServices_SetTokenAddress_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletBusinessServices_SetFeeCeiling_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFeeCeilingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).SetFeeCeiling(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_SetFeeCeiling_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).SetFeeCeiling(ctx, req.(*SetFeeCeilingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletBusinessServices_ListQuarantineDeposits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuarantineDepositsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).ListQuarantineDeposits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_ListQuarantineDeposits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).ListQuarantineDeposits(ctx, req.(*QuarantineDepositsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletBusinessServices_HandleQuarantineDeposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandleQuarantineDepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).HandleQuarantineDeposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_HandleQuarantineDeposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).HandleQuarantineDeposit(ctx, req.(*HandleQuarantineDepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletBusinessServices_GetBalanceAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).GetBalanceAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_GetBalanceAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).GetBalanceAt(ctx, req.(*GetBalanceAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletBusinessServices_GetProofOfReserves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProofOfReservesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).GetProofOfReserves(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_GetProofOfReserves_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).GetProofOfReserves(ctx, req.(*ProofOfReservesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletBusinessServices_GetFeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletBusinessServicesServer).GetFeeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletBusinessServices_GetFeeReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletBusinessServicesServer).GetFeeReport(ctx, req.(*FeeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WalletBusinessServices_ServiceDesc is the grpc.ServiceDesc for WalletBusinessServices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +519,10 @@ var WalletBusinessServices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "buildUnSignTransaction",
 			Handler:    _WalletBusinessServices_BuildUnSignTransaction_Handler,
 		},
+		{
+			MethodName: "estimateFee",
+			Handler:    _WalletBusinessServices_EstimateFee_Handler,
+		},
 		{
 			MethodName: "buildSignedTransaction",
 			Handler:    _WalletBusinessServices_BuildSignedTransaction_Handler,
@@ -275,6 +531,30 @@ var WalletBusinessServices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "setTokenAddress",
 			Handler:    _WalletBusinessServices_SetTokenAddress_Handler,
 		},
+		{
+			MethodName: "setFeeCeiling",
+			Handler:    _WalletBusinessServices_SetFeeCeiling_Handler,
+		},
+		{
+			MethodName: "listQuarantineDeposits",
+			Handler:    _WalletBusinessServices_ListQuarantineDeposits_Handler,
+		},
+		{
+			MethodName: "handleQuarantineDeposit",
+			Handler:    _WalletBusinessServices_HandleQuarantineDeposit_Handler,
+		},
+		{
+			MethodName: "getBalanceAt",
+			Handler:    _WalletBusinessServices_GetBalanceAt_Handler,
+		},
+		{
+			MethodName: "getProofOfReserves",
+			Handler:    _WalletBusinessServices_GetProofOfReserves_Handler,
+		},
+		{
+			MethodName: "getFeeReport",
+			Handler:    _WalletBusinessServices_GetFeeReport_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/exchange-wallet.proto",
@@ -0,0 +1,8 @@
+package exchange_wallet_go
+
+import _ "embed"
+
+/*由 protoc-gen-openapiv2 按 exchange-wallet.proto 与网关路由生成的 OpenAPI 文档*/
+//
+//go:embed exchange-wallet.swagger.json
+var OpenAPISpec []byte
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+
+	"exchange-wallet-service/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type businessContextKey struct{}
+
+/*鉴权通过的项目方写入请求上下文，限流按其计数*/
+func WithBusiness(ctx context.Context, business string) context.Context {
+	return context.WithValue(ctx, businessContextKey{}, business)
+}
+
+/*
+限流键：开启鉴权时取客户端证书对应的项目方；
+未开启时取请求中的 request_id，其次 consumer_token（均由客户端填写）
+*/
+func requestKey(ctx context.Context, req any) string {
+	if business, ok := ctx.Value(businessContextKey{}).(string); ok && business != "" {
+		return business
+	}
+	if r, ok := req.(interface{ GetRequestId() string }); ok && r.GetRequestId() != "" {
+		return r.GetRequestId()
+	}
+	if r, ok := req.(interface{ GetConsumerToken() string }); ok {
+		return r.GetConsumerToken()
+	}
+	return ""
+}
+
+/*gRPC 服务端拦截器：service 下的方法按项目方限流，超限返回 RESOURCE_EXHAUSTED*/
+func (l *Limiter) UnaryServerInterceptor(service string) grpc.UnaryServerInterceptor {
+	prefix := "/" + service + "/"
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		method := strings.TrimPrefix(info.FullMethod, prefix)
+		business := requestKey(ctx, req)
+		if !l.Allow(business, method) {
+			metrics.RecordRateLimited(method)
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s on %s", business, method)
+		}
+		return handler(ctx, req)
+	}
+}
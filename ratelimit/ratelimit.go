@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"exchange-wallet-service/common/clock"
+	"exchange-wallet-service/database"
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/time/rate"
+)
+
+/*配置中表示所有项目方或所有接口*/
+const Wildcard = "*"
+
+/*超过该时长未使用的令牌桶在刷新时清理，避免随意的 request_id 占用内存*/
+const idleTimeout = 10 * time.Minute
+
+/*令牌桶规则*/
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+/*项目方每日配额，0 表示不限制*/
+type Quota struct {
+	DailyAddresses int64
+	DailyWithdraws int64
+}
+
+type ruleKey struct {
+	business string
+	method   string
+}
+
+type bucket struct {
+	rule     Rule
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+/*
+按项目方与接口限流，并缓存项目方每日配额：
+配置存于 rate_limits、business_quotas 表，定时刷新，刷新失败沿用上次配置
+*/
+type Limiter struct {
+	rateLimits database.RateLimitsView
+	quotas     database.BusinessQuotasView
+
+	mu       sync.Mutex
+	rules    map[ruleKey]Rule
+	quotaMap map[string]Quota
+	buckets  map[ruleKey]*bucket
+	worker   *clock.LoopFn
+}
+
+func NewLimiter(rateLimits database.RateLimitsView, quotas database.BusinessQuotasView) *Limiter {
+	return &Limiter{
+		rateLimits: rateLimits,
+		quotas:     quotas,
+		rules:      make(map[ruleKey]Rule),
+		quotaMap:   make(map[string]Quota),
+		buckets:    make(map[ruleKey]*bucket),
+	}
+}
+
+/*先加载一次配置，再定时刷新*/
+func (l *Limiter) Start(interval time.Duration) error {
+	if err := l.Refresh(); err != nil {
+		return err
+	}
+	l.worker = clock.NewLoopFn(clock.SystemClock, func(ctx context.Context) {
+		if err := l.Refresh(); err != nil {
+			log.Error("failed to refresh rate limits, keep previous ones", "err", err)
+		}
+	}, nil, interval)
+	return nil
+}
+
+func (l *Limiter) Stop() error {
+	if l == nil || l.worker == nil {
+		return nil
+	}
+	return l.worker.Close()
+}
+
+/*从数据库重新加载限流规则与配额*/
+func (l *Limiter) Refresh() error {
+	rateLimits, err := l.rateLimits.QueryRateLimits()
+	if err != nil {
+		return err
+	}
+	quotas, err := l.quotas.QueryBusinessQuotas()
+	if err != nil {
+		return err
+	}
+	rules := make(map[ruleKey]Rule, len(rateLimits))
+	for _, rateLimit := range rateLimits {
+		rules[ruleKey{business: rateLimit.BusinessUid, method: rateLimit.Method}] = Rule{Rate: rateLimit.Rate, Burst: rateLimit.Burst}
+	}
+	quotaMap := make(map[string]Quota, len(quotas))
+	for _, quota := range quotas {
+		quotaMap[quota.BusinessUid] = Quota{DailyAddresses: quota.DailyAddresses, DailyWithdraws: quota.DailyWithdraws}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules = rules
+	l.quotaMap = quotaMap
+	/*规则变更的令牌桶调整速率与容量，规则删除或长期未使用的令牌桶清理*/
+	now := time.Now()
+	for key, b := range l.buckets {
+		rule, ok := l.rule(key.business, key.method)
+		if !ok || now.Sub(b.lastSeen) > idleTimeout {
+			delete(l.buckets, key)
+			continue
+		}
+		if rule != b.rule {
+			b.rule = rule
+			b.limiter.SetLimit(rate.Limit(rule.Rate))
+			b.limiter.SetBurst(rule.Burst)
+		}
+	}
+	return nil
+}
+
+/*
+项目方调用接口是否放行：规则按 (项目方, 接口)、(项目方, *)、(*, 接口)、(*, *) 的顺序取第一条，
+没有规则不限流；每个项目方每个接口独立一个令牌桶
+*/
+func (l *Limiter) Allow(business, method string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rule, ok := l.rule(business, method)
+	if !ok {
+		return true
+	}
+	key := ruleKey{business: business, method: method}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{rule: rule, limiter: rate.NewLimiter(rate.Limit(rule.Rate), rule.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = time.Now()
+	return b.limiter.Allow()
+}
+
+func (l *Limiter) rule(business, method string) (Rule, bool) {
+	for _, key := range []ruleKey{
+		{business: business, method: method},
+		{business: business, method: Wildcard},
+		{business: Wildcard, method: method},
+		{business: Wildcard, method: Wildcard},
+	} {
+		if rule, ok := l.rules[key]; ok {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+/*项目方每日配额，未单独配置时取默认配额（*）*/
+func (l *Limiter) Quota(business string) Quota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if quota, ok := l.quotaMap[business]; ok {
+		return quota
+	}
+	return l.quotaMap[Wildcard]
+}
+
+/*当前 UTC 自然日零点（unix 秒），每日配额的统计起点*/
+func StartOfDay(now time.Time) uint64 {
+	year, month, day := now.UTC().Date()
+	return uint64(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix())
+}
@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-wallet-service/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeStore struct {
+	rateLimits []*database.RateLimits
+	quotas     []*database.BusinessQuotas
+	err        error
+}
+
+func (s *fakeStore) QueryRateLimits() ([]*database.RateLimits, error) {
+	return s.rateLimits, s.err
+}
+
+func (s *fakeStore) QueryBusinessQuotas() ([]*database.BusinessQuotas, error) {
+	return s.quotas, s.err
+}
+
+func newTestLimiter(t *testing.T, store *fakeStore) *Limiter {
+	limiter := NewLimiter(store, store)
+	require.NoError(t, limiter.Refresh())
+	return limiter
+}
+
+/*桶容量内放行，之后拒绝（rate 极小，测试期间不会补充令牌）*/
+func allowed(limiter *Limiter, business, method string, times int) int {
+	count := 0
+	for i := 0; i < times; i++ {
+		if limiter.Allow(business, method) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAllowRulePrecedence(t *testing.T) {
+	store := &fakeStore{rateLimits: []*database.RateLimits{
+		{BusinessUid: Wildcard, Method: Wildcard, Rate: 0.001, Burst: 1},
+		{BusinessUid: Wildcard, Method: "exportAddressByPublicKeys", Rate: 0.001, Burst: 2},
+		{BusinessUid: "b1", Method: Wildcard, Rate: 0.001, Burst: 3},
+		{BusinessUid: "b1", Method: "buildUnSignTransaction", Rate: 0.001, Burst: 4},
+	}}
+	limiter := newTestLimiter(t, store)
+
+	assert.Equal(t, 4, allowed(limiter, "b1", "buildUnSignTransaction", 10))
+	assert.Equal(t, 3, allowed(limiter, "b1", "exportAddressByPublicKeys", 10))
+	assert.Equal(t, 2, allowed(limiter, "b2", "exportAddressByPublicKeys", 10))
+	assert.Equal(t, 1, allowed(limiter, "b2", "getFeeReport", 10))
+	/*每个项目方独立计数*/
+	assert.Equal(t, 1, allowed(limiter, "b3", "getFeeReport", 10))
+}
+
+func TestAllowWithoutRules(t *testing.T) {
+	limiter := newTestLimiter(t, &fakeStore{})
+	assert.Equal(t, 100, allowed(limiter, "b1", "buildUnSignTransaction", 100))
+}
+
+func TestRefresh(t *testing.T) {
+	store := &fakeStore{rateLimits: []*database.RateLimits{{BusinessUid: "b1", Method: Wildcard, Rate: 0.001, Burst: 1}}}
+	limiter := newTestLimiter(t, store)
+	assert.Equal(t, 1, allowed(limiter, "b1", "getFeeReport", 5))
+
+	/*规则变更后沿用原令牌桶，调整速率与容量*/
+	store.rateLimits = []*database.RateLimits{{BusinessUid: "b1", Method: Wildcard, Rate: 1000, Burst: 5}}
+	require.NoError(t, limiter.Refresh())
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 5, allowed(limiter, "b1", "getFeeReport", 5))
+
+	/*刷新失败保留原配置*/
+	store.err = errors.New("db down")
+	assert.Error(t, limiter.Refresh())
+	store.rateLimits = nil
+	assert.Equal(t, Rule{Rate: 1000, Burst: 5}, limiter.rules[ruleKey{business: "b1", method: Wildcard}])
+}
+
+func TestQuota(t *testing.T) {
+	limiter := newTestLimiter(t, &fakeStore{quotas: []*database.BusinessQuotas{
+		{BusinessUid: Wildcard, DailyAddresses: 100, DailyWithdraws: 10},
+		{BusinessUid: "b1", DailyAddresses: 5},
+	}})
+	assert.Equal(t, Quota{DailyAddresses: 5}, limiter.Quota("b1"))
+	assert.Equal(t, Quota{DailyAddresses: 100, DailyWithdraws: 10}, limiter.Quota("b2"))
+
+	assert.Equal(t, Quota{}, newTestLimiter(t, &fakeStore{}).Quota("b1"))
+}
+
+type testRequest struct {
+	requestId     string
+	consumerToken string
+}
+
+func (r *testRequest) GetRequestId() string     { return r.requestId }
+func (r *testRequest) GetConsumerToken() string { return r.consumerToken }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	limiter := newTestLimiter(t, &fakeStore{rateLimits: []*database.RateLimits{{BusinessUid: Wildcard, Method: Wildcard, Rate: 0.001, Burst: 1}}})
+	interceptor := limiter.UnaryServerInterceptor("wallet.Service")
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/wallet.Service/buildUnSignTransaction"}
+
+	resp, err := interceptor(context.Background(), &testRequest{requestId: "b1"}, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+	_, err = interceptor(context.Background(), &testRequest{requestId: "b1"}, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	/*无 request_id 时按 consumer_token 限流*/
+	_, err = interceptor(context.Background(), &testRequest{consumerToken: "token"}, info, handler)
+	require.NoError(t, err)
+
+	/*开启鉴权时按证书对应的项目方限流，更换 request_id 不能绕过*/
+	authed := WithBusiness(context.Background(), "b2")
+	_, err = interceptor(authed, &testRequest{requestId: "b2"}, info, handler)
+	require.NoError(t, err)
+	_, err = interceptor(authed, &testRequest{requestId: "spoofed"}, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	/*其他服务不限流*/
+	other := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	for i := 0; i < 3; i++ {
+		_, err = interceptor(context.Background(), &testRequest{requestId: "b1"}, other, handler)
+		require.NoError(t, err)
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	now := time.Date(2024, 5, 6, 23, 30, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	assert.Equal(t, uint64(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC).Unix()), StartOfDay(now))
+}
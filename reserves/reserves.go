@@ -0,0 +1,261 @@
+package reserves
+
+import (
+	"context"
+	"errors"
+	"exchange-wallet-service/common/merkle"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/rpcclient"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+储备证明（proof of reserves）数据集，储备与负债取同一区块：
+  - 储备：钱包所有地址（用户、热、冷）在该区块的链上余额，直连链节点按区块查询，零余额不列出；
+    代币为主币与快照中出现过的代币（同质化代币，NFT 不在余额表中）
+  - 负债：用户余额（可用 + 锁定）构建 Merkle 树，每个用户地址、代币一个叶子，附包含证明
+
+负债取指定区块（未指定为同步器已处理的最新区块）及之前的最近一次余额快照，区块以快照区块为准；
+逐地址查询链上余额耗时较长，只由命令行离线生成并存库，rpc 接口读取已存储的报告
+*/
+type Report struct {
+	BusinessId string `json:"business_id"`
+	/*储备与负债对应的区块*/
+	BlockNumber   string            `json:"block_number"`
+	GeneratedAt   uint64            `json:"generated_at"`
+	Reserves      []*Reserve        `json:"reserves"`
+	ReserveTotals map[string]string `json:"reserve_totals"`
+	Liabilities   *Liabilities      `json:"liabilities"`
+}
+
+/*单个地址单个代币的链上余额*/
+type Reserve struct {
+	Address      string `json:"address"`
+	AddressType  string `json:"address_type"`
+	TokenAddress string `json:"token_address"`
+	Balance      string `json:"balance"`
+}
+
+/*用户余额 Merkle 树*/
+type Liabilities struct {
+	Root      string            `json:"root"`
+	LeafCount int               `json:"leaf_count"`
+	Totals    map[string]string `json:"totals"`
+	Proofs    []*Proof          `json:"proofs"`
+}
+
+/*用户余额包含证明*/
+type Proof struct {
+	Address      string             `json:"address"`
+	TokenAddress string             `json:"token_address"`
+	Balance      string             `json:"balance"`
+	LeafIndex    int                `json:"leaf_index"`
+	Leaf         string             `json:"leaf"`
+	Proof        []merkle.ProofNode `json:"proof"`
+}
+
+/*用户余额条目*/
+type userBalance struct {
+	Address      common.Address
+	TokenAddress common.Address
+	Balance      *big.Int
+}
+
+type Builder struct {
+	db            *database.DB
+	balanceReader rpcclient.BalanceReader
+}
+
+func NewBuilder(db *database.DB, balanceReader rpcclient.BalanceReader) *Builder {
+	return &Builder{db: db, balanceReader: balanceReader}
+}
+
+/*生成项目方储备证明数据集，blockNumber 为 nil 时取同步器已处理的最新区块；区块与快照须从同一个库（主库）读取*/
+func (b *Builder) Build(ctx context.Context, businessId string, blockNumber *big.Int) (*Report, error) {
+	if b.balanceReader == nil {
+		return nil, errors.New("proof of reserves requires a chain node rpc url")
+	}
+	syncedBlock, err := b.db.Blocks.LatestBlocks()
+	if err != nil {
+		return nil, fmt.Errorf("query latest synced block fail: %w", err)
+	}
+	if syncedBlock == nil {
+		return nil, errors.New("no synced block yet")
+	}
+	if blockNumber == nil {
+		blockNumber = syncedBlock.Number
+	} else if blockNumber.Cmp(syncedBlock.Number) > 0 {
+		return nil, fmt.Errorf("block number %s is ahead of last synced block %s", blockNumber, syncedBlock.Number)
+	}
+
+	snapshots, err := b.db.Snapshots.QueryBalanceAt(businessId, &database.BalanceAtQuery{BlockNumber: blockNumber})
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no balance snapshot at or before block %s", blockNumber)
+	}
+	users, reportBlock := userBalances(snapshots)
+	liabilities, err := buildLiabilities(users)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		BusinessId:    businessId,
+		BlockNumber:   reportBlock.String(),
+		GeneratedAt:   uint64(time.Now().Unix()),
+		ReserveTotals: make(map[string]string),
+		Liabilities:   liabilities,
+	}
+	addressList, err := b.db.Address.QueryAddressList(businessId)
+	if err != nil {
+		return nil, err
+	}
+	tokens := reserveTokens(snapshots)
+	totals := make(map[common.Address]*big.Int)
+	for _, address := range addressList {
+		for _, token := range tokens {
+			chainBalance, err := b.balanceReader.BalanceAt(ctx, address.Address, token, reportBlock)
+			if err != nil {
+				return nil, fmt.Errorf("get chain balance of %s at block %s fail: %w", address.Address, reportBlock, err)
+			}
+			if chainBalance.Sign() == 0 {
+				continue
+			}
+			report.Reserves = append(report.Reserves, &Reserve{
+				Address:      address.Address.String(),
+				AddressType:  address.AddressType.String(),
+				TokenAddress: token.String(),
+				Balance:      chainBalance.String(),
+			})
+			addTotal(totals, token, chainBalance)
+		}
+	}
+	for token, total := range totals {
+		report.ReserveTotals[token.String()] = total.String()
+	}
+	log.Info("build proof of reserves done", "businessId", businessId, "blockNumber", report.BlockNumber, "addresses", len(addressList), "tokens", len(tokens), "reserves", len(report.Reserves), "leaves", liabilities.LeafCount, "root", liabilities.Root)
+	return report, nil
+}
+
+/*储备统计的代币：主币与快照中出现过的代币，按地址排序*/
+func reserveTokens(snapshots []*database.BalanceSnapshots) []common.Address {
+	seen := map[common.Address]bool{{}: true}
+	tokens := []common.Address{{}}
+	for _, snapshot := range snapshots {
+		if seen[snapshot.TokenAddress] {
+			continue
+		}
+		seen[snapshot.TokenAddress] = true
+		tokens = append(tokens, snapshot.TokenAddress)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return strings.Compare(tokens[i].Hex(), tokens[j].Hex()) < 0
+	})
+	return tokens
+}
+
+/*快照中的用户余额与快照区块：每轮快照复制全部余额行，最近一轮快照的区块即负债对应的区块*/
+func userBalances(snapshots []*database.BalanceSnapshots) ([]*userBalance, *big.Int) {
+	var users []*userBalance
+	snapshotBlock := new(big.Int)
+	for _, snapshot := range snapshots {
+		if snapshot.BlockNumber != nil && snapshot.BlockNumber.Cmp(snapshotBlock) > 0 {
+			snapshotBlock.Set(snapshot.BlockNumber)
+		}
+		if snapshot.AddressType != constant.AddressTypeUser {
+			continue
+		}
+		users = append(users, &userBalance{
+			Address:      snapshot.Address,
+			TokenAddress: snapshot.TokenAddress,
+			Balance:      sum(snapshot.Balance, snapshot.LockBalance),
+		})
+	}
+	return users, snapshotBlock
+}
+
+/*
+构建负债 Merkle 树：
+叶子按 (地址, 代币) 排序，叶子数据为 地址(20字节) | 代币地址(20字节) | 余额(32字节大端)，零余额不入树
+*/
+func buildLiabilities(users []*userBalance) (*Liabilities, error) {
+	entries := make([]*userBalance, 0, len(users))
+	for _, user := range users {
+		if user.Balance == nil || user.Balance.Sign() <= 0 {
+			continue
+		}
+		entries = append(entries, user)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Address != entries[j].Address {
+			return strings.Compare(entries[i].Address.Hex(), entries[j].Address.Hex()) < 0
+		}
+		return strings.Compare(entries[i].TokenAddress.Hex(), entries[j].TokenAddress.Hex()) < 0
+	})
+
+	liabilities := &Liabilities{Totals: make(map[string]string)}
+	if len(entries) == 0 {
+		return liabilities, nil
+	}
+	leaves := make([]common.Hash, len(entries))
+	totals := make(map[common.Address]*big.Int)
+	for i, entry := range entries {
+		leaves[i] = LeafHash(entry.Address, entry.TokenAddress, entry.Balance)
+		addTotal(totals, entry.TokenAddress, entry.Balance)
+	}
+	tree, err := merkle.NewTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+	liabilities.Root = tree.Root().String()
+	liabilities.LeafCount = tree.LeafCount()
+	for token, total := range totals {
+		liabilities.Totals[token.String()] = total.String()
+	}
+	for i, entry := range entries {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			return nil, err
+		}
+		liabilities.Proofs = append(liabilities.Proofs, &Proof{
+			Address:      entry.Address.String(),
+			TokenAddress: entry.TokenAddress.String(),
+			Balance:      entry.Balance.String(),
+			LeafIndex:    i,
+			Leaf:         leaves[i].String(),
+			Proof:        proof,
+		})
+	}
+	return liabilities, nil
+}
+
+/*用户余额叶子哈希，用户可据此自行校验包含证明*/
+func LeafHash(address, tokenAddress common.Address, balance *big.Int) common.Hash {
+	return merkle.LeafHash(address.Bytes(), tokenAddress.Bytes(), common.BigToHash(balance).Bytes())
+}
+
+func addTotal(totals map[common.Address]*big.Int, token common.Address, amount *big.Int) {
+	if _, ok := totals[token]; !ok {
+		totals[token] = new(big.Int)
+	}
+	totals[token].Add(totals[token], amount)
+}
+
+func sum(values ...*big.Int) *big.Int {
+	total := new(big.Int)
+	for _, value := range values {
+		if value != nil {
+			total.Add(total, value)
+		}
+	}
+	return total
+}
@@ -0,0 +1,79 @@
+package reserves
+
+import (
+	"exchange-wallet-service/common/merkle"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	usdt  = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	alice = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	carol = common.HexToAddress("0x3333333333333333333333333333333333333333")
+)
+
+func TestBuildLiabilities(t *testing.T) {
+	users := []*userBalance{
+		{Address: carol, TokenAddress: usdt, Balance: big.NewInt(300)},
+		{Address: alice, TokenAddress: common.Address{}, Balance: big.NewInt(100)},
+		{Address: alice, TokenAddress: usdt, Balance: big.NewInt(50)},
+		{Address: bob, TokenAddress: usdt, Balance: big.NewInt(0)},
+	}
+	liabilities, err := buildLiabilities(users)
+	require.NoError(t, err)
+	/*零余额不入树*/
+	require.Equal(t, 3, liabilities.LeafCount)
+	require.Len(t, liabilities.Proofs, 3)
+	require.Equal(t, "350", liabilities.Totals[usdt.String()])
+	require.Equal(t, "100", liabilities.Totals[common.Address{}.String()])
+
+	/*叶子排序确定，证明可由用户自行校验*/
+	require.Equal(t, alice.String(), liabilities.Proofs[0].Address)
+	require.Equal(t, carol.String(), liabilities.Proofs[2].Address)
+	root := common.HexToHash(liabilities.Root)
+	for _, proof := range liabilities.Proofs {
+		balance, ok := new(big.Int).SetString(proof.Balance, 10)
+		require.True(t, ok)
+		leaf := LeafHash(common.HexToAddress(proof.Address), common.HexToAddress(proof.TokenAddress), balance)
+		require.Equal(t, proof.Leaf, leaf.String())
+		require.True(t, merkle.Verify(root, leaf, proof.Proof))
+		/*篡改余额后校验失败*/
+		tampered := LeafHash(common.HexToAddress(proof.Address), common.HexToAddress(proof.TokenAddress), new(big.Int).Add(balance, big.NewInt(1)))
+		require.False(t, merkle.Verify(root, tampered, proof.Proof))
+	}
+
+	/*输入顺序不影响根*/
+	reordered, err := buildLiabilities([]*userBalance{users[2], users[0], users[1]})
+	require.NoError(t, err)
+	require.Equal(t, liabilities.Root, reordered.Root)
+}
+
+func TestBuildLiabilitiesEmpty(t *testing.T) {
+	liabilities, err := buildLiabilities(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, liabilities.LeafCount)
+	require.Empty(t, liabilities.Root)
+}
+
+/*负债取快照中的用户余额（可用 + 锁定），报告区块为最近一轮快照区块；储备代币含主币*/
+func TestSnapshotBalances(t *testing.T) {
+	hot := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	snapshots := []*database.BalanceSnapshots{
+		{BlockNumber: big.NewInt(990), Address: alice, AddressType: constant.AddressTypeUser, TokenAddress: usdt, Balance: big.NewInt(100), LockBalance: big.NewInt(20)},
+		{BlockNumber: big.NewInt(980), Address: bob, AddressType: constant.AddressTypeUser, TokenAddress: common.Address{}, Balance: big.NewInt(5), LockBalance: big.NewInt(0)},
+		{BlockNumber: big.NewInt(990), Address: hot, AddressType: constant.AddressTypeHot, TokenAddress: usdt, Balance: big.NewInt(1000), LockBalance: big.NewInt(0)},
+	}
+	users, block := userBalances(snapshots)
+	require.Equal(t, "990", block.String())
+	require.Len(t, users, 2)
+	require.Equal(t, "120", users[0].Balance.String())
+
+	tokens := reserveTokens(snapshots)
+	require.Equal(t, []common.Address{{}, usdt}, tokens)
+}
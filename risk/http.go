@@ -0,0 +1,64 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	gresty "github.com/go-resty/resty/v2"
+	"time"
+)
+
+/*
+外部评分服务适配：POST 评分请求（JSON）到配置地址，
+响应 {"score":0~100,"action":"allow|hold|reject","reason":""}，
+未返回 action 时按分数映射
+*/
+type HTTPScorer struct {
+	client *gresty.Client
+	url    string
+	policy Policy
+}
+
+func NewHTTPScorer(url string, timeout time.Duration, policy Policy) (*HTTPScorer, error) {
+	if url == "" {
+		return nil, errors.New("risk scorer url is required")
+	}
+	client := gresty.New()
+	if timeout > 0 {
+		client.SetTimeout(timeout)
+	}
+	return &HTTPScorer{client: client, url: url, policy: policy}, nil
+}
+
+func (s *HTTPScorer) Score(ctx context.Context, request *Request) (*Result, error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(request).
+		SetResult(&Result{}).
+		Post(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("request risk scorer fail: %w", err)
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("risk scorer response %s", res.Status())
+	}
+	result, ok := res.Result().(*Result)
+	if !ok {
+		return nil, errors.New("response is not a risk result")
+	}
+	if result.Score < 0 || result.Score > MaxScore {
+		return nil, fmt.Errorf("risk score out of range: %d", result.Score)
+	}
+	if result.Action == "" {
+		result.Action = s.policy.Action(result.Score)
+		return result, nil
+	}
+	action, err := constant.ParseRiskAction(string(result.Action))
+	if err != nil {
+		return nil, err
+	}
+	result.Action = action
+	return result, nil
+}
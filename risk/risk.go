@@ -0,0 +1,72 @@
+package risk
+
+import (
+	"context"
+	"exchange-wallet-service/config"
+	"exchange-wallet-service/database/constant"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+/*评分上限*/
+const MaxScore = 100
+
+/*评分请求：充值在过确认位前评分，提现在接收前评分*/
+type Request struct {
+	BusinessId   string                   `json:"business_id"`
+	TxType       constant.TransactionType `json:"tx_type"`
+	TxHash       string                   `json:"tx_hash,omitempty"`
+	FromAddress  string                   `json:"from_address"`
+	ToAddress    string                   `json:"to_address"`
+	TokenType    constant.TokenType       `json:"token_type"`
+	TokenAddress string                   `json:"token_address"`
+	TokenId      string                   `json:"token_id"`
+	Amount       string                   `json:"amount"`
+}
+
+/*评分结果*/
+type Result struct {
+	/*0~100，越高风险越大*/
+	Score  int                 `json:"score"`
+	Action constant.RiskAction `json:"action"`
+	Reason string              `json:"reason"`
+}
+
+/*
+风险评分接口（AML/KYT），内置本地规则评分与 HTTP 外部服务适配，
+接入供应商只需实现该接口
+*/
+type RiskScorer interface {
+	Score(ctx context.Context, request *Request) (*Result, error)
+}
+
+/*分数到处置动作的映射：达到拒绝分拒绝，达到挂起分挂起，否则放行*/
+type Policy struct {
+	HoldScore   int
+	RejectScore int
+}
+
+func (p Policy) Action(score int) constant.RiskAction {
+	switch {
+	case p.RejectScore > 0 && score >= p.RejectScore:
+		return constant.RiskActionReject
+	case p.HoldScore > 0 && score >= p.HoldScore:
+		return constant.RiskActionHold
+	default:
+		return constant.RiskActionAllow
+	}
+}
+
+/*按配置新建评分器：配置了外部服务地址用 HTTP 适配，否则用本地规则*/
+func NewRiskScorer(cfg config.RiskConfig) (RiskScorer, error) {
+	policy := Policy{HoldScore: cfg.HoldScore, RejectScore: cfg.RejectScore}
+	if cfg.ScorerUrl != "" {
+		log.Info("new http risk scorer", "url", cfg.ScorerUrl, "holdScore", cfg.HoldScore, "rejectScore", cfg.RejectScore)
+		return NewHTTPScorer(cfg.ScorerUrl, cfg.ScorerTimeout, policy)
+	}
+	largeAmounts, err := ParseLargeAmounts(cfg.LargeAmounts)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("new rules risk scorer", "largeAmounts", len(largeAmounts), "holdScore", cfg.HoldScore, "rejectScore", cfg.RejectScore)
+	return NewRulesScorer(policy, LargeAmountRule(largeAmounts)), nil
+}
@@ -0,0 +1,91 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"exchange-wallet-service/database/constant"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const usdt = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+
+func TestPolicyAction(t *testing.T) {
+	policy := Policy{HoldScore: 60, RejectScore: 90}
+	assert.Equal(t, constant.RiskActionAllow, policy.Action(59))
+	assert.Equal(t, constant.RiskActionHold, policy.Action(60))
+	assert.Equal(t, constant.RiskActionReject, policy.Action(95))
+	assert.Equal(t, constant.RiskActionAllow, Policy{}.Action(100))
+}
+
+func TestRulesScorer(t *testing.T) {
+	thresholds, err := ParseLargeAmounts([]string{usdt + ":1000000000", "0x0000000000000000000000000000000000000000:10000000000000000000"})
+	require.NoError(t, err)
+	scorer := NewRulesScorer(Policy{HoldScore: 60, RejectScore: 90}, LargeAmountRule(thresholds))
+
+	small, err := scorer.Score(context.Background(), &Request{TokenAddress: usdt, Amount: "999999999"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, small.Score)
+	assert.Equal(t, constant.RiskActionAllow, small.Action)
+
+	large, err := scorer.Score(context.Background(), &Request{TokenAddress: usdt, Amount: "1000000000"})
+	require.NoError(t, err)
+	assert.Equal(t, LargeAmountScore, large.Score)
+	assert.Equal(t, constant.RiskActionHold, large.Action)
+	assert.Contains(t, large.Reason, "large amount")
+
+	unknown, err := scorer.Score(context.Background(), &Request{TokenAddress: "0x1111111111111111111111111111111111111111", Amount: "1000000000000"})
+	require.NoError(t, err)
+	assert.Equal(t, constant.RiskActionAllow, unknown.Action)
+}
+
+func TestParseLargeAmountsInvalid(t *testing.T) {
+	_, err := ParseLargeAmounts([]string{"not-an-address:1"})
+	assert.Error(t, err)
+	_, err = ParseLargeAmounts([]string{usdt + ":abc"})
+	assert.Error(t, err)
+}
+
+func TestHTTPScorer(t *testing.T) {
+	/*本地替身评分服务*/
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Header().Set("Content-Type", "application/json")
+		switch request.ToAddress {
+		case "reject":
+			_, _ = w.Write([]byte(`{"score":40,"action":"reject","reason":"vendor blacklist"}`))
+		case "score-only":
+			_, _ = w.Write([]byte(`{"score":70}`))
+		case "error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"score":10,"action":"allow"}`))
+		}
+	}))
+	defer server.Close()
+
+	scorer, err := NewHTTPScorer(server.URL, time.Second, Policy{HoldScore: 60, RejectScore: 90})
+	require.NoError(t, err)
+
+	allowed, err := scorer.Score(context.Background(), &Request{TxType: constant.TxTypeDeposit, ToAddress: "ok"})
+	require.NoError(t, err)
+	assert.Equal(t, constant.RiskActionAllow, allowed.Action)
+
+	rejected, err := scorer.Score(context.Background(), &Request{ToAddress: "reject"})
+	require.NoError(t, err)
+	assert.Equal(t, constant.RiskActionReject, rejected.Action)
+	assert.Equal(t, "vendor blacklist", rejected.Reason)
+
+	mapped, err := scorer.Score(context.Background(), &Request{ToAddress: "score-only"})
+	require.NoError(t, err)
+	assert.Equal(t, constant.RiskActionHold, mapped.Action)
+
+	_, err = scorer.Score(context.Background(), &Request{ToAddress: "error"})
+	assert.Error(t, err)
+}
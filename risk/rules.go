@@ -0,0 +1,82 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+)
+
+/*单条规则：返回加分与命中原因，未命中返回 0*/
+type Rule func(request *Request) (int, string)
+
+/*本地规则评分：各规则分数累加（封顶 100），原因拼接*/
+type RulesScorer struct {
+	policy Policy
+	rules  []Rule
+}
+
+func NewRulesScorer(policy Policy, rules ...Rule) *RulesScorer {
+	return &RulesScorer{policy: policy, rules: rules}
+}
+
+func (s *RulesScorer) Score(ctx context.Context, request *Request) (*Result, error) {
+	var (
+		score   int
+		reasons []string
+	)
+	for _, rule := range s.rules {
+		points, reason := rule(request)
+		if points <= 0 {
+			continue
+		}
+		score += points
+		reasons = append(reasons, reason)
+	}
+	score = min(score, MaxScore)
+	return &Result{
+		Score:  score,
+		Action: s.policy.Action(score),
+		Reason: strings.Join(reasons, "; "),
+	}, nil
+}
+
+/*大额规则加分，默认阈值下单笔大额即挂起*/
+var LargeAmountScore = 60
+
+/*大额规则：金额达到代币阈值即加分*/
+func LargeAmountRule(thresholds map[common.Address]*big.Int) Rule {
+	return func(request *Request) (int, string) {
+		threshold, ok := thresholds[common.HexToAddress(request.TokenAddress)]
+		if !ok {
+			return 0, ""
+		}
+		amount, ok := new(big.Int).SetString(request.Amount, 10)
+		if !ok || amount.Cmp(threshold) < 0 {
+			return 0, ""
+		}
+		return LargeAmountScore, fmt.Sprintf("amount %s reaches large amount threshold %s", amount, threshold)
+	}
+}
+
+/*解析大额阈值配置，每项格式 token地址:金额（最小单位），主币用零地址*/
+func ParseLargeAmounts(items []string) (map[common.Address]*big.Int, error) {
+	thresholds := make(map[common.Address]*big.Int)
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		address, amount, found := strings.Cut(item, ":")
+		if !found || !common.IsHexAddress(strings.TrimSpace(address)) {
+			return nil, fmt.Errorf("invalid large amount item: %s", item)
+		}
+		threshold, ok := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+		if !ok || threshold.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid large amount: %s", item)
+		}
+		thresholds[common.HexToAddress(strings.TrimSpace(address))] = threshold
+	}
+	return thresholds, nil
+}
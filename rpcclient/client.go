@@ -3,6 +3,7 @@ package rpcclient
 import (
 	"context"
 	"exchange-wallet-service/rpcclient/chainsunion"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"math/big"
@@ -20,6 +21,11 @@ func NewChainsUnionRpcClient(ctx context.Context, rpc chainsunion.ChainsUnionSer
 	return &ChainsUnionRpcClient{Ctx: ctx, ChainsRpcClient: rpc, ChainName: chainName}, nil
 }
 
+/*绑定请求上下文（链路追踪、超时），返回共享连接的副本*/
+func (c *ChainsUnionRpcClient) WithContext(ctx context.Context) *ChainsUnionRpcClient {
+	return &ChainsUnionRpcClient{Ctx: ctx, ChainName: c.ChainName, ChainsRpcClient: c.ChainsRpcClient}
+}
+
 /*根据公钥获取地址*/
 func (c *ChainsUnionRpcClient) ExportAddressByPublicKey(typeOrVersion, publicKey string) string {
 	req := &chainsunion.ConvertAddressRequest{
@@ -27,7 +33,7 @@ func (c *ChainsUnionRpcClient) ExportAddressByPublicKey(typeOrVersion, publicKey
 		Type:      typeOrVersion,
 		PublicKey: publicKey,
 	}
-	address, err := c.ChainsRpcClient.ConvertAddress(context.Background(), req)
+	address, err := c.ChainsRpcClient.ConvertAddress(c.Ctx, req)
 	if err != nil {
 		log.Error("convert address failed", "err", err)
 		return ""
@@ -128,3 +134,63 @@ func (c *ChainsUnionRpcClient) SendTx(rawTx string) (string, error) {
 	}
 	return txInfo.TxHash, nil
 }
+
+/*获取账户链上余额封装，主币 contractAddress 传 0x00*/
+func (c *ChainsUnionRpcClient) GetAccountBalance(address, contractAddress string) (*big.Int, error) {
+	req := &chainsunion.AccountRequest{
+		Chain:           c.ChainName,
+		Network:         "mainnet",
+		Address:         address,
+		ContractAddress: contractAddress,
+	}
+	accountInfo, err := c.ChainsRpcClient.GetAccount(c.Ctx, req)
+	if err != nil {
+		log.Error("get account GetAccount fail", "err", err)
+		return nil, err
+	}
+	if accountInfo.Code == chainsunion.ReturnCode_ERROR {
+		return nil, fmt.Errorf("get account balance fail: %s", accountInfo.Msg)
+	}
+	balance, ok := new(big.Int).SetString(accountInfo.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid account balance: %s", accountInfo.Balance)
+	}
+	return balance, nil
+}
+
+/*通过 chains-union-rpc 校验地址格式*/
+func (c *ChainsUnionRpcClient) ValidAddress(address string) (bool, error) {
+	req := &chainsunion.ValidAddressRequest{
+		Chain:   c.ChainName,
+		Network: "mainnet",
+		Address: address,
+	}
+	result, err := c.ChainsRpcClient.ValidAddress(c.Ctx, req)
+	if err != nil {
+		log.Error("valid address ValidAddress fail", "err", err)
+		return false, err
+	}
+	if result.Code == chainsunion.ReturnCode_ERROR {
+		return false, fmt.Errorf("valid address fail: %s", result.Msg)
+	}
+	return result.Valid, nil
+}
+
+/*检查 chains-union-rpc 可达且支持当前链*/
+func (c *ChainsUnionRpcClient) Ping(ctx context.Context) error {
+	req := &chainsunion.SupportChainsRequest{
+		Chain:   c.ChainName,
+		Network: "mainnet",
+	}
+	result, err := c.ChainsRpcClient.GetSupportChains(ctx, req)
+	if err != nil {
+		return err
+	}
+	if result.Code == chainsunion.ReturnCode_ERROR {
+		return fmt.Errorf("get support chains fail: %s", result.Msg)
+	}
+	if !result.Support {
+		return fmt.Errorf("chain %s not supported", c.ChainName)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package rpcclient
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+)
+
+/*事件日志来源（同步器解析 Transfer 事件用）*/
+type TransferLogSource interface {
+	TransferLogs(blockNumber *big.Int) ([]types.Log, error)
+}
+
+/*gas 估算来源（构建交易时估算 gasLimit 用）*/
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, from common.Address, to *common.Address, value *big.Int, data []byte) (uint64, error)
+}
+
+/*指定区块的链上余额来源（储备证明用），tokenAddress 为零地址表示主币*/
+type BalanceReader interface {
+	BalanceAt(ctx context.Context, address, tokenAddress common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+/*ERC-20 balanceOf(address)*/
+var erc20BalanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+
+/*
+直连链节点（RpcUrl）的客户端，
+chains-union-rpc 未提供事件日志等数据，这部分直接从节点获取
+*/
+type EthClient struct {
+	Ctx    context.Context
+	client *ethclient.Client
+	rpc    *rpc.Client
+}
+
+/*新建链节点客户端*/
+func NewEthClient(ctx context.Context, rpcUrl string) (*EthClient, error) {
+	log.Info("NewEthClient", "rpcUrl", rpcUrl)
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial eth node: %w", err)
+	}
+	return &EthClient{Ctx: ctx, client: client, rpc: client.Client()}, nil
+}
+
+/*获取单个区块内的全部代币转账事件日志（ERC-20/721/1155）*/
+func (c *EthClient) TransferLogs(blockNumber *big.Int) ([]types.Log, error) {
+	query := ethereum.FilterQuery{
+		FromBlock: blockNumber,
+		ToBlock:   blockNumber,
+		Topics:    [][]common.Hash{{TransferEventTopic, TransferSingleEventTopic, TransferBatchEventTopic}},
+	}
+	logs, err := c.client.FilterLogs(c.Ctx, query)
+	if err != nil {
+		log.Error("filter transfer logs fail", "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+	return logs, nil
+}
+
+/*通过 callTracer 追踪整个区块，取出内部调用产生的主币转账*/
+func (c *EthClient) InternalTransfers(blockNumber *big.Int) ([]*InternalTransfer, error) {
+	var results []txTraceResult
+	tracerConfig := map[string]interface{}{"tracer": "callTracer"}
+	if err := c.rpc.CallContext(c.Ctx, &results, "debug_traceBlockByNumber", hexutil.EncodeBig(blockNumber), tracerConfig); err != nil {
+		log.Error("trace block fail", "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+	var transfers []*InternalTransfer
+	for _, result := range results {
+		transfers = append(transfers, flattenInternalTransfers(result.TxHash, result.Result, 0)...)
+	}
+	return transfers, nil
+}
+
+/*通过 eth_estimateGas 估算交易 gas*/
+func (c *EthClient) EstimateGas(ctx context.Context, from common.Address, to *common.Address, value *big.Int, data []byte) (uint64, error) {
+	gas, err := c.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    to,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		log.Error("estimate gas fail", "from", from, "to", to, "err", err)
+		return 0, err
+	}
+	return gas, nil
+}
+
+/*指定区块的余额：主币 eth_getBalance，ERC-20 在该区块 eth_call balanceOf*/
+func (c *EthClient) BalanceAt(ctx context.Context, address, tokenAddress common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if tokenAddress == (common.Address{}) {
+		balance, err := c.client.BalanceAt(ctx, address, blockNumber)
+		if err != nil {
+			log.Error("get balance fail", "address", address, "blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+		return balance, nil
+	}
+	data := append(append([]byte{}, erc20BalanceOfSelector...), common.LeftPadBytes(address.Bytes(), 32)...)
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{To: &tokenAddress, Data: data}, blockNumber)
+	if err != nil {
+		log.Error("call balanceOf fail", "address", address, "tokenAddress", tokenAddress, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+	if len(result) < 32 {
+		return nil, fmt.Errorf("invalid balanceOf result of token %s: %x", tokenAddress, result)
+	}
+	return new(big.Int).SetBytes(result[:32]), nil
+}
+
+func (c *EthClient) Close() {
+	c.client.Close()
+}
@@ -0,0 +1,79 @@
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*本地替身节点：记录请求的区块号，主币返回 0x64，balanceOf 返回 200*/
+func newBalanceStandIn(t *testing.T, blocks *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("invalid json rpc request: %v", err)
+			return
+		}
+		var result string
+		switch req.Method {
+		case "eth_getBalance":
+			result = `"0x64"`
+		case "eth_call":
+			var call struct {
+				Input string `json:"input"`
+				Data  string `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(req.Params[0], &call))
+			input := call.Input
+			if input == "" {
+				input = call.Data
+			}
+			assert.Equal(t, hexutil.Encode(erc20BalanceOfSelector), input[:10])
+			result = `"` + hexutil.Encode(common.BigToHash(big.NewInt(200)).Bytes()) + `"`
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+			return
+		}
+		var block string
+		require.NoError(t, json.Unmarshal(req.Params[1], &block))
+		*blocks = append(*blocks, block)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + result + `}`))
+	}))
+}
+
+/*主币与 ERC-20 余额都按指定区块查询*/
+func TestEthClientBalanceAt(t *testing.T) {
+	var blocks []string
+	server := newBalanceStandIn(t, &blocks)
+	defer server.Close()
+
+	client, err := NewEthClient(context.Background(), server.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	address := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	native, err := client.BalanceAt(context.Background(), address, common.Address{}, big.NewInt(100))
+	require.NoError(t, err)
+	assert.Equal(t, "100", native.String())
+
+	token, err := client.BalanceAt(context.Background(), address, common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"), big.NewInt(100))
+	require.NoError(t, err)
+	assert.Equal(t, "200", token.String())
+
+	assert.Equal(t, []string{"0x64", "0x64"}, blocks)
+}
@@ -0,0 +1,67 @@
+package rpcclient
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+)
+
+/*内部调用转账来源（同步器识别合约钱包、交易所等内部调用转入的主币）*/
+type TraceSource interface {
+	InternalTransfers(blockNumber *big.Int) ([]*InternalTransfer, error)
+}
+
+/*内部调用产生的一笔主币转账*/
+type InternalTransfer struct {
+	TxHash common.Hash
+	From   common.Address
+	To     common.Address
+	Value  *big.Int
+	/*调用深度，顶层交易为 0*/
+	Depth int
+}
+
+/*callTracer 输出的调用帧*/
+type callFrame struct {
+	Type  string         `json:"type"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+	Error string         `json:"error"`
+	Calls []callFrame    `json:"calls"`
+}
+
+/*debug_traceBlockByNumber 单笔交易的结果*/
+type txTraceResult struct {
+	TxHash common.Hash `json:"txHash"`
+	Result callFrame   `json:"result"`
+}
+
+/*
+展开调用树，取出所有带 value 的内部调用。
+顶层调用即交易本身，已由区块交易列表处理，这里跳过；
+失败的调用连同其子调用都已回滚，整棵子树跳过
+*/
+func flattenInternalTransfers(txHash common.Hash, frame callFrame, depth int) []*InternalTransfer {
+	if frame.Error != "" {
+		return nil
+	}
+	var transfers []*InternalTransfer
+	if depth > 0 && frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		switch strings.ToUpper(frame.Type) {
+		case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+			transfers = append(transfers, &InternalTransfer{
+				TxHash: txHash,
+				From:   frame.From,
+				To:     frame.To,
+				Value:  new(big.Int).Set(frame.Value.ToInt()),
+				Depth:  depth,
+			})
+		}
+	}
+	for _, call := range frame.Calls {
+		transfers = append(transfers, flattenInternalTransfers(txHash, call, depth+1)...)
+	}
+	return transfers
+}
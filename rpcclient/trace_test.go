@@ -0,0 +1,69 @@
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+/*本地替身节点：返回固定的 callTracer 结果*/
+const traceBlockResult = `[
+  {
+    "txHash": "0x00000000000000000000000000000000000000000000000000000000000000aa",
+    "result": {
+      "type": "CALL",
+      "from": "0x1111111111111111111111111111111111111111",
+      "to": "0x2222222222222222222222222222222222222222",
+      "value": "0x0",
+      "calls": [
+        {"type": "CALL", "from": "0x2222222222222222222222222222222222222222", "to": "0x3333333333333333333333333333333333333333", "value": "0x64"},
+        {"type": "STATICCALL", "from": "0x2222222222222222222222222222222222222222", "to": "0x4444444444444444444444444444444444444444"},
+        {"type": "CALL", "from": "0x2222222222222222222222222222222222222222", "to": "0x5555555555555555555555555555555555555555", "value": "0xc8", "error": "execution reverted",
+         "calls": [{"type": "CALL", "from": "0x5555555555555555555555555555555555555555", "to": "0x6666666666666666666666666666666666666666", "value": "0x1"}]}
+      ]
+    }
+  }
+]`
+
+func newTraceStandIn(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("invalid json rpc request: %v", err)
+			return
+		}
+		assert.Equal(t, "debug_traceBlockByNumber", req.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + traceBlockResult + `}`))
+	}))
+}
+
+/*只取出成功的、带 value 的内部调用*/
+func TestEthClientInternalTransfers(t *testing.T) {
+	server := newTraceStandIn(t)
+	defer server.Close()
+
+	client, err := NewEthClient(context.Background(), server.URL)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	transfers, err := client.InternalTransfers(big.NewInt(100))
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, common.HexToHash("0xaa"), transfers[0].TxHash)
+	assert.Equal(t, common.HexToAddress("0x2222222222222222222222222222222222222222"), transfers[0].From)
+	assert.Equal(t, common.HexToAddress("0x3333333333333333333333333333333333333333"), transfers[0].To)
+	assert.Equal(t, big.NewInt(100), transfers[0].Value)
+	assert.Equal(t, 1, transfers[0].Depth)
+}
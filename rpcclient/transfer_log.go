@@ -0,0 +1,140 @@
+package rpcclient
+
+import (
+	"exchange-wallet-service/database/constant"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"math/big"
+)
+
+var (
+	/*Transfer(address,address,uint256) 事件签名，ERC-20 与 ERC-721 相同，靠 indexed 参数个数区分*/
+	TransferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	/*ERC-1155 单个转账事件签名*/
+	TransferSingleEventTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	/*ERC-1155 批量转账事件签名*/
+	TransferBatchEventTopic = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+/*TransferBatch 事件 data 部分：ids 与 values 两个数组*/
+var transferBatchArguments = func() abi.Arguments {
+	uint256Array, _ := abi.NewType("uint256[]", "", nil)
+	return abi.Arguments{{Name: "ids", Type: uint256Array}, {Name: "values", Type: uint256Array}}
+}()
+
+/*从事件日志中解析出的一笔代币转账*/
+type TokenTransfer struct {
+	TxHash       common.Hash
+	LogIndex     uint
+	TokenType    constant.TokenType
+	TokenAddress common.Address
+	/*NFT 的 token id，ERC-20 为空*/
+	TokenId *big.Int
+	From    common.Address
+	To      common.Address
+	Amount  *big.Int
+}
+
+/*
+解析代币转账事件：
+ERC-20 Transfer：topics 为 签名/from/to，data 为金额；
+ERC-721 Transfer：topics 为 签名/from/to/tokenId，金额固定为 1；
+ERC-1155 TransferSingle/TransferBatch：topics 为 签名/operator/from/to，data 为 id 与数量（批量为数组）。
+同一笔交易中的多次转账（路由、批量转账、合约钱包）各自返回一条
+*/
+func DecodeTransferLogs(logs []types.Log) []*TokenTransfer {
+	var transfers []*TokenTransfer
+	for _, item := range logs {
+		/*被重组移除的日志不处理*/
+		if item.Removed || len(item.Topics) == 0 {
+			continue
+		}
+		switch item.Topics[0] {
+		case TransferEventTopic:
+			if transfer := decodeTransfer(item); transfer != nil {
+				transfers = append(transfers, transfer)
+			}
+		case TransferSingleEventTopic:
+			if transfer := decodeTransferSingle(item); transfer != nil {
+				transfers = append(transfers, transfer)
+			}
+		case TransferBatchEventTopic:
+			transfers = append(transfers, decodeTransferBatch(item)...)
+		}
+	}
+	return transfers
+}
+
+/*ERC-20 / ERC-721 Transfer*/
+func decodeTransfer(item types.Log) *TokenTransfer {
+	transfer := &TokenTransfer{
+		TxHash:       item.TxHash,
+		LogIndex:     item.Index,
+		TokenAddress: item.Address,
+	}
+	switch {
+	case len(item.Topics) == 3 && len(item.Data) == common.HashLength:
+		transfer.TokenType = constant.TokenTypeERC20
+		transfer.Amount = new(big.Int).SetBytes(item.Data)
+	case len(item.Topics) == 4 && len(item.Data) == 0:
+		transfer.TokenType = constant.TokenTypeERC721
+		transfer.TokenId = new(big.Int).SetBytes(item.Topics[3].Bytes())
+		transfer.Amount = big.NewInt(1)
+	default:
+		return nil
+	}
+	transfer.From = common.BytesToAddress(item.Topics[1].Bytes())
+	transfer.To = common.BytesToAddress(item.Topics[2].Bytes())
+	return transfer
+}
+
+/*ERC-1155 TransferSingle*/
+func decodeTransferSingle(item types.Log) *TokenTransfer {
+	if len(item.Topics) != 4 || len(item.Data) != 2*common.HashLength {
+		return nil
+	}
+	return &TokenTransfer{
+		TxHash:       item.TxHash,
+		LogIndex:     item.Index,
+		TokenType:    constant.TokenTypeERC1155,
+		TokenAddress: item.Address,
+		TokenId:      new(big.Int).SetBytes(item.Data[:common.HashLength]),
+		From:         common.BytesToAddress(item.Topics[2].Bytes()),
+		To:           common.BytesToAddress(item.Topics[3].Bytes()),
+		Amount:       new(big.Int).SetBytes(item.Data[common.HashLength:]),
+	}
+}
+
+/*ERC-1155 TransferBatch，每个 id 拆成一笔转账*/
+func decodeTransferBatch(item types.Log) []*TokenTransfer {
+	if len(item.Topics) != 4 {
+		return nil
+	}
+	values, err := transferBatchArguments.Unpack(item.Data)
+	if err != nil || len(values) != 2 {
+		log.Warn("decode transfer batch log fail", "txHash", item.TxHash, "err", err)
+		return nil
+	}
+	ids, okIds := values[0].([]*big.Int)
+	amounts, okAmounts := values[1].([]*big.Int)
+	if !okIds || !okAmounts || len(ids) != len(amounts) {
+		return nil
+	}
+	var transfers []*TokenTransfer
+	for i := range ids {
+		transfers = append(transfers, &TokenTransfer{
+			TxHash:       item.TxHash,
+			LogIndex:     item.Index,
+			TokenType:    constant.TokenTypeERC1155,
+			TokenAddress: item.Address,
+			TokenId:      ids[i],
+			From:         common.BytesToAddress(item.Topics[2].Bytes()),
+			To:           common.BytesToAddress(item.Topics[3].Bytes()),
+			Amount:       amounts[i],
+		})
+	}
+	return transfers
+}
@@ -0,0 +1,126 @@
+package rpcclient
+
+import (
+	"exchange-wallet-service/database/constant"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func transferLog(token, from, to common.Address, amount int64, index uint) types.Log {
+	return types.Log{
+		Address: token,
+		Topics: []common.Hash{
+			TransferEventTopic,
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data:   common.BigToHash(big.NewInt(amount)).Bytes(),
+		TxHash: common.HexToHash("0x01"),
+		Index:  index,
+	}
+}
+
+/*同一笔交易中的多次转账各自解析*/
+func TestDecodeTransferLogs(t *testing.T) {
+	token := common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	router := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	userA := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	userB := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	logs := []types.Log{
+		transferLog(token, router, userA, 100, 0),
+		transferLog(token, router, userB, 200, 1),
+	}
+	transfers := DecodeTransferLogs(logs)
+
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, token, transfers[0].TokenAddress)
+	assert.Equal(t, router, transfers[0].From)
+	assert.Equal(t, userA, transfers[0].To)
+	assert.Equal(t, big.NewInt(100), transfers[0].Amount)
+	assert.Equal(t, userB, transfers[1].To)
+	assert.Equal(t, uint(1), transfers[1].LogIndex)
+}
+
+/*非代币转账事件或已移除的日志跳过*/
+func TestDecodeTransferLogsSkip(t *testing.T) {
+	token := common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	removed := transferLog(token, from, to, 1, 0)
+	removed.Removed = true
+	otherEvent := transferLog(token, from, to, 1, 1)
+	otherEvent.Topics[0] = common.HexToHash("0x02")
+
+	transfers := DecodeTransferLogs([]types.Log{removed, otherEvent})
+	assert.Empty(t, transfers)
+}
+
+/*ERC-721 Transfer：tokenId 在第 4 个 topic，数量固定为 1*/
+func TestDecodeTransferLogsERC721(t *testing.T) {
+	nft := common.HexToAddress("0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	transfers := DecodeTransferLogs([]types.Log{{
+		Address: nft,
+		Topics: []common.Hash{
+			TransferEventTopic,
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+			common.BigToHash(big.NewInt(7)),
+		},
+		TxHash: common.HexToHash("0x01"),
+	}})
+
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, constant.TokenTypeERC721, transfers[0].TokenType)
+	assert.Equal(t, big.NewInt(7), transfers[0].TokenId)
+	assert.Equal(t, big.NewInt(1), transfers[0].Amount)
+	assert.Equal(t, to, transfers[0].To)
+}
+
+/*ERC-1155 TransferSingle 与 TransferBatch（批量按 id 拆分）*/
+func TestDecodeTransferLogsERC1155(t *testing.T) {
+	nft := common.HexToAddress("0x76be3b62873462d2142405439777e971754e8e77")
+	operator := common.HexToAddress("0x9999999999999999999999999999999999999999")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	topics := func(event common.Hash) []common.Hash {
+		return []common.Hash{
+			event,
+			common.BytesToHash(operator.Bytes()),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		}
+	}
+
+	singleData := append(common.BigToHash(big.NewInt(3)).Bytes(), common.BigToHash(big.NewInt(10)).Bytes()...)
+	batchData, err := transferBatchArguments.Pack(
+		[]*big.Int{big.NewInt(4), big.NewInt(5)},
+		[]*big.Int{big.NewInt(20), big.NewInt(30)},
+	)
+	assert.NoError(t, err)
+
+	transfers := DecodeTransferLogs([]types.Log{
+		{Address: nft, Topics: topics(TransferSingleEventTopic), Data: singleData, Index: 0},
+		{Address: nft, Topics: topics(TransferBatchEventTopic), Data: batchData, Index: 1},
+	})
+
+	assert.Len(t, transfers, 3)
+	for _, transfer := range transfers {
+		assert.Equal(t, constant.TokenTypeERC1155, transfer.TokenType)
+		assert.Equal(t, from, transfer.From)
+		assert.Equal(t, to, transfer.To)
+	}
+	assert.Equal(t, big.NewInt(3), transfers[0].TokenId)
+	assert.Equal(t, big.NewInt(10), transfers[0].Amount)
+	assert.Equal(t, big.NewInt(5), transfers[2].TokenId)
+	assert.Equal(t, big.NewInt(30), transfers[2].Amount)
+	assert.Equal(t, uint(1), transfers[2].LogIndex)
+}
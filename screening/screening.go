@@ -0,0 +1,139 @@
+package screening
+
+import (
+	"bufio"
+	"exchange-wallet-service/config"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"os"
+	"strings"
+)
+
+/*筛查结果*/
+type Result struct {
+	/*命中禁止名单，交易需挂起*/
+	Denied bool
+	/*命中相似地址（地址投毒）*/
+	Lookalike bool
+	/*风险原因，未命中为空*/
+	Reason string
+}
+
+/*是否命中任一规则*/
+func (r *Result) Hit() bool {
+	return r != nil && (r.Denied || r.Lookalike)
+}
+
+/*
+地址筛查：
+1. 禁止名单：本地加载的制裁地址列表（OFAC 等），命中则挂起
+2. 相似地址：与近期交易对手地址首尾若干位相同但地址不同，疑似地址投毒
+*/
+type Screener struct {
+	denyList     map[common.Address]string
+	prefixLength int
+	suffixLength int
+	/*比对的近期交易条数*/
+	counterpartyLimit int
+}
+
+/*新建筛查器，未配置禁止名单文件时只做相似地址检测*/
+func NewScreener(cfg config.ScreeningConfig) (*Screener, error) {
+	denyList := make(map[common.Address]string)
+	if cfg.DenyListFile != "" {
+		list, err := LoadDenyList(cfg.DenyListFile)
+		if err != nil {
+			return nil, err
+		}
+		denyList = list
+	}
+	log.Info("new screener", "denyListFile", cfg.DenyListFile, "denyListSize", len(denyList), "prefixLength", cfg.LookalikePrefixLength, "suffixLength", cfg.LookalikeSuffixLength)
+	return &Screener{
+		denyList:          denyList,
+		prefixLength:      clampLength(cfg.LookalikePrefixLength),
+		suffixLength:      clampLength(cfg.LookalikeSuffixLength),
+		counterpartyLimit: cfg.CounterpartyLimit,
+	}, nil
+}
+
+/*相似地址检测比对的近期交易条数*/
+func (s *Screener) CounterpartyLimit() int {
+	return s.counterpartyLimit
+}
+
+/*首尾比较位数限制在 0~20 之间（地址共 40 位）*/
+func clampLength(length int) int {
+	return min(max(length, 0), common.AddressLength)
+}
+
+/*
+加载禁止名单文件：每行一个地址，逗号后可跟标签（来源、名单名称）；
+空行与 # 开头的注释行跳过，非法地址跳过并告警
+*/
+func LoadDenyList(path string) (map[common.Address]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open deny list file fail: %w", err)
+	}
+	defer file.Close()
+
+	denyList := make(map[common.Address]string)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		address, label, _ := strings.Cut(line, ",")
+		address = strings.TrimSpace(address)
+		if !common.IsHexAddress(address) {
+			log.Warn("skip invalid deny list address", "line", lineNumber, "address", address)
+			continue
+		}
+		label = strings.TrimSpace(label)
+		if label == "" {
+			label = "deny list"
+		}
+		denyList[common.HexToAddress(address)] = label
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read deny list file fail: %w", err)
+	}
+	return denyList, nil
+}
+
+/*筛查地址：先查禁止名单，再与近期交易对手比对相似地址*/
+func (s *Screener) Screen(address common.Address, counterparties []common.Address) *Result {
+	if s == nil {
+		return &Result{}
+	}
+	if label, ok := s.denyList[address]; ok {
+		return &Result{Denied: true, Reason: fmt.Sprintf("address %s on deny list: %s", address, label)}
+	}
+	if similar, ok := s.LookalikeOf(address, counterparties); ok {
+		return &Result{Lookalike: true, Reason: fmt.Sprintf("address %s looks like counterparty %s", address, similar)}
+	}
+	return &Result{}
+}
+
+/*返回与地址首尾相同但不相等的交易对手地址*/
+func (s *Screener) LookalikeOf(address common.Address, counterparties []common.Address) (common.Address, bool) {
+	if s.prefixLength <= 0 && s.suffixLength <= 0 {
+		return common.Address{}, false
+	}
+	target := strings.ToLower(address.Hex()[2:])
+	for _, counterparty := range counterparties {
+		if counterparty == address || counterparty == (common.Address{}) {
+			continue
+		}
+		candidate := strings.ToLower(counterparty.Hex()[2:])
+		if target[:s.prefixLength] == candidate[:s.prefixLength] &&
+			target[len(target)-s.suffixLength:] == candidate[len(candidate)-s.suffixLength:] {
+			return counterparty, true
+		}
+	}
+	return common.Address{}, false
+}
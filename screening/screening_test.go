@@ -0,0 +1,52 @@
+package screening
+
+import (
+	"exchange-wallet-service/config"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDenyList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deny.txt")
+	content := "# sanctioned addresses\n" +
+		"0x8589427373D6D84E98730D7795D8f6f8731FDA16, OFAC SDN\n" +
+		"\n" +
+		"0x722122dF12D4e14e13Ac3b6895a86e84145b6967\n" +
+		"not-an-address\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	denyList, err := LoadDenyList(path)
+	require.NoError(t, err)
+	assert.Len(t, denyList, 2)
+	assert.Equal(t, "OFAC SDN", denyList[common.HexToAddress("0x8589427373d6d84e98730d7795d8f6f8731fda16")])
+	assert.Equal(t, "deny list", denyList[common.HexToAddress("0x722122df12d4e14e13ac3b6895a86e84145b6967")])
+}
+
+func TestScreen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deny.txt")
+	require.NoError(t, os.WriteFile(path, []byte("0x8589427373D6D84E98730D7795D8f6f8731FDA16,OFAC SDN\n"), 0o600))
+	screener, err := NewScreener(config.ScreeningConfig{DenyListFile: path, LookalikePrefixLength: 4, LookalikeSuffixLength: 4})
+	require.NoError(t, err)
+
+	counterparty := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	poisoned := common.HexToAddress("0xdac1000000000000000000000000000000001ec7")
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	denied := screener.Screen(common.HexToAddress("0x8589427373d6d84e98730d7795d8f6f8731fda16"), nil)
+	assert.True(t, denied.Denied)
+	assert.Contains(t, denied.Reason, "OFAC SDN")
+
+	lookalike := screener.Screen(poisoned, []common.Address{other, counterparty})
+	assert.True(t, lookalike.Lookalike)
+	assert.False(t, lookalike.Denied)
+	assert.Contains(t, lookalike.Reason, counterparty.Hex())
+
+	/*与交易对手完全相同不算相似地址*/
+	assert.False(t, screener.Screen(counterparty, []common.Address{counterparty}).Hit())
+	assert.False(t, screener.Screen(other, []common.Address{counterparty}).Hit())
+}
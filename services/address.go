@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"strings"
+)
+
+/*地址校验不通过，携带返回码*/
+type addressError struct {
+	code exchange_wallet_go.ReturnCode
+	msg  string
+}
+
+func (e *addressError) Error() string {
+	return e.msg
+}
+
+func newAddressError(code exchange_wallet_go.ReturnCode, format string, args ...any) error {
+	return &addressError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+/*
+地址格式校验：0x 开头的 40 位十六进制；
+大小写混合时按 EIP-55 校验和校验，全小写或全大写不含校验和
+*/
+func checkAddressFormat(address string) error {
+	if !strings.HasPrefix(address, "0x") || !common.IsHexAddress(address) {
+		return newAddressError(exchange_wallet_go.ReturnCode_INVALID_ADDRESS, "invalid address format: %s", address)
+	}
+	hex := address[2:]
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && common.HexToAddress(address).Hex() != address {
+		return newAddressError(exchange_wallet_go.ReturnCode_INVALID_ADDRESS, "invalid address checksum: %s", address)
+	}
+	return nil
+}
+
+/*地址校验：本地格式与校验和，再经 chains-union-rpc 校验*/
+func (w *WalletBusinessService) checkAddress(address string) error {
+	if err := checkAddressFormat(address); err != nil {
+		return err
+	}
+	valid, err := w.chainUnionClient.ValidAddress(address)
+	if err != nil {
+		return fmt.Errorf("valid address fail: %w", err)
+	}
+	if !valid {
+		return newAddressError(exchange_wallet_go.ReturnCode_INVALID_ADDRESS, "address rejected by chain: %s", address)
+	}
+	return nil
+}
+
+/*
+交易地址校验：from、to 格式合法，to 不能为零地址；
+提现 to 不能为本项目方钱包地址（钱包内转账走内部交易），也不能为代币合约地址
+*/
+func (w *WalletBusinessService) checkTransferAddresses(requestId string, from, to, contractAddress string, transactionType constant.TransactionType) error {
+	if err := w.checkAddress(from); err != nil {
+		return err
+	}
+	if err := w.checkAddress(to); err != nil {
+		return err
+	}
+	toAddress := common.HexToAddress(to)
+	if toAddress == (common.Address{}) {
+		return newAddressError(exchange_wallet_go.ReturnCode_ZERO_ADDRESS, "to address cannot be zero address")
+	}
+	if transactionType != constant.TxTypeWithdraw {
+		return nil
+	}
+	if exist, addressType := w.db.Address.AddressExist(requestId, &toAddress); exist {
+		return newAddressError(exchange_wallet_go.ReturnCode_OWN_ADDRESS, "withdraw to own %s address %s, use internal transaction instead", addressType, to)
+	}
+	if common.IsHexAddress(contractAddress) && common.HexToAddress(contractAddress) == toAddress {
+		return newAddressError(exchange_wallet_go.ReturnCode_CONTRACT_ADDRESS, "withdraw to token contract address %s", to)
+	}
+	token, err := w.db.Tokens.QueryTokensByAddress(requestId, toAddress)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		return newAddressError(exchange_wallet_go.ReturnCode_CONTRACT_ADDRESS, "withdraw to token contract address %s", to)
+	}
+	return nil
+}
+
+/*地址校验错误转为返回码与提示，其他错误原样返回*/
+func asAddressError(err error) (*addressError, bool) {
+	var addrErr *addressError
+	if errors.As(err, &addrErr) {
+		return addrErr, true
+	}
+	return nil, false
+}
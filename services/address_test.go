@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/rpcclient"
+	"exchange-wallet-service/rpcclient/chainsunion"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+var (
+	usdt    = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	userOne = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	hotOne  = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	payee   = common.HexToAddress("0x5555555555555555555555555555555555555555")
+)
+
+/*chains-union-rpc：地址校验、账户余额与 nonce*/
+type fakeChainsUnion struct {
+	chainsunion.ChainsUnionServiceClient
+	rejected map[string]bool
+	balance  string
+	nonce    string
+}
+
+func (f *fakeChainsUnion) ValidAddress(_ context.Context, in *chainsunion.ValidAddressRequest, _ ...grpc.CallOption) (*chainsunion.ValidAddressResponse, error) {
+	return &chainsunion.ValidAddressResponse{Code: chainsunion.ReturnCode_SUCCESS, Valid: !f.rejected[in.Address]}, nil
+}
+
+func (f *fakeChainsUnion) GetAccount(_ context.Context, _ *chainsunion.AccountRequest, _ ...grpc.CallOption) (*chainsunion.AccountResponse, error) {
+	return &chainsunion.AccountResponse{Code: chainsunion.ReturnCode_SUCCESS, Balance: f.balance, Sequence: f.nonce}, nil
+}
+
+/*项目方钱包地址*/
+type fakeAddressDB struct {
+	database.AddressDB
+	owned map[common.Address]constant.AddressType
+}
+
+func (f *fakeAddressDB) AddressExist(_ string, address *common.Address) (bool, constant.AddressType) {
+	addressType, ok := f.owned[*address]
+	return ok, addressType
+}
+
+/*项目方代币合约*/
+type fakeTokensDB struct {
+	database.TokensDB
+	tokens map[common.Address]bool
+}
+
+func (f *fakeTokensDB) QueryTokensByAddress(_ string, tokenAddress common.Address) (*database.Tokens, error) {
+	if f.tokens[tokenAddress] {
+		return &database.Tokens{TokenAddress: tokenAddress}, nil
+	}
+	return nil, nil
+}
+
+func newTestService(chains *fakeChainsUnion, db *database.DB) *WalletBusinessService {
+	return &WalletBusinessService{
+		chainUnionClient: &rpcclient.ChainsUnionRpcClient{Ctx: context.Background(), ChainName: ChainName, ChainsRpcClient: chains},
+		db:               db,
+	}
+}
+
+func TestCheckAddressFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		code    exchange_wallet_go.ReturnCode
+	}{
+		{name: "checksum", address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", code: exchange_wallet_go.ReturnCode_SUCCESS},
+		{name: "all lowercase", address: "0xdac17f958d2ee523a2206206994597c13d831ec7", code: exchange_wallet_go.ReturnCode_SUCCESS},
+		{name: "all uppercase", address: "0xDAC17F958D2EE523A2206206994597C13D831EC7", code: exchange_wallet_go.ReturnCode_SUCCESS},
+		{name: "bad checksum", address: "0xdAC17F958D2ee523a2206206994597C13D831eC7", code: exchange_wallet_go.ReturnCode_INVALID_ADDRESS},
+		{name: "missing 0x", address: "dAC17F958D2ee523a2206206994597C13D831ec7", code: exchange_wallet_go.ReturnCode_INVALID_ADDRESS},
+		{name: "too short", address: "0xdAC17F958D2ee523a2206206994597C13D831e", code: exchange_wallet_go.ReturnCode_INVALID_ADDRESS},
+		{name: "not hex", address: "0xzAC17F958D2ee523a2206206994597C13D831ec7", code: exchange_wallet_go.ReturnCode_INVALID_ADDRESS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAddressFormat(tt.address)
+			if tt.code == exchange_wallet_go.ReturnCode_SUCCESS {
+				require.NoError(t, err)
+				return
+			}
+			addrErr, ok := asAddressError(err)
+			require.True(t, ok)
+			require.Equal(t, tt.code, addrErr.code)
+		})
+	}
+}
+
+/*提现 to 不能为本项目方钱包地址、代币合约地址；内部交易不受限*/
+func TestCheckTransferAddresses(t *testing.T) {
+	chains := &fakeChainsUnion{rejected: map[string]bool{"0x6666666666666666666666666666666666666666": true}}
+	db := &database.DB{
+		Address: &fakeAddressDB{owned: map[common.Address]constant.AddressType{
+			userOne: constant.AddressTypeUser,
+			hotOne:  constant.AddressTypeHot,
+		}},
+		Tokens: &fakeTokensDB{tokens: map[common.Address]bool{usdt: true}},
+	}
+	w := newTestService(chains, db)
+	tests := []struct {
+		name            string
+		to              string
+		contractAddress string
+		txType          constant.TransactionType
+		code            exchange_wallet_go.ReturnCode
+	}{
+		{name: "withdraw to external", to: payee.Hex(), contractAddress: "0x00", txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_SUCCESS},
+		{name: "withdraw to zero address", to: common.Address{}.Hex(), contractAddress: "0x00", txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_ZERO_ADDRESS},
+		{name: "withdraw to own user address", to: userOne.Hex(), contractAddress: "0x00", txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_OWN_ADDRESS},
+		{name: "withdraw to own hot wallet", to: hotOne.Hex(), contractAddress: usdt.Hex(), txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_OWN_ADDRESS},
+		{name: "withdraw to transferred token contract", to: "0x7777777777777777777777777777777777777777", contractAddress: "0x7777777777777777777777777777777777777777", txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_CONTRACT_ADDRESS},
+		{name: "withdraw to registered token contract", to: usdt.Hex(), contractAddress: "0x00", txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_CONTRACT_ADDRESS},
+		{name: "rejected by chain", to: "0x6666666666666666666666666666666666666666", contractAddress: "0x00", txType: constant.TxTypeWithdraw, code: exchange_wallet_go.ReturnCode_INVALID_ADDRESS},
+		{name: "collection to own hot wallet", to: hotOne.Hex(), contractAddress: usdt.Hex(), txType: constant.TxTypeCollection, code: exchange_wallet_go.ReturnCode_SUCCESS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := w.checkTransferAddresses("biz", userOne.Hex(), tt.to, tt.contractAddress, tt.txType)
+			if tt.code == exchange_wallet_go.ReturnCode_SUCCESS {
+				require.NoError(t, err)
+				return
+			}
+			addrErr, ok := asAddressError(err)
+			require.True(t, ok, "unexpected error: %v", err)
+			require.Equal(t, tt.code, addrErr.code)
+		})
+	}
+}
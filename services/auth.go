@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/ratelimit"
+	"exchange-wallet-service/tlsutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+/*网关转发已校验客户端证书对应项目方的 metadata key，只有进程内网关服务读取*/
+const businessMetadataKey = "x-wallet-business"
+
+/*
+项目方鉴权：开启双向 TLS 时，业务接口请求的 request_id 必须与客户端证书对应的项目方一致；
+identify 从请求上下文取项目方，直连取 TLS 对端证书，网关取转发的 metadata；
+鉴权通过的项目方写入上下文，供限流按项目方计数
+*/
+func (w *WalletBusinessService) businessAuthInterceptor(identify func(ctx context.Context) string) grpc.UnaryServerInterceptor {
+	prefix := "/" + exchange_wallet_go.WalletBusinessServices_ServiceDesc.ServiceName + "/"
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if w.WalletBusinessConfig.TLS.ClientCAFile == "" || !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		business := identify(ctx)
+		if business == "" {
+			return nil, status.Error(codes.Unauthenticated, "client certificate required")
+		}
+		request, ok := req.(interface{ GetRequestId() string })
+		if !ok || request.GetRequestId() != business {
+			return nil, status.Errorf(codes.PermissionDenied, "client certificate of business %s cannot access this request", business)
+		}
+		return handler(ratelimit.WithBusiness(ctx, business), req)
+	}
+}
+
+/*直连：TLS 对端证书对应的项目方*/
+func peerBusiness(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return tlsutil.BusinessId(&tlsInfo.State)
+}
+
+/*网关：网关转发的项目方*/
+func gatewayBusiness(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, businessMetadataKey)
+	if len(values) != 1 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,421 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/risk"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	/*批量提现 gas：基础 gas + 每笔出款 gas*/
+	BatchBaseGasLimit   uint64 = 50000
+	BatchEthPayoutGas   uint64 = 12000
+	BatchTokenPayoutGas uint64 = 40000
+	MaxBatchPayouts            = 200
+)
+
+/*disperse 合约 ABI，代币批量出款前热钱包需对 disperse 合约 approve*/
+const disperseABI = `[
+	{"type":"function","name":"disperseEther","stateMutability":"payable","inputs":[{"name":"recipients","type":"address[]"},{"name":"values","type":"uint256[]"}]},
+	{"type":"function","name":"disperseToken","inputs":[{"name":"token","type":"address"},{"name":"recipients","type":"address[]"},{"name":"values","type":"uint256[]"}]}
+]`
+
+var (
+	disperseEther abi.Method
+	disperseToken abi.Method
+)
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(disperseABI))
+	if err != nil {
+		panic(fmt.Sprintf("parse disperse abi fail: %v", err))
+	}
+	disperseEther = parsed.Methods["disperseEther"]
+	disperseToken = parsed.Methods["disperseToken"]
+}
+
+/*
+批量提现交易：热钱包调用 disperse 合约，一笔交易向多个地址出款。
+与 NFT 转账一样在本地构建，未签名交易返回待签名哈希
+*/
+type disperseTx struct {
+	ChainId              string
+	Nonce                uint64
+	FromAddress          common.Address
+	DisperseAddress      common.Address
+	TokenType            constant.TokenType
+	TokenAddress         common.Address
+	Recipients           []common.Address
+	Values               []*big.Int
+	GasLimit             uint64
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+	Legacy               bool
+}
+
+/*组装交易：主币出款交易 value 为出款总额，代币出款 value 为 0*/
+func (t *disperseTx) build() (*types.Transaction, types.Signer, error) {
+	chainId, ok := new(big.Int).SetString(t.ChainId, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid chain id: %s", t.ChainId)
+	}
+	maxFeePerGas, ok := new(big.Int).SetString(t.MaxFeePerGas, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid max fee per gas: %s", t.MaxFeePerGas)
+	}
+	maxPriorityFeePerGas, ok := new(big.Int).SetString(t.MaxPriorityFeePerGas, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid max priority fee per gas: %s", t.MaxPriorityFeePerGas)
+	}
+	if len(t.Recipients) == 0 || len(t.Recipients) != len(t.Values) {
+		return nil, nil, fmt.Errorf("invalid disperse payouts: %d recipients, %d values", len(t.Recipients), len(t.Values))
+	}
+	var (
+		data  []byte
+		value = big.NewInt(0)
+	)
+	switch t.TokenType {
+	case constant.TokenTypeETH:
+		args, err := disperseEther.Inputs.Pack(t.Recipients, t.Values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(disperseEther.ID, args...)
+		value = sumAmounts(t.Values)
+	case constant.TokenTypeERC20:
+		args, err := disperseToken.Inputs.Pack(t.TokenAddress, t.Recipients, t.Values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(disperseToken.ID, args...)
+	default:
+		return nil, nil, fmt.Errorf("unsupported batch token type: %s", t.TokenType)
+	}
+	tx := newLocalTx(chainId, t.Nonce, &t.DisperseAddress, value, data, t.GasLimit, maxFeePerGas, maxPriorityFeePerGas, t.Legacy)
+	return tx, types.LatestSignerForChainID(chainId), nil
+}
+
+/*未签名交易：返回待签名的交易哈希*/
+func (t *disperseTx) UnSignTx() (string, error) {
+	tx, signer, err := t.build()
+	if err != nil {
+		return "", err
+	}
+	return signer.Hash(tx).Hex(), nil
+}
+
+/*已签名交易：返回可广播的原始交易*/
+func (t *disperseTx) SignedTx(signature string) (string, error) {
+	tx, signer, err := t.build()
+	if err != nil {
+		return "", err
+	}
+	return applySignature(tx, signer, t.FromAddress.String(), signature)
+}
+
+/*批量提现 gas 限制*/
+func batchGasLimit(tokenType constant.TokenType, payouts int) uint64 {
+	perPayout := BatchTokenPayoutGas
+	if tokenType == constant.TokenTypeETH {
+		perPayout = BatchEthPayoutGas
+	}
+	return BatchBaseGasLimit + perPayout*uint64(payouts)
+}
+
+func sumAmounts(amounts []*big.Int) *big.Int {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		total.Add(total, amount)
+	}
+	return total
+}
+
+/*批量提现请求验证*/
+func validateBatchRequest(request *exchange_wallet_go.UnSignTransactionRequest) error {
+	if request.From == "" {
+		return errors.New("from address cannot be empty")
+	}
+	if len(request.Payouts) == 0 {
+		return errors.New("payouts cannot be empty")
+	}
+	if len(request.Payouts) > MaxBatchPayouts {
+		return fmt.Errorf("too many payouts: %d, max %d", len(request.Payouts), MaxBatchPayouts)
+	}
+	transactionType, err := constant.ParseTransactionType(request.TxType)
+	if err != nil {
+		return fmt.Errorf("invalid transaction type: %w", err)
+	}
+	if transactionType != constant.TxTypeWithdraw {
+		return fmt.Errorf("batch mode only supports withdraw, got %s", transactionType)
+	}
+	tokenType := determineTokenType(request)
+	if tokenType != constant.TokenTypeETH && tokenType != constant.TokenTypeERC20 {
+		return fmt.Errorf("batch mode does not support token type %s", tokenType)
+	}
+	/*同一批次内收款地址不能重复，重复出款应合并金额*/
+	recipients := make(map[common.Address]struct{}, len(request.Payouts))
+	for _, payout := range request.Payouts {
+		if !common.IsHexAddress(payout.To) {
+			return fmt.Errorf("invalid payout address: %s", payout.To)
+		}
+		to := common.HexToAddress(payout.To)
+		if _, ok := recipients[to]; ok {
+			return fmt.Errorf("duplicate payout address: %s", payout.To)
+		}
+		recipients[to] = struct{}{}
+		amount, ok := new(big.Int).SetString(payout.Value, 10)
+		if !ok || amount.Sign() <= 0 {
+			return fmt.Errorf("invalid payout value: %s", payout.Value)
+		}
+	}
+	return nil
+}
+
+/*
+批量提现：
+每笔出款分别做地址筛查与风险评分，挂起、拒绝的出款单独落库，不进入批次；
+放行的出款各存一条提现记录并关联批次，合并为一笔 disperse 合约调用
+*/
+func (w *WalletBusinessService) buildUnSignBatch(ctx context.Context, request *exchange_wallet_go.UnSignTransactionRequest) (*exchange_wallet_go.UnSignTransactionResponse, error) {
+	response := &exchange_wallet_go.UnSignTransactionResponse{
+		Code:     exchange_wallet_go.ReturnCode_ERROR,
+		UnSignTx: "0x00",
+	}
+	if w.WalletBusinessConfig.DisperseContract == "" || !common.IsHexAddress(w.WalletBusinessConfig.DisperseContract) {
+		response.Msg = "batch withdraw is not enabled"
+		return response, nil
+	}
+	if err := validateBatchRequest(request); err != nil {
+		return nil, fmt.Errorf("invalid request:%w", err)
+	}
+	if err := w.reserveWithdrawQuota(request.RequestId, len(request.Payouts)); err != nil {
+		return nil, err
+	}
+	feeReq, err := feeRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request:%w", err)
+	}
+	nonce, err := w.getAccountNonce(ctx, request.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %w", err)
+	}
+	feeInfo, err := w.getFeeInfo(ctx, request.RequestId, request.From, feeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee info: %w", err)
+	}
+	tokenType := determineTokenType(request)
+	gasLimit := batchGasLimit(tokenType, len(request.Payouts))
+
+	var withdraws []*database.Withdraws
+	for i, payout := range request.Payouts {
+		if err := w.checkTransferAddresses(request.RequestId, request.From, payout.To, request.ContractAddress, constant.TxTypeWithdraw); err != nil {
+			if addrErr, ok := asAddressError(err); ok {
+				response.Code = addrErr.code
+				response.Msg = fmt.Sprintf("payout %d: %s", i, addrErr.msg)
+				return response, nil
+			}
+			return nil, err
+		}
+	}
+	for _, payout := range request.Payouts {
+		payoutRequest := &exchange_wallet_go.UnSignTransactionRequest{
+			RequestId:       request.RequestId,
+			ChainId:         request.ChainId,
+			From:            request.From,
+			To:              payout.To,
+			Value:           payout.Value,
+			ContractAddress: request.ContractAddress,
+			TokenType:       request.TokenType,
+			TxType:          request.TxType,
+		}
+		guid := uuid.New()
+		amount, _ := new(big.Int).SetString(payout.Value, 10)
+		result := &exchange_wallet_go.BatchPayoutResult{
+			TransactionId: guid.String(),
+			To:            payout.To,
+			Value:         payout.Value,
+			Code:          exchange_wallet_go.ReturnCode_SUCCESS,
+		}
+		response.Payouts = append(response.Payouts, result)
+
+		riskResult, status, err := w.checkWithdraw(ctx, payoutRequest, tokenType)
+		if err != nil {
+			return nil, err
+		}
+		if status != constant.TxStatusCreateUnsigned {
+			log.Warn("batch payout not allowed, store it alone", "guid", guid, "to", payout.To, "status", status, "reason", riskResult.Reason)
+			if err := w.storeWithdraw(payoutRequest, guid, amount, gasLimit, feeInfo, constant.TxTypeWithdraw, status, riskResult); err != nil {
+				log.Error("failed to store withdraw", "guid", guid, "err", err)
+				return nil, err
+			}
+			result.Code = exchange_wallet_go.ReturnCode_RISK_HOLD
+			if status == constant.TxStatusRejected {
+				result.Code = exchange_wallet_go.ReturnCode_RISK_REJECT
+			}
+			result.RiskReason = riskResult.Reason
+			continue
+		}
+		withdraws = append(withdraws, newWithdraw(payoutRequest, guid, amount, gasLimit, feeInfo, constant.TxTypeWithdraw, status, riskResult))
+	}
+	if len(withdraws) == 0 {
+		response.Code = exchange_wallet_go.ReturnCode_RISK_HOLD
+		response.Msg = "no payout passed risk control"
+		return response, nil
+	}
+
+	/*出款按 guid 排序，与签名时查询批次内提现的顺序一致；只有部分出款放行时按实际笔数重算 gas*/
+	sort.Slice(withdraws, func(i, j int) bool {
+		return withdraws[i].GUID.String() < withdraws[j].GUID.String()
+	})
+	gasLimit = batchGasLimit(tokenType, len(withdraws))
+	var (
+		recipients []common.Address
+		values     []*big.Int
+	)
+	for _, withdraw := range withdraws {
+		withdraw.GasLimit = gasLimit
+		recipients = append(recipients, withdraw.ToAddress)
+		values = append(values, withdraw.Amount)
+	}
+	batch := &database.WithdrawBatches{
+		GUID:                 uuid.New(),
+		Timestamp:            uint64(time.Now().Unix()),
+		Status:               constant.TxStatusCreateUnsigned,
+		TxHash:               common.Hash{},
+		FromAddress:          common.HexToAddress(request.From),
+		DisperseAddress:      common.HexToAddress(w.WalletBusinessConfig.DisperseContract),
+		TokenType:            tokenType,
+		TokenAddress:         common.HexToAddress(request.ContractAddress),
+		TotalAmount:          sumAmounts(values),
+		PayoutCount:          len(withdraws),
+		GasLimit:             gasLimit,
+		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
+		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+		TxSignHex:            "",
+	}
+	batchTx := &disperseTx{
+		ChainId:              request.ChainId,
+		Nonce:                uint64(nonce),
+		FromAddress:          batch.FromAddress,
+		DisperseAddress:      batch.DisperseAddress,
+		TokenType:            batch.TokenType,
+		TokenAddress:         batch.TokenAddress,
+		Recipients:           recipients,
+		Values:               values,
+		GasLimit:             batch.GasLimit,
+		MaxFeePerGas:         batch.MaxFeePerGas,
+		MaxPriorityFeePerGas: batch.MaxPriorityFeePerGas,
+		Legacy:               feeInfo.Legacy,
+	}
+	unSignTx, err := batchTx.UnSignTx()
+	if err != nil {
+		log.Error("build batch unsign transaction fail", "batchId", batch.GUID, "err", err)
+		return nil, err
+	}
+	if err := w.db.WithdrawBatches.StoreBatch(request.RequestId, batch, withdraws); err != nil {
+		log.Error("failed to store withdraw batch", "batchId", batch.GUID, "err", err)
+		return nil, err
+	}
+	log.Info("build batch withdraw success", "requestId", request.RequestId, "batchId", batch.GUID, "payouts", len(withdraws), "total", batch.TotalAmount)
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "build unsign batch transaction success"
+	response.TransactionId = batch.GUID.String()
+	response.BatchId = batch.GUID.String()
+	response.UnSignTx = unSignTx
+	response.MaxFeePerGas = batch.MaxFeePerGas
+	response.MaxPriorityFeePerGas = batch.MaxPriorityFeePerGas
+	response.GasLimit = batch.GasLimit
+	return response, nil
+}
+
+/*批量提现签名：按存储的出款顺序重建 disperse 调用，批次与批次内提现一并更新为已签名*/
+func (w *WalletBusinessService) buildSignedBatch(ctx context.Context, request *exchange_wallet_go.SignedTransactionRequest) (*exchange_wallet_go.SignedTransactionResponse, error) {
+	response := &exchange_wallet_go.SignedTransactionResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	batch, err := w.db.WithdrawBatches.QueryBatchById(request.RequestId, request.BatchId)
+	if err != nil {
+		return nil, fmt.Errorf("query withdraw batch failed: %w", err)
+	}
+	if batch == nil {
+		response.Msg = "Withdraw batch not found"
+		return response, nil
+	}
+	if batch.Status != constant.TxStatusCreateUnsigned && batch.Status != constant.TxStatusSigned {
+		response.Msg = fmt.Sprintf("withdraw batch already %s", batch.Status)
+		return response, nil
+	}
+	withdraws, err := w.db.WithdrawBatches.QueryBatchWithdraws(request.RequestId, request.BatchId)
+	if err != nil {
+		return nil, err
+	}
+	if len(withdraws) != batch.PayoutCount {
+		return nil, fmt.Errorf("withdraw batch %s has %d payouts, expected %d", batch.GUID, len(withdraws), batch.PayoutCount)
+	}
+	nonce, err := w.getAccountNonce(ctx, batch.FromAddress.String())
+	if err != nil {
+		return nil, fmt.Errorf("get account nonce fail: %w", err)
+	}
+	batchTx := &disperseTx{
+		ChainId:              request.ChainId,
+		Nonce:                uint64(nonce),
+		FromAddress:          batch.FromAddress,
+		DisperseAddress:      batch.DisperseAddress,
+		TokenType:            batch.TokenType,
+		TokenAddress:         batch.TokenAddress,
+		GasLimit:             batch.GasLimit,
+		MaxFeePerGas:         batch.MaxFeePerGas,
+		MaxPriorityFeePerGas: batch.MaxPriorityFeePerGas,
+		Legacy:               w.WalletBusinessConfig.FeeLegacy,
+	}
+	for _, withdraw := range withdraws {
+		batchTx.Recipients = append(batchTx.Recipients, withdraw.ToAddress)
+		batchTx.Values = append(batchTx.Values, withdraw.Amount)
+	}
+	signedTx, err := batchTx.SignedTx(request.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("build batch signed transaction failed: %w", err)
+	}
+	if err := w.db.WithdrawBatches.UpdateBatchById(request.RequestId, request.BatchId, signedTx, constant.TxStatusSigned); err != nil {
+		return nil, fmt.Errorf("update withdraw batch status failed: %w", err)
+	}
+	response.SignedTx = signedTx
+	response.Msg = "build signed batch tx success"
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	return response, nil
+}
+
+/*提现风控：地址筛查命中挂起，风险评分挂起或拒绝，否则放行（待签名）*/
+func (w *WalletBusinessService) checkWithdraw(ctx context.Context, request *exchange_wallet_go.UnSignTransactionRequest, tokenType constant.TokenType) (*risk.Result, constant.TxStatus, error) {
+	screenResult, err := w.screenWithdraw(request)
+	if err != nil {
+		return nil, "", err
+	}
+	if screenResult.Hit() {
+		return &risk.Result{Action: constant.RiskActionHold, Reason: screenResult.Reason}, constant.TxStatusHold, nil
+	}
+	riskResult, err := w.scoreWithdraw(ctx, request, tokenType)
+	if err != nil {
+		return nil, "", err
+	}
+	switch riskResult.Action {
+	case constant.RiskActionHold:
+		return riskResult, constant.TxStatusHold, nil
+	case constant.RiskActionReject:
+		return riskResult, constant.TxStatusRejected, nil
+	}
+	return riskResult, constant.TxStatusCreateUnsigned, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+
+	"github.com/stretchr/testify/require"
+)
+
+/*批量提现请求验证：代币在请求级指定，批次内只有一种代币，不支持 NFT*/
+func TestValidateBatchRequest(t *testing.T) {
+	payouts := func(addresses ...string) []*exchange_wallet_go.BatchPayout {
+		var list []*exchange_wallet_go.BatchPayout
+		for _, address := range addresses {
+			list = append(list, &exchange_wallet_go.BatchPayout{To: address, Value: "100"})
+		}
+		return list
+	}
+	tooMany := make([]string, MaxBatchPayouts+1)
+	for i := range tooMany {
+		tooMany[i] = "0x5555555555555555555555555555555555555555"
+	}
+	tests := []struct {
+		name    string
+		request *exchange_wallet_go.UnSignTransactionRequest
+		err     string
+	}{
+		{
+			name:    "eth batch",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: "0x00", Payouts: payouts(payee.Hex(), userOne.Hex())},
+		},
+		{
+			name:    "erc20 batch",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: usdt.Hex(), Payouts: payouts(payee.Hex(), userOne.Hex())},
+		},
+		{
+			name:    "empty batch",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: "0x00"},
+			err:     "payouts cannot be empty",
+		},
+		{
+			name:    "too many payouts",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: "0x00", Payouts: payouts(tooMany...)},
+			err:     "too many payouts",
+		},
+		{
+			name:    "duplicate recipients",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: "0x00", Payouts: payouts(payee.Hex(), strings.ToLower(payee.Hex()))},
+			err:     "duplicate payout address",
+		},
+		{
+			name:    "nft token",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: usdt.Hex(), TokenType: string(constant.TokenTypeERC721), Payouts: payouts(payee.Hex())},
+			err:     "does not support token type",
+		},
+		{
+			name:    "not withdraw",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeCollection), ContractAddress: "0x00", Payouts: payouts(payee.Hex())},
+			err:     "only supports withdraw",
+		},
+		{
+			name:    "invalid value",
+			request: &exchange_wallet_go.UnSignTransactionRequest{From: hotOne.Hex(), TxType: string(constant.TxTypeWithdraw), ContractAddress: "0x00", Payouts: []*exchange_wallet_go.BatchPayout{{To: payee.Hex(), Value: "0"}}},
+			err:     "invalid payout value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBatchRequest(tt.request)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
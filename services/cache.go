@@ -0,0 +1,91 @@
+package services
+
+import (
+	"exchange-wallet-service/common/cache"
+	"exchange-wallet-service/common/clock"
+	"exchange-wallet-service/config"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/metrics"
+	"fmt"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/protobuf/proto"
+)
+
+/*缓存类别：列表查询与详情查询分别配置容量、过期时间*/
+type cacheKind int
+
+const (
+	listCache cacheKind = iota
+	detailCache
+)
+
+/*
+查询接口响应缓存：只缓存成功的响应。
+key 带项目方缓存版本，Finder、Withdraw、Fallback 等 worker 变更数据时在同一事务内递增版本，
+rpc 服务不需要与 worker 通信即可失效；未开启时为 nil，各方法直接跳过
+*/
+type apiCache struct {
+	list   *cache.Cache[string, proto.Message]
+	detail *cache.Cache[string, proto.Message]
+}
+
+func newApiCache(enable bool, cfg config.CacheConfig) *apiCache {
+	if !enable {
+		return nil
+	}
+	log.Info("api cache enabled", "listSize", cfg.ListSize, "listExpireTime", cfg.ListExpireTime, "detailSize", cfg.DetailSize, "detailExpireTime", cfg.DetailExpireTime)
+	return &apiCache{
+		list:   cache.New[string, proto.Message](cfg.ListSize, cfg.ListExpireTime, clock.SystemClock),
+		detail: cache.New[string, proto.Message](cfg.DetailSize, cfg.DetailExpireTime, clock.SystemClock),
+	}
+}
+
+func (c *apiCache) store(kind cacheKind) *cache.Cache[string, proto.Message] {
+	if kind == listCache {
+		return c.list
+	}
+	return c.detail
+}
+
+/*
+缓存 key：方法 + 项目方 + 缓存版本 + 请求的确定性编码；项目方为空时不走缓存。
+版本须与数据从同一个库（reader）读取，且先读版本，避免从库延迟时把旧数据缓存到新版本下；
+返回空表示不走缓存
+*/
+func (c *apiCache) key(reader *database.DB, method string, businessId string, request proto.Message) string {
+	if c == nil || businessId == "" {
+		return ""
+	}
+	version, err := reader.CacheVersions.QueryCacheVersion(businessId)
+	if err != nil {
+		log.Warn("failed to query cache version, skip api cache", "method", method, "requestId", businessId, "err", err)
+		return ""
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		log.Warn("failed to marshal request, skip api cache", "method", method, "err", err)
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%d/%x", method, businessId, version, data)
+}
+
+/*读取缓存，返回副本，调用方可以修改*/
+func (c *apiCache) get(kind cacheKind, method string, key string) (proto.Message, bool) {
+	if c == nil || key == "" {
+		return nil, false
+	}
+	response, ok := c.store(kind).Get(key)
+	metrics.RecordApiCache(method, ok)
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(response), true
+}
+
+/*写入缓存，保存副本*/
+func (c *apiCache) add(kind cacheKind, key string, response proto.Message) {
+	if c == nil || key == "" {
+		return
+	}
+	c.store(kind).Add(key, proto.Clone(response))
+}
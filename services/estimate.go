@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+/*
+手续费预估：与构建未签名交易相同的费率档位、项目方费率上限与 gasLimit 逻辑，
+返回最高花费 gasLimit * maxFeePerGas（主币最小单位），不落库
+*/
+func (w *WalletBusinessService) EstimateFee(ctx context.Context, request *exchange_wallet_go.EstimateFeeRequest) (*exchange_wallet_go.EstimateFeeResponse, error) {
+	w = w.withContext(ctx)
+	response := &exchange_wallet_go.EstimateFeeResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	contractAddress := request.ContractAddress
+	if contractAddress == "" {
+		contractAddress = "0x00"
+	}
+	txRequest := &exchange_wallet_go.UnSignTransactionRequest{
+		RequestId:            request.RequestId,
+		From:                 request.From,
+		To:                   request.To,
+		Value:                request.Value,
+		ContractAddress:      contractAddress,
+		TokenId:              request.TokenId,
+		TokenType:            request.TokenType,
+		FeeLevel:             request.FeeLevel,
+		MaxFeePerGas:         request.MaxFeePerGas,
+		MaxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+	}
+	if err := validateRequest(txRequest); err != nil {
+		response.Msg = "invalid request: " + err.Error()
+		return response, nil
+	}
+	for _, address := range []string{request.From, request.To} {
+		if err := w.checkAddress(address); err != nil {
+			if addrErr, ok := asAddressError(err); ok {
+				response.Code = addrErr.code
+				response.Msg = addrErr.msg
+				return response, nil
+			}
+			return nil, err
+		}
+	}
+	feeReq, err := feeRequest(txRequest)
+	if err != nil {
+		response.Msg = "invalid request: " + err.Error()
+		return response, nil
+	}
+	feeInfo, err := w.getFeeInfo(ctx, request.RequestId, request.From, feeReq)
+	if err != nil {
+		log.Error("failed to get fee info", "requestId", request.RequestId, "err", err)
+		response.Msg = "get fee info fail: " + err.Error()
+		return response, nil
+	}
+	gasLimit, _ := w.getGasAndContractInfo(ctx, txRequest)
+	totalFee := maxGasCost(gasLimit, feeInfo)
+
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "estimate fee success"
+	response.GasLimit = gasLimit
+	response.MaxFeePerGas = feeInfo.MaxPriorityFee.String()
+	response.MaxPriorityFeePerGas = feeInfo.MultipliedTip.String()
+	response.TotalFee = totalFee.String()
+	response.FeeLevel = string(feeReq.Level)
+	response.Legacy = feeInfo.Legacy
+	return response, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/config"
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+/*直连节点 gas 估算*/
+type fakeGasEstimator struct {
+	gas uint64
+	err error
+}
+
+func (f *fakeGasEstimator) EstimateGas(_ context.Context, _ common.Address, _ *common.Address, _ *big.Int, _ []byte) (uint64, error) {
+	return f.gas, f.err
+}
+
+/*手续费预估：gasLimit 取估算值加余量，未配置或估算失败取默认值，总花费恒为 gasLimit * maxFeePerGas*/
+func TestEstimateFeeTotal(t *testing.T) {
+	feeInfo := &FeeInfo{MaxPriorityFee: big.NewInt(30_000_000_000), MultipliedTip: big.NewInt(2_000_000_000)}
+	tests := []struct {
+		name            string
+		estimator       *fakeGasEstimator
+		contractAddress string
+		tokenType       string
+		gasLimit        uint64
+	}{
+		{name: "eth default", contractAddress: "0x00", gasLimit: EthGasLimit},
+		{name: "erc20 default", contractAddress: usdt.Hex(), gasLimit: TokenGasLimit},
+		{name: "eth estimated with margin", estimator: &fakeGasEstimator{gas: 21000}, contractAddress: "0x00", gasLimit: 25200},
+		{name: "erc20 estimated with margin", estimator: &fakeGasEstimator{gas: 50000}, contractAddress: usdt.Hex(), gasLimit: 60000},
+		{name: "eth estimate fails", estimator: &fakeGasEstimator{err: errors.New("execution reverted")}, contractAddress: "0x00", gasLimit: EthGasLimit},
+		{name: "erc20 estimate fails", estimator: &fakeGasEstimator{err: errors.New("execution reverted")}, contractAddress: usdt.Hex(), gasLimit: TokenGasLimit},
+		{name: "nft not estimated", estimator: &fakeGasEstimator{gas: 50000}, contractAddress: usdt.Hex(), tokenType: string(constant.TokenTypeERC721), gasLimit: NftGasLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WalletBusinessService{WalletBusinessConfig: &config.WalletBusinessConfig{GasLimitMargin: 20}}
+			if tt.estimator != nil {
+				w.gasEstimator = tt.estimator
+			}
+			request := &exchange_wallet_go.UnSignTransactionRequest{
+				From:            userOne.Hex(),
+				To:              payee.Hex(),
+				Value:           "1000",
+				ContractAddress: tt.contractAddress,
+				TokenType:       tt.tokenType,
+			}
+			gasLimit, _ := w.getGasAndContractInfo(context.Background(), request)
+			require.Equal(t, tt.gasLimit, gasLimit)
+			expected := new(big.Int).Mul(new(big.Int).SetUint64(tt.gasLimit), feeInfo.MaxPriorityFee)
+			require.Equal(t, expected.String(), maxGasCost(gasLimit, feeInfo).String())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"exchange-wallet-service/database"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+/*手续费报表：按交易类型、代币、日期汇总项目方钱包发出交易实际支付的手续费，跨项目方汇总只在命令行提供*/
+func (w *WalletBusinessService) GetFeeReport(ctx context.Context, request *exchange_wallet_go.FeeReportRequest) (*exchange_wallet_go.FeeReportResponse, error) {
+	w = w.withContext(ctx)
+	response := &exchange_wallet_go.FeeReportResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" {
+		response.Msg = "request id cannot be empty"
+		return response, nil
+	}
+	if request.EndTime > 0 && request.EndTime < request.StartTime {
+		response.Msg = "end time must not be before start time"
+		return response, nil
+	}
+
+	reader := w.db.Replica()
+	cacheKey := w.apiCache.key(reader, "getFeeReport", request.RequestId, request)
+	if cached, ok := w.apiCache.get(listCache, "getFeeReport", cacheKey); ok {
+		return cached.(*exchange_wallet_go.FeeReportResponse), nil
+	}
+
+	summaries, err := reader.Fees.QueryFeeSummary(request.RequestId, request.StartTime, request.EndTime)
+	if err != nil {
+		log.Error("failed to query fee summary", "requestId", request.RequestId, "err", err)
+		response.Msg = "query fee report fail"
+		return response, nil
+	}
+	response.Items = FeeReportItems(request.RequestId, summaries)
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query fee report success"
+	w.apiCache.add(listCache, cacheKey, response)
+	return response, nil
+}
+
+/*手续费汇总转为报表项，跨项目方报表（命令行 fee-report）共用*/
+func FeeReportItems(businessId string, summaries []*database.FeeSummary) []*exchange_wallet_go.FeeReportItem {
+	items := make([]*exchange_wallet_go.FeeReportItem, 0, len(summaries))
+	for _, summary := range summaries {
+		items = append(items, &exchange_wallet_go.FeeReportItem{
+			BusinessId:   businessId,
+			TxType:       string(summary.TxType),
+			TokenAddress: common.HexToAddress(summary.TokenAddress).String(),
+			Day:          summary.Day,
+			TxCount:      summary.TxCount,
+			TotalFee:     summary.TotalFee,
+		})
+	}
+	return items
+}
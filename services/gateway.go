@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/tlsutil"
+	"exchange-wallet-service/tracing"
+	"fmt"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"net"
+	"net/http"
+	"strings"
+)
+
+/*网关与进程内 gRPC 服务之间的连接缓冲区大小*/
+const gatewayBufferSize = 1024 * 1024
+
+/*
+HTTP/JSON 网关：路由见 protobuf/exchange-wallet-gateway.yaml，GET /openapi.json 返回 OpenAPI 文档；
+请求经进程内连接转发到网关专用的 gRPC 服务，与 gRPC 调用走同一拦截器链（鉴权、指标、链路追踪），
+Authorization 与 Grpc-Metadata-* 请求头转为 gRPC metadata，客户端证书对应的项目方经 metadata 转发
+*/
+func newGateway(ctx context.Context, listener *bufconn.Listener) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		/*字段名与 proto 一致（下划线），零值字段也输出*/
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			if business := tlsutil.BusinessId(r.TLS); business != "" {
+				return metadata.Pairs(businessMetadataKey, business)
+			}
+			return nil
+		}),
+	)
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxRecvMessageSize), grpc.MaxCallSendMsgSize(MaxRecvMessageSize)),
+		tracing.DialOption(),
+	}
+	if err := exchange_wallet_go.RegisterWalletBusinessServicesHandlerFromEndpoint(ctx, mux, "passthrough:///gateway", opts); err != nil {
+		return nil, fmt.Errorf("failed to register gateway: %w", err)
+	}
+	err := mux.HandlePath(http.MethodGet, "/openapi.json", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(exchange_wallet_go.OpenAPISpec); err != nil {
+			log.Error("failed to write openapi spec", "err", err)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register openapi handler: %w", err)
+	}
+	/*项目方只能由客户端证书确定，丢弃请求自带的同名 metadata*/
+	forbidden := runtime.MetadataHeaderPrefix + businessMetadataKey
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del(forbidden)
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+/*同一端口同时提供 gRPC（HTTP/2 + application/grpc）与 HTTP/JSON 网关*/
+func grpcOrGatewayHandler(gs *grpc.Server, gateway http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			gs.ServeHTTP(w, r)
+			return
+		}
+		gateway.ServeHTTP(w, r)
+	})
+}
+
+/*HTTP/1.1 与 HTTP/2；明文时为 h2c（gRPC 明文连接）*/
+func serverProtocols(tls bool) *http.Protocols {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	if tls {
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	return protocols
+}
@@ -7,12 +7,20 @@ import (
 	"exchange-wallet-service/common/json2"
 	"exchange-wallet-service/database"
 	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/fee"
 	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/risk"
 	"exchange-wallet-service/rpcclient/chainsunion"
+	"exchange-wallet-service/screening"
+	"exchange-wallet-service/tracing"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
 	"strconv"
 	"time"
@@ -27,25 +35,36 @@ var (
 	EthGasLimit   uint64 = 60000
 	TokenGasLimit uint64 = 120000
 	Min1Gwei      uint64 = 1000000000
+	/*transfer(address,uint256)*/
+	erc20TransferSelector = crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
 	//maxFeePerGas                = "135177480"
 	//maxPriorityFeePerGas        = "535177480"
 )
 
 /*项目方注册*/
 func (w *WalletBusinessService) BusinessRegister(ctx context.Context, request *exchange_wallet_go.BusinessRegisterRequest) (*exchange_wallet_go.BusinessRegisterResponse, error) {
+	w = w.withContext(ctx)
 	if request.RequestId == "" || request.NotifyUrl == "" {
 		return &exchange_wallet_go.BusinessRegisterResponse{
 			Code: exchange_wallet_go.ReturnCode_SUCCESS,
 			Msg:  "invalid requestId or NotifiUrl",
 		}, nil
 	}
+	feeCeiling, err := parseFeeCeiling(request.FeeCeiling)
+	if err != nil {
+		return &exchange_wallet_go.BusinessRegisterResponse{
+			Code: exchange_wallet_go.ReturnCode_ERROR,
+			Msg:  err.Error(),
+		}, nil
+	}
 	business := &database.Business{
 		GUID:        uuid.New(),
 		BusinessUid: request.RequestId,
 		NotifyUrl:   request.NotifyUrl,
 		Timestamp:   uint64(time.Now().Unix()),
+		FeeCeiling:  feeCeiling,
 	}
-	err := w.db.Business.StoreBusiness(business)
+	err = w.db.Business.StoreBusiness(business)
 	if err != nil {
 		log.Error("failed to store business", "business", business, "err", err)
 		return &exchange_wallet_go.BusinessRegisterResponse{
@@ -62,14 +81,30 @@ func (w *WalletBusinessService) BusinessRegister(ctx context.Context, request *e
 
 /*批量公钥转地址*/
 func (w *WalletBusinessService) ExportAddressByPublicKeys(ctx context.Context, request *exchange_wallet_go.ExportAddressRequest) (*exchange_wallet_go.ExportAddressResponse, error) {
+	w = w.withContext(ctx)
 	var (
 		retAddresses []*exchange_wallet_go.Address
 		dbAddresses  []*database.Address
 		balances     []*database.Balances
 	)
+	if err := w.reserveAddressQuota(request.RequestId, len(request.PublicKeys)); err != nil {
+		return nil, err
+	}
 
 	for _, value := range request.PublicKeys {
 		address := w.chainUnionClient.ExportAddressByPublicKey("", value.PublicKey)
+		/*公钥转换失败返回空地址，不合法的地址不入库*/
+		if err := w.checkAddress(address); err != nil {
+			log.Error("export address invalid", "publicKey", value.PublicKey, "address", address, "err", err)
+			code := exchange_wallet_go.ReturnCode_ERROR
+			if addrErr, ok := asAddressError(err); ok {
+				code = addrErr.code
+			}
+			return &exchange_wallet_go.ExportAddressResponse{
+				Code: code,
+				Msg:  "export address fail: " + err.Error(),
+			}, nil
+		}
 		item := &exchange_wallet_go.Address{
 			Type:    value.Type,
 			Address: address,
@@ -137,10 +172,16 @@ func (w *WalletBusinessService) ExportAddressByPublicKeys(ctx context.Context, r
 
 /*构建未签名交易*/
 func (w *WalletBusinessService) BuildUnSignTransaction(ctx context.Context, request *exchange_wallet_go.UnSignTransactionRequest) (*exchange_wallet_go.UnSignTransactionResponse, error) {
+	w = w.withContext(ctx)
+	logger := tracing.Logger(ctx)
 	response := &exchange_wallet_go.UnSignTransactionResponse{
 		Code:     exchange_wallet_go.ReturnCode_ERROR,
 		UnSignTx: "0x00",
 	}
+	/*批量提现*/
+	if request != nil && len(request.Payouts) > 0 {
+		return w.buildUnSignBatch(ctx, request)
+	}
 	if err := validateRequest(request); err != nil {
 		return nil, fmt.Errorf("invalid request:%w", err)
 	}
@@ -149,6 +190,21 @@ func (w *WalletBusinessService) BuildUnSignTransaction(ctx context.Context, requ
 	if err != nil {
 		return nil, fmt.Errorf("invalid transaction type: %w", err)
 	}
+	/*地址校验：格式、校验和、零地址，提现目标不能为本方钱包地址或代币合约*/
+	if err := w.checkTransferAddresses(request.RequestId, request.From, request.To, request.ContractAddress, transactionType); err != nil {
+		if addrErr, ok := asAddressError(err); ok {
+			logger.Warn("transaction address rejected", "requestId", request.RequestId, "from", request.From, "to", request.To, "reason", addrErr.msg)
+			response.Code = addrErr.code
+			response.Msg = addrErr.msg
+			return response, nil
+		}
+		return nil, err
+	}
+	if transactionType == constant.TxTypeWithdraw {
+		if err := w.reserveWithdrawQuota(request.RequestId, 1); err != nil {
+			return nil, err
+		}
+	}
 	amountBig, ok := new(big.Int).SetString(request.Value, 10)
 	if !ok {
 		return nil, fmt.Errorf("invalid amount: %s", request.Value)
@@ -158,47 +214,176 @@ func (w *WalletBusinessService) BuildUnSignTransaction(ctx context.Context, requ
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account nonce: %w", err)
 	}
-	feeInfo, err := w.getFeeInfo(ctx, request.From)
+	feeReq, err := feeRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request:%w", err)
+	}
+	feeInfo, err := w.getFeeInfo(ctx, request.RequestId, request.From, feeReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get fee info: %w", err)
 	}
-	gasLimit, contractAddress := w.getGasAndContractInfo(request.ContractAddress)
+	gasLimit, contractAddress := w.getGasAndContractInfo(ctx, request)
+	tokenType := determineTokenType(request)
+	if isNftTokenType(tokenType) {
+		/*NFT 统一存 0x 开头的十六进制 token id，与扫链记录一致*/
+		tokenId, _ := math.ParseBig256(request.TokenId)
+		request.TokenId = hexutil.EncodeBig(tokenId)
+	}
 
-	var returnTx *chainsunion.UnSignTransactionResponse
 	/*开启事务*/
 	switch transactionType {
 	/*似乎用不到，充值交易是扫链触发的，而不是业务方调用*/
 	case constant.TxTypeDeposit:
 		err := w.StoreDeposits(ctx, request, guid, amountBig, gasLimit, feeInfo, transactionType)
 		if err != nil {
-			log.Error("failed to store deposit", "guid", guid, "err", err)
+			logger.Error("failed to store deposit", "guid", guid, "err", err)
 			return nil, err
 		}
 	case constant.TxTypeWithdraw:
-		if err := w.storeWithdraw(request, guid, amountBig, gasLimit, feeInfo, transactionType); err != nil {
-			log.Error("failed to store withdraw", "guid", guid, "err", err)
+		/*提现目标地址筛查：命中禁止名单或相似地址则挂起，不返回待签名交易*/
+		result, err := w.screenWithdraw(request)
+		if err != nil {
+			return nil, err
+		}
+		if result.Hit() {
+			logger.Warn("withdraw to address hit screening, hold it", "guid", guid, "to", request.To, "reason", result.Reason)
+			screenResult := &risk.Result{Action: constant.RiskActionHold, Reason: result.Reason}
+			if err := w.storeWithdraw(request, guid, amountBig, gasLimit, feeInfo, transactionType, constant.TxStatusHold, screenResult); err != nil {
+				logger.Error("failed to store withdraw", "guid", guid, "err", err)
+				return nil, err
+			}
+			response.Code = exchange_wallet_go.ReturnCode_RISK_HOLD
+			response.Msg = "withdraw held by address screening"
+			response.TransactionId = guid.String()
+			response.RiskReason = result.Reason
+			return response, nil
+		}
+		/*风险评分：挂起或拒绝都落库留痕，不返回待签名交易*/
+		riskResult, err := w.scoreWithdraw(ctx, request, tokenType)
+		if err != nil {
+			return nil, err
+		}
+		switch riskResult.Action {
+		case constant.RiskActionHold, constant.RiskActionReject:
+			status, code := constant.TxStatusHold, exchange_wallet_go.ReturnCode_RISK_HOLD
+			if riskResult.Action == constant.RiskActionReject {
+				status, code = constant.TxStatusRejected, exchange_wallet_go.ReturnCode_RISK_REJECT
+			}
+			logger.Warn("withdraw hit risk scoring", "guid", guid, "to", request.To, "score", riskResult.Score, "action", riskResult.Action, "reason", riskResult.Reason)
+			if err := w.storeWithdraw(request, guid, amountBig, gasLimit, feeInfo, transactionType, status, riskResult); err != nil {
+				logger.Error("failed to store withdraw", "guid", guid, "err", err)
+				return nil, err
+			}
+			response.Code = code
+			response.Msg = fmt.Sprintf("withdraw %s by risk scoring", status)
+			response.TransactionId = guid.String()
+			response.RiskReason = riskResult.Reason
+			return response, nil
+		}
+		if err := w.storeWithdraw(request, guid, amountBig, gasLimit, feeInfo, transactionType, constant.TxStatusCreateUnsigned, riskResult); err != nil {
+			logger.Error("failed to store withdraw", "guid", guid, "err", err)
 			return nil, err
 		}
-	case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot:
-		if err := w.storeInternal(request, guid, amountBig, gasLimit, feeInfo, transactionType); err != nil {
-			log.Error("failed to store internal", "guid", guid, "err", err)
+	case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot, constant.TxTypeGasFunding:
+		/*代币归集：用户地址主币不足以支付 gas 时，先构建热钱包到用户地址的 gas 补充交易*/
+		var gasFunding *database.Internals
+		if transactionType == constant.TxTypeCollection && tokenType != constant.TokenTypeETH {
+			var gasFundingTx string
+			gasFunding, gasFundingTx, err = w.prepareGasFunding(ctx, request, gasLimit, feeInfo)
+			if err != nil {
+				logger.Error("failed to prepare gas funding", "guid", guid, "err", err)
+				return nil, err
+			}
+			if gasFunding != nil {
+				response.GasFundingTransactionId = gasFunding.GUID.String()
+				response.GasFundingUnSignTx = gasFundingTx
+			}
+		}
+		if err := w.storeInternal(request, guid, amountBig, gasLimit, feeInfo, transactionType, gasFunding); err != nil {
+			logger.Error("failed to store internal", "guid", guid, "err", err)
 			return nil, err
 		}
 	default:
-		log.Error("invalid transaction type", "transactionType", transactionType)
+		logger.Error("invalid transaction type", "transactionType", transactionType)
 		err := errors.New("invalid transaction type")
 		return nil, err
 	}
 
+	/*NFT 转账本地构建*/
+	if isNftTokenType(tokenType) {
+		nftTx := &nftTransferTx{
+			ChainId:              request.ChainId,
+			Nonce:                uint64(nonce),
+			FromAddress:          request.From,
+			ToAddress:            request.To,
+			ContractAddress:      contractAddress,
+			TokenType:            tokenType,
+			TokenId:              request.TokenId,
+			Amount:               request.Value,
+			GasLimit:             gasLimit,
+			MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
+			MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+			Legacy:               feeInfo.Legacy,
+		}
+		unSignTx, err := nftTx.UnSignTx()
+		if err != nil {
+			logger.Error("build nft unsign transaction fail", "guid", guid, "err", err)
+			return nil, err
+		}
+		response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+		response.Msg = "build unsign transaction success"
+		response.TransactionId = guid.String()
+		response.UnSignTx = unSignTx
+		response.MaxFeePerGas = feeInfo.MaxPriorityFee.String()
+		response.MaxPriorityFeePerGas = feeInfo.MultipliedTip.String()
+		response.GasLimit = gasLimit
+		return response, nil
+	}
+
+	unSignTx, err := w.buildUnSignTx(ctx, request.ChainId, uint64(nonce), request.From, request.To, request.Value, contractAddress, gasLimit, feeInfo)
+	if err != nil {
+		return nil, err
+	}
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "build unsign transaction success"
+	response.TransactionId = guid.String()
+	response.UnSignTx = unSignTx
+	response.MaxFeePerGas = feeInfo.MaxPriorityFee.String()
+	response.MaxPriorityFeePerGas = feeInfo.MultipliedTip.String()
+	response.GasLimit = gasLimit
+	return response, nil
+}
+
+/*构建未签名交易（主币、ERC-20）：EIP-1559 通过 chains-union-rpc 构建，legacy 定价时本地构建 type-0 交易*/
+func (w *WalletBusinessService) buildUnSignTx(ctx context.Context, chainId string, nonce uint64,
+	fromAddress, toAddress, amount, contractAddress string, gasLimit uint64, feeInfo *FeeInfo) (string, error) {
+	if feeInfo.Legacy {
+		legacyTx := &legacyTransferTx{
+			ChainId:         chainId,
+			Nonce:           nonce,
+			FromAddress:     fromAddress,
+			ToAddress:       toAddress,
+			ContractAddress: contractAddress,
+			Amount:          amount,
+			GasLimit:        gasLimit,
+			GasPrice:        feeInfo.MaxPriorityFee.String(),
+		}
+		unSignTx, err := legacyTx.UnSignTx()
+		if err != nil {
+			log.Error("build legacy unsign transaction fail", "err", err)
+			return "", err
+		}
+		return unSignTx, nil
+	}
 	dynamicFeeTxReq := Eip1559DynamicFeeTx{
-		ChainId:              request.ChainId,
-		Nonce:                uint64(nonce),
-		FromAddress:          request.From,
-		ToAddress:            request.To,
+		ChainId:              chainId,
+		Nonce:                nonce,
+		FromAddress:          fromAddress,
+		ToAddress:            toAddress,
 		GasLimit:             gasLimit,                        /*gas 总限制*/
 		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(), /*每单位最大 gas = baseFee + priorityFee*/
 		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),  /*矿工优先费*/
-		Amount:               request.Value,
+		Amount:               amount,
 		ContractAddress:      contractAddress,
 	}
 	data := json2.ToJSON(dynamicFeeTxReq)
@@ -210,30 +395,80 @@ func (w *WalletBusinessService) BuildUnSignTransaction(ctx context.Context, requ
 		Base64Tx: base64Str,
 	}
 	log.Info("WalletBusinessService CreateUnSignTransaction unsignTx", "unsignTx", json2.ToJSONString(unsignTx))
-	returnTx, err = w.chainUnionClient.ChainsRpcClient.BuildUnSignTransaction(ctx, unsignTx)
+	returnTx, err := w.chainUnionClient.ChainsRpcClient.BuildUnSignTransaction(ctx, unsignTx)
 	log.Info("WalletBusinessService CreateUnSignTransaction returnTx", "returnTx", json2.ToJSONString(returnTx))
 	if err != nil {
 		log.Error("WalletBusinessService CreateUnSignTransaction returnTx", "err", err)
-		return nil, err
+		return "", err
 	}
-	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
-	response.Msg = "build unsign transaction success"
-	response.TransactionId = guid.String()
-	response.UnSignTx = returnTx.UnSignTx
-	return response, nil
+	return returnTx.UnSignTx, nil
+}
+
+/*
+代币归集 gas 补充：
+归集 gas = gasLimit * maxFeePerGas，用户地址链上主币余额不足时，
+从热钱包（归集目标地址）转入差额，返回补充交易记录与其未签名交易；余额足够返回 nil
+*/
+func (w *WalletBusinessService) prepareGasFunding(ctx context.Context, request *exchange_wallet_go.UnSignTransactionRequest,
+	gasLimit uint64, feeInfo *FeeInfo) (*database.Internals, string, error) {
+	gasNeeded := maxGasCost(gasLimit, feeInfo)
+	balance, err := w.chainUnionClient.GetAccountBalance(request.From, "0x00")
+	if err != nil {
+		return nil, "", fmt.Errorf("get user native balance fail: %w", err)
+	}
+	if balance.Cmp(gasNeeded) >= 0 {
+		return nil, "", nil
+	}
+	amount := new(big.Int).Sub(gasNeeded, balance)
+
+	nonce, err := w.getAccountNonce(ctx, request.To)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get hot wallet nonce: %w", err)
+	}
+	unSignTx, err := w.buildUnSignTx(ctx, request.ChainId, uint64(nonce), request.To, request.From, amount.String(), "0x00", EthGasLimit, feeInfo)
+	if err != nil {
+		return nil, "", err
+	}
+	gasFunding := &database.Internals{
+		GUID:                 uuid.New(),
+		Timestamp:            uint64(time.Now().Unix()),
+		Status:               constant.TxStatusCreateUnsigned,
+		BlockHash:            common.Hash{},
+		BlockNumber:          big.NewInt(1),
+		TxHash:               common.Hash{},
+		TxType:               constant.TxTypeGasFunding,
+		FromAddress:          common.HexToAddress(request.To),
+		ToAddress:            common.HexToAddress(request.From),
+		Amount:               amount,
+		GasLimit:             EthGasLimit,
+		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
+		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+		TokenType:            constant.TokenTypeETH,
+		TokenAddress:         common.Address{},
+		TxSignHex:            "",
+	}
+	log.Info("user address needs gas funding before collection", "requestId", request.RequestId, "user", request.From, "gasNeeded", gasNeeded, "balance", balance, "amount", amount)
+	return gasFunding, unSignTx, nil
 }
 
 /*构建已签名交易*/
 func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, request *exchange_wallet_go.SignedTransactionRequest) (*exchange_wallet_go.SignedTransactionResponse, error) {
+	w = w.withContext(ctx)
 	response := &exchange_wallet_go.SignedTransactionResponse{
 		Code: exchange_wallet_go.ReturnCode_ERROR,
 	}
+	/*批量提现签名*/
+	if request.BatchId != "" {
+		return w.buildSignedBatch(ctx, request)
+	}
 	/*1. 从数据库中获取交易类型*/
 	var (
 		fromAddress          string
 		toAddress            string
 		amount               string
 		tokenAddress         string
+		tokenType            constant.TokenType
+		tokenId              string
 		gasLimit             uint64
 		maxFeePerGas         string
 		maxPriorityFeePerGas string
@@ -256,6 +491,8 @@ func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, requ
 		toAddress = tx.ToAddress.String()
 		amount = tx.Amount.String()
 		tokenAddress = tx.TokenAddress.String()
+		tokenType = tx.TokenType
+		tokenId = tx.TokenId
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
@@ -268,14 +505,32 @@ func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, requ
 			response.Msg = "Withdraw transaction not found"
 			return response, nil
 		}
+		/*筛查挂起、风险评分挂起或拒绝的提现不签名*/
+		if tx.Status == constant.TxStatusHold || tx.Status == constant.TxStatusHoldNotified {
+			response.Code = exchange_wallet_go.ReturnCode_RISK_HOLD
+			response.Msg = "withdraw held by risk control: " + tx.RiskReason
+			return response, nil
+		}
+		if tx.Status == constant.TxStatusRejected {
+			response.Code = exchange_wallet_go.ReturnCode_RISK_REJECT
+			response.Msg = "withdraw rejected by risk scoring: " + tx.RiskReason
+			return response, nil
+		}
+		/*批次内的提现随批次签名*/
+		if tx.BatchId != "" {
+			response.Msg = "withdraw belongs to batch " + tx.BatchId + ", sign the batch instead"
+			return response, nil
+		}
 		fromAddress = tx.FromAddress.String()
 		toAddress = tx.ToAddress.String()
 		amount = tx.Amount.String()
 		tokenAddress = tx.TokenAddress.String()
+		tokenType = tx.TokenType
+		tokenId = tx.TokenId
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
-	case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot:
+	case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot, constant.TxTypeGasFunding:
 		tx, err := w.db.Internals.QueryInternalsById(request.RequestId, request.TransactionId)
 		if err != nil {
 			return nil, fmt.Errorf("query internal failed: %w", err)
@@ -288,6 +543,8 @@ func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, requ
 		toAddress = tx.ToAddress.String()
 		amount = tx.Amount.String()
 		tokenAddress = tx.TokenAddress.String()
+		tokenType = tx.TokenType
+		tokenId = tx.TokenId
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
@@ -303,10 +560,81 @@ func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, requ
 		return nil, fmt.Errorf("get account nonce fail: %w", err)
 	}
 
-	/*3. 构建 EIP-1159 交易类型*/
+	/*3. 构建 EIP-1159 交易类型，NFT 转账本地组装*/
+	var signedTx string
+	if isNftTokenType(tokenType) {
+		nftTx := &nftTransferTx{
+			ChainId:              request.ChainId,
+			Nonce:                uint64(nonce),
+			FromAddress:          fromAddress,
+			ToAddress:            toAddress,
+			ContractAddress:      tokenAddress,
+			TokenType:            tokenType,
+			TokenId:              tokenId,
+			Amount:               amount,
+			GasLimit:             gasLimit,
+			MaxFeePerGas:         maxFeePerGas,
+			MaxPriorityFeePerGas: maxPriorityFeePerGas,
+			Legacy:               w.WalletBusinessConfig.FeeLegacy,
+		}
+		signedTx, err = nftTx.SignedTx(request.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("build nft signed transaction failed: %w", err)
+		}
+	} else {
+		signedTx, err = w.buildSignedTx(ctx, request, fromAddress, toAddress, amount, tokenAddress, uint64(nonce), gasLimit, maxFeePerGas, maxPriorityFeePerGas)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	/*4. 更新数据库状态*/
+	var updateErr error
+	switch transactionType {
+	case constant.TxTypeDeposit:
+		updateErr = w.db.Deposits.UpdateDepositById(request.RequestId, request.TransactionId, signedTx, constant.TxStatusSigned)
+	case constant.TxTypeWithdraw:
+		updateErr = w.db.Withdraws.UpdateWithdrawById(request.RequestId, request.TransactionId, signedTx, constant.TxStatusSigned)
+	case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot, constant.TxTypeGasFunding:
+		updateErr = w.db.Internals.UpdateInternalById(request.RequestId, request.TransactionId, signedTx, constant.TxStatusSigned)
+	default:
+		response.Msg = "Unsupported transaction type"
+		response.SignedTx = "0x00"
+		return response, nil
+	}
+	if updateErr != nil {
+		return nil, fmt.Errorf("update transaction status failed: %w", updateErr)
+	}
+	response.SignedTx = signedTx
+	response.Msg = "build signed tx success"
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	return response, nil
+}
+
+/*构建已签名交易（主币、ERC-20）：EIP-1559 通过 chains-union-rpc 构建，legacy 定价时本地组装，gasPrice 取 maxFeePerGas*/
+func (w *WalletBusinessService) buildSignedTx(ctx context.Context, request *exchange_wallet_go.SignedTransactionRequest,
+	fromAddress, toAddress, amount, tokenAddress string, nonce, gasLimit uint64, maxFeePerGas, maxPriorityFeePerGas string) (string, error) {
+	if w.WalletBusinessConfig.FeeLegacy {
+		legacyTx := &legacyTransferTx{
+			ChainId:         request.ChainId,
+			Nonce:           nonce,
+			FromAddress:     fromAddress,
+			ToAddress:       toAddress,
+			ContractAddress: tokenAddress,
+			Amount:          amount,
+			GasLimit:        gasLimit,
+			GasPrice:        maxFeePerGas,
+		}
+		signedTx, err := legacyTx.SignedTx(request.Signature)
+		if err != nil {
+			return "", fmt.Errorf("build legacy signed transaction failed: %w", err)
+		}
+		return signedTx, nil
+	}
+	/*构建 EIP-1159 交易类型*/
 	dynamicFeeTx := Eip1559DynamicFeeTx{
 		ChainId:              request.ChainId,
-		Nonce:                uint64(nonce),
+		Nonce:                nonce,
 		FromAddress:          fromAddress,
 		ToAddress:            toAddress,
 		GasLimit:             gasLimit,
@@ -316,7 +644,7 @@ func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, requ
 		ContractAddress:      tokenAddress,
 	}
 
-	/*4. 构建已签名交易*/
+	/*构建已签名交易*/
 	data := json2.ToJSON(&dynamicFeeTx)
 	base64Str := base64.StdEncoding.EncodeToString(data)
 	signedTxReq := &chainsunion.SignedTransactionRequest{
@@ -330,48 +658,33 @@ func (w *WalletBusinessService) BuildSignedTransaction(ctx context.Context, requ
 	returnTx, err := w.chainUnionClient.ChainsRpcClient.BuildSignedTransaction(ctx, signedTxReq)
 	log.Info("BuildSignedTransaction request", "returnTx", json2.ToJSONString(returnTx))
 	if err != nil {
-		return nil, fmt.Errorf("build signed transaction failed: %w", err)
-	}
-
-	/*5. 更新数据库状态*/
-	var updateErr error
-	switch transactionType {
-	case constant.TxTypeDeposit:
-		updateErr = w.db.Deposits.UpdateDepositById(request.RequestId, request.TransactionId, returnTx.SignedTx, constant.TxStatusSigned)
-	case constant.TxTypeWithdraw:
-		updateErr = w.db.Withdraws.UpdateWithdrawById(request.RequestId, request.TransactionId, returnTx.SignedTx, constant.TxStatusSigned)
-	case constant.TxTypeCollection, constant.TxTypeHot2Cold, constant.TxTypeCold2Hot:
-		updateErr = w.db.Internals.UpdateInternalById(request.RequestId, request.TransactionId, returnTx.SignedTx, constant.TxStatusSigned)
-	default:
-		response.Msg = "Unsupported transaction type"
-		response.SignedTx = "0x00"
-		return response, nil
+		return "", fmt.Errorf("build signed transaction failed: %w", err)
 	}
-	if updateErr != nil {
-		return nil, fmt.Errorf("update transaction status failed: %w", updateErr)
-	}
-	response.SignedTx = returnTx.SignedTx
-	response.Msg = "build signed tx success"
-	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
-	return response, nil
+	return returnTx.SignedTx, nil
 }
 
 /*设定支持的 token 合约*/
 func (w *WalletBusinessService) SetTokenAddress(ctx context.Context, request *exchange_wallet_go.SetTokenAddressRequest) (*exchange_wallet_go.SetTokenAddressResponse, error) {
+	w = w.withContext(ctx)
 	var (
 		tokenList []database.Tokens
 	)
 	for _, value := range request.TokenList {
 		CollectAmountBigInt, _ := new(big.Int).SetString(value.CollectAmount, 10)
 		ColdAmountBigInt, _ := new(big.Int).SetString(value.ColdAmount, 10)
+		MinDepositAmountBigInt, ok := new(big.Int).SetString(value.MinDepositAmount, 10)
+		if !ok {
+			MinDepositAmountBigInt = big.NewInt(0)
+		}
 		token := database.Tokens{
-			GUID:          uuid.New(),
-			TokenAddress:  common.HexToAddress(value.Address),
-			Decimals:      uint8(value.Decimals),
-			TokenName:     value.TokenName,
-			CollectAmount: CollectAmountBigInt,
-			ColdAmount:    ColdAmountBigInt,
-			Timestamp:     uint64(time.Now().Unix()),
+			GUID:             uuid.New(),
+			TokenAddress:     common.HexToAddress(value.Address),
+			Decimals:         uint8(value.Decimals),
+			TokenName:        value.TokenName,
+			CollectAmount:    CollectAmountBigInt,
+			ColdAmount:       ColdAmountBigInt,
+			MinDepositAmount: MinDepositAmountBigInt,
+			Timestamp:        uint64(time.Now().Unix()),
 		}
 		tokenList = append(tokenList, token)
 	}
@@ -389,6 +702,29 @@ func (w *WalletBusinessService) SetTokenAddress(ctx context.Context, request *ex
 
 }
 
+/*设置项目方 gas 费率上限*/
+func (w *WalletBusinessService) SetFeeCeiling(ctx context.Context, request *exchange_wallet_go.SetFeeCeilingRequest) (*exchange_wallet_go.SetFeeCeilingResponse, error) {
+	w = w.withContext(ctx)
+	feeCeiling, err := parseFeeCeiling(request.FeeCeiling)
+	if err != nil {
+		return &exchange_wallet_go.SetFeeCeilingResponse{
+			Code: exchange_wallet_go.ReturnCode_ERROR,
+			Msg:  err.Error(),
+		}, nil
+	}
+	if err := w.db.Business.UpdateFeeCeiling(request.RequestId, feeCeiling); err != nil {
+		log.Error("failed to update fee ceiling", "requestId", request.RequestId, "err", err)
+		return &exchange_wallet_go.SetFeeCeilingResponse{
+			Code: exchange_wallet_go.ReturnCode_ERROR,
+			Msg:  "set fee ceiling fail",
+		}, nil
+	}
+	return &exchange_wallet_go.SetFeeCeilingResponse{
+		Code: exchange_wallet_go.ReturnCode_SUCCESS,
+		Msg:  "set fee ceiling success",
+	}, nil
+}
+
 /*请求验证*/
 func validateRequest(request *exchange_wallet_go.UnSignTransactionRequest) error {
 	if request == nil {
@@ -403,6 +739,20 @@ func validateRequest(request *exchange_wallet_go.UnSignTransactionRequest) error
 	if request.Value == "" {
 		return errors.New("value cannot be empty")
 	}
+	if request.TokenType != "" {
+		switch constant.TokenType(request.TokenType) {
+		case constant.TokenTypeETH, constant.TokenTypeERC20:
+		case constant.TokenTypeERC721, constant.TokenTypeERC1155:
+			if !common.IsHexAddress(request.ContractAddress) {
+				return errors.New("nft contract address invalid")
+			}
+			if _, ok := math.ParseBig256(request.TokenId); !ok {
+				return errors.New("nft token id invalid")
+			}
+		default:
+			return fmt.Errorf("invalid token type: %s", request.TokenType)
+		}
+	}
 	return nil
 }
 
@@ -423,12 +773,62 @@ func (w *WalletBusinessService) getAccountNonce(ctx context.Context, address str
 	return strconv.Atoi(accountInfo.Sequence)
 }
 
-/*获取默认 gasLimit*/
-func (w *WalletBusinessService) getGasAndContractInfo(contractAddress string) (uint64, string) {
+/*
+获取 gasLimit：配置了 gas 估算时按主币转账或代币合约 transfer 估算并加安全余量，
+未配置或估算失败使用默认 gasLimit，NFT 转账使用 NFT 默认 gasLimit
+*/
+func (w *WalletBusinessService) getGasAndContractInfo(ctx context.Context, request *exchange_wallet_go.UnSignTransactionRequest) (uint64, string) {
+	gasLimit, contractAddress := TokenGasLimit, request.ContractAddress
+	if request.ContractAddress == "0x00" {
+		gasLimit, contractAddress = EthGasLimit, "0x00"
+	}
+	if isNftTokenType(determineTokenType(request)) {
+		return NftGasLimit, contractAddress
+	}
+	if w.gasEstimator == nil {
+		return gasLimit, contractAddress
+	}
+	ctx, span := tracing.Start(ctx, "gas.estimate", attribute.String("contract.address", contractAddress))
+	estimated, err := w.estimateTransferGas(ctx, request.From, request.To, request.Value, contractAddress)
+	tracing.End(span, err)
+	if err != nil {
+		tracing.Logger(ctx).Warn("estimate gas fail, use default gas limit", "contractAddress", contractAddress, "gasLimit", gasLimit, "err", err)
+		return gasLimit, contractAddress
+	}
+	return fee.WithMargin(estimated, w.WalletBusinessConfig.GasLimitMargin), contractAddress
+}
+
+/*估算主币或 ERC-20 转账 gas*/
+func (w *WalletBusinessService) estimateTransferGas(ctx context.Context, from, to, amount, contractAddress string) (uint64, error) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount: %s", amount)
+	}
+	toAddress := common.HexToAddress(to)
 	if contractAddress == "0x00" {
-		return EthGasLimit, "0x00"
+		return w.gasEstimator.EstimateGas(ctx, common.HexToAddress(from), &toAddress, value, nil)
 	}
-	return TokenGasLimit, contractAddress
+	contract := common.HexToAddress(contractAddress)
+	return w.gasEstimator.EstimateGas(ctx, common.HexToAddress(from), &contract, big.NewInt(0), erc20TransferData(toAddress, value))
+}
+
+/*ERC-20 transfer(address,uint256) 调用数据*/
+func erc20TransferData(to common.Address, amount *big.Int) []byte {
+	data := append([]byte{}, erc20TransferSelector...)
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+}
+
+/*解析项目方费率上限，为空为 0（不限制）*/
+func parseFeeCeiling(feeCeiling string) (*big.Int, error) {
+	if feeCeiling == "" {
+		return big.NewInt(0), nil
+	}
+	ceiling, ok := new(big.Int).SetString(feeCeiling, 10)
+	if !ok || ceiling.Sign() < 0 {
+		return nil, fmt.Errorf("invalid fee ceiling: %s", feeCeiling)
+	}
+	return ceiling, nil
 }
 
 /*封装存储充值*/
@@ -451,7 +851,7 @@ func (w *WalletBusinessService) StoreDeposits(ctx context.Context,
 		GasLimit:             gasLimit,
 		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
 		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
-		TokenType:            determineTokenType(depositsRequest.ContractAddress),
+		TokenType:            determineTokenType(depositsRequest),
 		TokenAddress:         common.HexToAddress(depositsRequest.ContractAddress),
 		TokenId:              depositsRequest.TokenId,
 		TokenMeta:            depositsRequest.TokenMeta,
@@ -461,9 +861,46 @@ func (w *WalletBusinessService) StoreDeposits(ctx context.Context,
 	return w.db.Deposits.StoreDeposits(depositsRequest.RequestId, []*database.Deposits{dbDeposit})
 }
 
-/*确定合约类型*/
-func determineTokenType(contractAddress string) constant.TokenType {
-	if contractAddress == "0x00" {
+/*提现目标地址筛查*/
+func (w *WalletBusinessService) screenWithdraw(request *exchange_wallet_go.UnSignTransactionRequest) (*screening.Result, error) {
+	counterparties, err := w.db.Transactions.QueryRecentCounterparties(request.RequestId, w.screener.CounterpartyLimit())
+	if err != nil {
+		log.Error("failed to query recent counterparties", "requestId", request.RequestId, "err", err)
+		return nil, err
+	}
+	return w.screener.Screen(common.HexToAddress(request.To), counterparties), nil
+}
+
+/*提现风险评分，未配置评分器时放行*/
+func (w *WalletBusinessService) scoreWithdraw(ctx context.Context, request *exchange_wallet_go.UnSignTransactionRequest, tokenType constant.TokenType) (*risk.Result, error) {
+	if w.scorer == nil {
+		return &risk.Result{Action: constant.RiskActionAllow}, nil
+	}
+	ctx, span := tracing.Start(ctx, "risk.score", attribute.String("business.id", request.RequestId))
+	result, err := w.scorer.Score(ctx, &risk.Request{
+		BusinessId:   request.RequestId,
+		TxType:       constant.TxTypeWithdraw,
+		FromAddress:  request.From,
+		ToAddress:    request.To,
+		TokenType:    tokenType,
+		TokenAddress: request.ContractAddress,
+		TokenId:      request.TokenId,
+		Amount:       request.Value,
+	})
+	tracing.End(span, err)
+	if err != nil {
+		tracing.Logger(ctx).Error("failed to score withdraw", "requestId", request.RequestId, "to", request.To, "err", err)
+		return nil, fmt.Errorf("score withdraw fail: %w", err)
+	}
+	return result, nil
+}
+
+/*确定合约类型：请求指定了代币类型则以请求为准*/
+func determineTokenType(request *exchange_wallet_go.UnSignTransactionRequest) constant.TokenType {
+	if request.TokenType != "" {
+		return constant.TokenType(request.TokenType)
+	}
+	if request.ContractAddress == "0x00" {
 		return constant.TokenTypeETH
 	}
 	// 这里可以添加更多的 token 类型判断逻辑
@@ -472,12 +909,20 @@ func determineTokenType(contractAddress string) constant.TokenType {
 
 /*存储提现封装*/
 func (w *WalletBusinessService) storeWithdraw(request *exchange_wallet_go.UnSignTransactionRequest,
-	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType constant.TransactionType) error {
+	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType constant.TransactionType,
+	status constant.TxStatus, riskResult *risk.Result) error {
+	withdraw := newWithdraw(request, transactionId, amountBig, gasLimit, feeInfo, transactionType, status, riskResult)
+	return w.db.Withdraws.StoreWithdraw(request.RequestId, withdraw)
+}
 
-	withdraw := &database.Withdraws{
+/*新建提现记录*/
+func newWithdraw(request *exchange_wallet_go.UnSignTransactionRequest,
+	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType constant.TransactionType,
+	status constant.TxStatus, riskResult *risk.Result) *database.Withdraws {
+	return &database.Withdraws{
 		GUID:                 transactionId,
 		Timestamp:            uint64(time.Now().Unix()),
-		Status:               constant.TxStatusCreateUnsigned,
+		Status:               status,
 		BlockHash:            common.Hash{},
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.Hash{},
@@ -488,19 +933,21 @@ func (w *WalletBusinessService) storeWithdraw(request *exchange_wallet_go.UnSign
 		GasLimit:             gasLimit,
 		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
 		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
-		TokenType:            determineTokenType(request.ContractAddress),
+		TokenType:            determineTokenType(request),
 		TokenAddress:         common.HexToAddress(request.ContractAddress),
 		TokenId:              request.TokenId,
 		TokenMeta:            request.TokenMeta,
 		TxSignHex:            "",
+		RiskReason:           riskResult.Reason,
+		RiskScore:            riskResult.Score,
+		RiskAction:           riskResult.Action,
 	}
-
-	return w.db.Withdraws.StoreWithdraw(request.RequestId, withdraw)
 }
 
-// 存储内部交易(冷热互转、归集)
+// 存储内部交易(冷热互转、归集)，有 gas 补充交易时一并存储并关联
 func (w *WalletBusinessService) storeInternal(request *exchange_wallet_go.UnSignTransactionRequest,
-	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType constant.TransactionType) error {
+	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType constant.TransactionType,
+	gasFunding *database.Internals) error {
 
 	internal := &database.Internals{
 		GUID:                 transactionId,
@@ -516,12 +963,21 @@ func (w *WalletBusinessService) storeInternal(request *exchange_wallet_go.UnSign
 		GasLimit:             gasLimit,
 		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
 		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
-		TokenType:            determineTokenType(request.ContractAddress),
+		TokenType:            determineTokenType(request),
 		TokenAddress:         common.HexToAddress(request.ContractAddress),
 		TokenId:              request.TokenId,
 		TokenMeta:            request.TokenMeta,
 		TxSignHex:            "",
 	}
+	if gasFunding == nil {
+		return w.db.Internals.StoreInternal(request.RequestId, internal)
+	}
 
-	return w.db.Internals.StoreInternal(request.RequestId, internal)
+	internal.GasFundingGuid = gasFunding.GUID.String()
+	return w.db.Transaction(func(tx *database.DB) error {
+		if err := tx.Internals.StoreInternal(request.RequestId, gasFunding); err != nil {
+			return err
+		}
+		return tx.Internals.StoreInternal(request.RequestId, internal)
+	})
 }
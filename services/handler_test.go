@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+/*代币归集 gas 补充：用户地址主币不足 gasLimit * maxFeePerGas 时从热钱包补足差额*/
+func TestPrepareGasFunding(t *testing.T) {
+	feeInfo := &FeeInfo{MaxPriorityFee: big.NewInt(10), MultipliedTip: big.NewInt(10), Legacy: true}
+	gasNeeded := maxGasCost(TokenGasLimit, feeInfo)
+	tests := []struct {
+		name    string
+		balance *big.Int
+		amount  *big.Int
+	}{
+		{name: "no native balance", balance: big.NewInt(0), amount: gasNeeded},
+		{name: "partial native balance", balance: big.NewInt(400_000), amount: new(big.Int).Sub(gasNeeded, big.NewInt(400_000))},
+		{name: "enough native balance", balance: gasNeeded},
+		{name: "more than enough", balance: new(big.Int).Add(gasNeeded, big.NewInt(1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestService(&fakeChainsUnion{balance: tt.balance.String(), nonce: "3"}, nil)
+			request := &exchange_wallet_go.UnSignTransactionRequest{
+				RequestId:       "biz",
+				ChainId:         "1",
+				From:            userOne.Hex(),
+				To:              hotOne.Hex(),
+				ContractAddress: usdt.Hex(),
+			}
+			gasFunding, unSignTx, err := w.prepareGasFunding(context.Background(), request, TokenGasLimit, feeInfo)
+			require.NoError(t, err)
+			if tt.amount == nil {
+				require.Nil(t, gasFunding)
+				require.Empty(t, unSignTx)
+				return
+			}
+			require.NotNil(t, gasFunding)
+			require.NotEmpty(t, unSignTx)
+			require.Equal(t, tt.amount, gasFunding.Amount)
+			require.Equal(t, constant.TxTypeGasFunding, gasFunding.TxType)
+			require.Equal(t, constant.TxStatusCreateUnsigned, gasFunding.Status)
+			require.Equal(t, hotOne, gasFunding.FromAddress)
+			require.Equal(t, userOne, gasFunding.ToAddress)
+			require.Equal(t, common.Address{}, gasFunding.TokenAddress)
+			require.Equal(t, EthGasLimit, gasFunding.GasLimit)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+/*
+处理筛查或风险评分挂起的交易（命令行 handle-hold 调用）：
+* 充值放行：入账（NFT 更新持有）并恢复或补记流水，状态改为 success、处置动作改为 allow，不再评分，后续走确认位、通知流程
+* 充值拒绝：状态改为 ignored，不入账、不通知
+* 提现放行：状态改为 create_unsign，项目方可按交易 id 签名
+* 提现拒绝：状态改为 rejected，不签名
+事务内先按 hold / hold_notified 状态条件更新，成功后才入账，并发或重复处理只有一次生效
+*/
+func HandleHold(db *database.DB, requestId string, txType constant.TransactionType, transactionId string, release bool) error {
+	var err error
+	switch txType {
+	case constant.TxTypeDeposit:
+		err = handleHoldDeposit(db, requestId, transactionId, release)
+	case constant.TxTypeWithdraw:
+		err = db.Transaction(func(tx *database.DB) error {
+			status, riskAction := constant.TxStatusRejected, constant.RiskActionReject
+			if release {
+				status, riskAction = constant.TxStatusCreateUnsigned, constant.RiskActionAllow
+			}
+			if err := tx.Withdraws.ReleaseHoldWithdraw(requestId, transactionId, status, riskAction); err != nil {
+				return err
+			}
+			return tx.CacheVersions.BumpCacheVersion(requestId)
+		})
+	default:
+		return fmt.Errorf("unsupported hold transaction type: %s", txType)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("handle hold transaction success", "requestId", requestId, "txType", txType, "transactionId", transactionId, "release", release)
+	return nil
+}
+
+func handleHoldDeposit(db *database.DB, requestId string, transactionId string, release bool) error {
+	deposit, err := db.Deposits.QueryDepositsById(requestId, transactionId)
+	if err != nil {
+		return fmt.Errorf("query deposit failed: %w", err)
+	}
+	if deposit == nil {
+		return database.ErrDepositNotHeld
+	}
+	if !release {
+		return db.Transaction(func(tx *database.DB) error {
+			if err := tx.Deposits.ReleaseHoldDeposit(requestId, transactionId, constant.TxStatusIgnored, constant.RiskActionReject); err != nil {
+				return err
+			}
+			if _, err := tx.Transactions.UpdateHoldDepositFlowStatus(requestId, deposit, constant.TxStatusIgnored); err != nil {
+				return err
+			}
+			return tx.CacheVersions.BumpCacheVersion(requestId)
+		})
+	}
+	return db.Transaction(func(tx *database.DB) error {
+		if err := tx.Deposits.ReleaseHoldDeposit(requestId, transactionId, constant.TxStatusSuccess, constant.RiskActionAllow); err != nil {
+			return err
+		}
+		if err := creditDeposit(tx, requestId, deposit); err != nil {
+			return err
+		}
+		/*评分挂起的充值冲正时流水已改为 hold，恢复即可；扫链时即挂起的没有流水，补记*/
+		flowHeld, err := tx.Transactions.UpdateHoldDepositFlowStatus(requestId, deposit, constant.TxStatusSuccess)
+		if err != nil {
+			return err
+		}
+		if !flowHeld {
+			if err := tx.Transactions.StoreTransactions(requestId, []*database.Transactions{newDepositFlow(deposit)}, 1); err != nil {
+				return err
+			}
+		}
+		return tx.CacheVersions.BumpCacheVersion(requestId)
+	})
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"exchange-wallet-service/database/constant"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"strings"
+)
+
+var NftGasLimit uint64 = 200000
+
+/*safeTransferFrom 的 ABI（ERC-721 三参数，ERC-1155 五参数）*/
+const nftTransferABI = `[
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}]}
+]`
+
+var (
+	erc721SafeTransferFrom  abi.Method
+	erc1155SafeTransferFrom abi.Method
+)
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(nftTransferABI))
+	if err != nil {
+		panic(fmt.Sprintf("parse nft transfer abi fail: %v", err))
+	}
+	/*同名重载方法 go-ethereum 会自动重命名为 safeTransferFrom0*/
+	erc721SafeTransferFrom = parsed.Methods["safeTransferFrom"]
+	erc1155SafeTransferFrom = parsed.Methods["safeTransferFrom0"]
+}
+
+/*
+NFT 转账交易。
+chains-union-rpc 只支持主币与 ERC-20 转账的构建，
+NFT 的 safeTransferFrom 交易在本地构建：未签名交易返回待签名哈希，签名后本地组装原始交易
+*/
+type nftTransferTx struct {
+	ChainId              string
+	Nonce                uint64
+	FromAddress          string
+	ToAddress            string
+	ContractAddress      string
+	TokenType            constant.TokenType
+	TokenId              string
+	Amount               string
+	GasLimit             uint64
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+	Legacy               bool
+}
+
+/*构建 safeTransferFrom 调用数据*/
+func buildNftTransferData(tokenType constant.TokenType, from, to common.Address, tokenId, amount *big.Int) ([]byte, error) {
+	switch tokenType {
+	case constant.TokenTypeERC721:
+		if amount.Cmp(big.NewInt(1)) != 0 {
+			return nil, fmt.Errorf("erc721 transfer amount must be 1, got %s", amount)
+		}
+		args, err := erc721SafeTransferFrom.Inputs.Pack(from, to, tokenId)
+		if err != nil {
+			return nil, err
+		}
+		return append(erc721SafeTransferFrom.ID, args...), nil
+	case constant.TokenTypeERC1155:
+		if amount.Sign() <= 0 {
+			return nil, fmt.Errorf("erc1155 transfer amount must be positive, got %s", amount)
+		}
+		args, err := erc1155SafeTransferFrom.Inputs.Pack(from, to, tokenId, amount, []byte{})
+		if err != nil {
+			return nil, err
+		}
+		return append(erc1155SafeTransferFrom.ID, args...), nil
+	default:
+		return nil, fmt.Errorf("unsupported nft token type: %s", tokenType)
+	}
+}
+
+/*组装交易*/
+func (t *nftTransferTx) build() (*types.Transaction, types.Signer, error) {
+	chainId, ok := new(big.Int).SetString(t.ChainId, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid chain id: %s", t.ChainId)
+	}
+	tokenId, ok := math.ParseBig256(t.TokenId)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid token id: %s", t.TokenId)
+	}
+	amount, ok := new(big.Int).SetString(t.Amount, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid amount: %s", t.Amount)
+	}
+	maxFeePerGas, ok := new(big.Int).SetString(t.MaxFeePerGas, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid max fee per gas: %s", t.MaxFeePerGas)
+	}
+	maxPriorityFeePerGas, ok := new(big.Int).SetString(t.MaxPriorityFeePerGas, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid max priority fee per gas: %s", t.MaxPriorityFeePerGas)
+	}
+	if !common.IsHexAddress(t.ContractAddress) {
+		return nil, nil, fmt.Errorf("invalid contract address: %s", t.ContractAddress)
+	}
+	data, err := buildNftTransferData(t.TokenType, common.HexToAddress(t.FromAddress), common.HexToAddress(t.ToAddress), tokenId, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	contract := common.HexToAddress(t.ContractAddress)
+	tx := newLocalTx(chainId, t.Nonce, &contract, big.NewInt(0), data, t.GasLimit, maxFeePerGas, maxPriorityFeePerGas, t.Legacy)
+	return tx, types.LatestSignerForChainID(chainId), nil
+}
+
+/*本地构建交易：默认 EIP-1559，legacy 定价时 gasPrice 取 maxFeePerGas*/
+func newLocalTx(chainId *big.Int, nonce uint64, to *common.Address, value *big.Int, data []byte,
+	gasLimit uint64, maxFeePerGas, maxPriorityFeePerGas *big.Int, legacy bool) *types.Transaction {
+	if legacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: maxFeePerGas,
+			Gas:      gasLimit,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     nonce,
+		GasTipCap: maxPriorityFeePerGas,
+		GasFeeCap: maxFeePerGas,
+		Gas:       gasLimit,
+		To:        to,
+		Value:     value,
+		Data:      data,
+	})
+}
+
+/*未签名交易：返回待签名的交易哈希*/
+func (t *nftTransferTx) UnSignTx() (string, error) {
+	tx, signer, err := t.build()
+	if err != nil {
+		return "", err
+	}
+	return signer.Hash(tx).Hex(), nil
+}
+
+/*已签名交易：签名为 65 字节 r||s||v，返回可广播的原始交易*/
+func (t *nftTransferTx) SignedTx(signature string) (string, error) {
+	tx, signer, err := t.build()
+	if err != nil {
+		return "", err
+	}
+	return applySignature(tx, signer, t.FromAddress, signature)
+}
+
+/*本地构建的交易组装签名，校验签名地址与 from 一致（NFT 转账、批量提现共用）*/
+func applySignature(tx *types.Transaction, signer types.Signer, fromAddress string, signature string) (string, error) {
+	sig := common.FromHex(signature)
+	if len(sig) != 65 {
+		return "", errors.New("signature must be 65 bytes")
+	}
+	/*兼容 v 为 27/28 的签名*/
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	signedTx, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return "", fmt.Errorf("apply signature fail: %w", err)
+	}
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return "", fmt.Errorf("recover signer fail: %w", err)
+	}
+	if sender != common.HexToAddress(fromAddress) {
+		return "", fmt.Errorf("signature signer %s mismatch from address %s", sender, fromAddress)
+	}
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(rawTx), nil
+}
+
+func isNftTokenType(tokenType constant.TokenType) bool {
+	return tokenType == constant.TokenTypeERC721 || tokenType == constant.TokenTypeERC1155
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/database/constant"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"math/big"
+	"time"
+)
+
+/*隔离充值列表（非白名单代币充值）*/
+func (w *WalletBusinessService) ListQuarantineDeposits(ctx context.Context, request *exchange_wallet_go.QuarantineDepositsRequest) (*exchange_wallet_go.QuarantineDepositsResponse, error) {
+	w = w.withContext(ctx)
+	response := &exchange_wallet_go.QuarantineDepositsResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" {
+		response.Msg = "request id cannot be empty"
+		return response, nil
+	}
+	reader := w.db.Replica()
+	cacheKey := w.apiCache.key(reader, "listQuarantineDeposits", request.RequestId, request)
+	if cached, ok := w.apiCache.get(listCache, "listQuarantineDeposits", cacheKey); ok {
+		return cached.(*exchange_wallet_go.QuarantineDepositsResponse), nil
+	}
+	depositList, err := reader.Deposits.QueryQuarantineDeposits(request.RequestId)
+	if err != nil {
+		log.Error("failed to query quarantine deposits", "requestId", request.RequestId, "err", err)
+		response.Msg = "query quarantine deposits fail"
+		return response, nil
+	}
+	for _, deposit := range depositList {
+		response.Deposits = append(response.Deposits, &exchange_wallet_go.QuarantineDeposit{
+			TransactionId: deposit.GUID.String(),
+			TxHash:        deposit.TxHash.String(),
+			BlockNumber:   deposit.BlockNumber.String(),
+			From:          deposit.FromAddress.String(),
+			To:            deposit.ToAddress.String(),
+			TokenType:     string(deposit.TokenType),
+			TokenAddress:  deposit.TokenAddress.String(),
+			TokenId:       deposit.TokenId,
+			Amount:        deposit.Amount.String(),
+			Timestamp:     deposit.Timestamp,
+		})
+	}
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query quarantine deposits success"
+	w.apiCache.add(listCache, cacheKey, response)
+	return response, nil
+}
+
+/*
+处理隔离充值：
+* 入账：按正常充值更新余额（NFT 更新持有）、记流水，状态改为 success，后续走确认位、通知流程
+* 忽略：状态改为 ignored，不入账、不通知
+事务内先按 quarantine 状态条件更新，成功后才入账，并发或重复处理只有一次生效
+*/
+func (w *WalletBusinessService) HandleQuarantineDeposit(ctx context.Context, request *exchange_wallet_go.HandleQuarantineDepositRequest) (*exchange_wallet_go.HandleQuarantineDepositResponse, error) {
+	w = w.withContext(ctx)
+	response := &exchange_wallet_go.HandleQuarantineDepositResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" {
+		response.Msg = "request id cannot be empty"
+		return response, nil
+	}
+	deposit, err := w.db.Deposits.QueryDepositsById(request.RequestId, request.TransactionId)
+	if err != nil {
+		log.Error("failed to query deposit", "transactionId", request.TransactionId, "err", err)
+		response.Msg = "query deposit fail"
+		return response, nil
+	}
+	if deposit == nil || deposit.Status != constant.TxStatusQuarantine {
+		response.Msg = "deposit is not in quarantine"
+		return response, nil
+	}
+
+	switch request.Action {
+	case exchange_wallet_go.QuarantineAction_ACCEPT:
+		err = w.db.Transaction(func(tx *database.DB) error {
+			if err := tx.Deposits.ReleaseQuarantineDeposit(request.RequestId, request.TransactionId, constant.TxStatusSuccess); err != nil {
+				return err
+			}
+			if err := creditDeposit(tx, request.RequestId, deposit); err != nil {
+				return err
+			}
+			if err := tx.Transactions.StoreTransactions(request.RequestId, []*database.Transactions{newDepositFlow(deposit)}, 1); err != nil {
+				return err
+			}
+			return tx.CacheVersions.BumpCacheVersion(request.RequestId)
+		})
+	case exchange_wallet_go.QuarantineAction_IGNORE:
+		err = w.db.Transaction(func(tx *database.DB) error {
+			if err := tx.Deposits.ReleaseQuarantineDeposit(request.RequestId, request.TransactionId, constant.TxStatusIgnored); err != nil {
+				return err
+			}
+			return tx.CacheVersions.BumpCacheVersion(request.RequestId)
+		})
+	default:
+		response.Msg = "invalid quarantine action"
+		return response, nil
+	}
+	if errors.Is(err, database.ErrDepositNotQuarantined) {
+		log.Warn("deposit already handled", "requestId", request.RequestId, "transactionId", request.TransactionId, "action", request.Action)
+		response.Msg = "deposit is not in quarantine"
+		return response, nil
+	}
+	if err != nil {
+		log.Error("failed to handle quarantine deposit", "transactionId", request.TransactionId, "action", request.Action, "err", err)
+		response.Msg = "handle quarantine deposit fail"
+		return response, nil
+	}
+	log.Info("handle quarantine deposit success", "requestId", request.RequestId, "transactionId", request.TransactionId, "action", request.Action)
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "handle quarantine deposit success"
+	return response, nil
+}
+
+/*人工入账充值：按正常充值更新余额（NFT 更新持有）*/
+func creditDeposit(tx *database.DB, requestId string, deposit *database.Deposits) error {
+	if isNftTokenType(deposit.TokenType) {
+		return tx.NftHoldings.UpdateNftHoldings(requestId, []*database.NftTransfer{{
+			FromAddress:  deposit.FromAddress,
+			ToAddress:    deposit.ToAddress,
+			TokenAddress: deposit.TokenAddress,
+			TokenId:      deposit.TokenId,
+			TokenType:    deposit.TokenType,
+			Amount:       deposit.Amount,
+			TxType:       constant.TxTypeDeposit,
+		}})
+	}
+	return tx.Balances.UpdateOrCreate(requestId, []*database.TokenBalance{{
+		FromAddress:  deposit.FromAddress,
+		ToAddress:    deposit.ToAddress,
+		TokenAddress: deposit.TokenAddress,
+		Balance:      deposit.Amount,
+		TxType:       constant.TxTypeDeposit,
+		TxHash:       deposit.TxHash,
+	}})
+}
+
+/*人工入账充值的交易流水*/
+func newDepositFlow(deposit *database.Deposits) *database.Transactions {
+	txFee := deposit.Fee
+	if txFee == nil {
+		txFee = big.NewInt(0)
+	}
+	return &database.Transactions{
+		GUID:         uuid.New(),
+		BlockHash:    deposit.BlockHash,
+		BlockNumber:  deposit.BlockNumber,
+		Hash:         deposit.TxHash,
+		FromAddress:  deposit.FromAddress,
+		ToAddress:    deposit.ToAddress,
+		TokenType:    deposit.TokenType,
+		TokenAddress: deposit.TokenAddress,
+		TokenId:      deposit.TokenId,
+		TokenMeta:    deposit.TokenMeta,
+		Fee:          txFee,
+		Amount:       deposit.Amount,
+		Status:       constant.TxStatusSuccess,
+		TxType:       constant.TxTypeDeposit,
+		Timestamp:    uint64(time.Now().Unix()),
+	}
+}
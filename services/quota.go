@@ -0,0 +1,44 @@
+package services
+
+import (
+	"exchange-wallet-service/ratelimit"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+/*
+每日导出地址配额：在库内按 当日用量 + 本次数量 <= 配额 条件占用，超出返回 RESOURCE_EXHAUSTED；
+配额在构建前占用，后续失败不退回
+*/
+func (w *WalletBusinessService) reserveAddressQuota(requestId string, count int) error {
+	limit := w.limiter.Quota(requestId).DailyAddresses
+	if limit <= 0 {
+		return nil
+	}
+	ok, err := w.db.BusinessQuotas.ConsumeDailyAddresses(requestId, ratelimit.StartOfDay(time.Now()), int64(count), limit)
+	if err != nil {
+		return fmt.Errorf("failed to reserve address quota: %w", err)
+	}
+	if !ok {
+		return status.Errorf(codes.ResourceExhausted, "daily address quota exceeded: requested %d, quota %d", count, limit)
+	}
+	return nil
+}
+
+/*每日提现配额：同上，批量提现按笔数占用*/
+func (w *WalletBusinessService) reserveWithdrawQuota(requestId string, count int) error {
+	limit := w.limiter.Quota(requestId).DailyWithdraws
+	if limit <= 0 {
+		return nil
+	}
+	ok, err := w.db.BusinessQuotas.ConsumeDailyWithdraws(requestId, ratelimit.StartOfDay(time.Now()), int64(count), limit)
+	if err != nil {
+		return fmt.Errorf("failed to reserve withdraw quota: %w", err)
+	}
+	if !ok {
+		return status.Errorf(codes.ResourceExhausted, "daily withdraw quota exceeded: requested %d, quota %d", count, limit)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"github.com/ethereum/go-ethereum/log"
+	"math/big"
+)
+
+/*
+储备证明查询：返回命令行 proof-of-reserves 已生成的报告（指定区块时取该区块及之前最近一次），
+数据集较大，以 JSON 字符串返回，与命令行导出格式一致
+*/
+func (w *WalletBusinessService) GetProofOfReserves(ctx context.Context, request *exchange_wallet_go.ProofOfReservesRequest) (*exchange_wallet_go.ProofOfReservesResponse, error) {
+	w = w.withContext(ctx)
+	response := &exchange_wallet_go.ProofOfReservesResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" {
+		response.Msg = "request id cannot be empty"
+		return response, nil
+	}
+	var blockNumber *big.Int
+	if request.BlockNumber != "" {
+		number, ok := new(big.Int).SetString(request.BlockNumber, 10)
+		if !ok || number.Sign() < 0 {
+			response.Msg = "invalid block number"
+			return response, nil
+		}
+		blockNumber = number
+	}
+
+	reader := w.db.Replica()
+	cacheKey := w.apiCache.key(reader, "getProofOfReserves", request.RequestId, request)
+	if cached, ok := w.apiCache.get(detailCache, "getProofOfReserves", cacheKey); ok {
+		return cached.(*exchange_wallet_go.ProofOfReservesResponse), nil
+	}
+	report, err := reader.ProofOfReserves.QueryLatestProofOfReserves(request.RequestId, blockNumber)
+	if err != nil {
+		log.Error("failed to query proof of reserves", "requestId", request.RequestId, "blockNumber", request.BlockNumber, "err", err)
+		response.Msg = "query proof of reserves fail"
+		return response, nil
+	}
+	if report == nil {
+		response.Msg = "proof of reserves not generated, run the proof-of-reserves command first"
+		return response, nil
+	}
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query proof of reserves success"
+	response.MerkleRoot = report.MerkleRoot
+	response.Data = report.Report
+	w.apiCache.add(detailCache, cacheKey, response)
+	return response, nil
+}
@@ -2,23 +2,34 @@ package services
 
 import (
 	"context"
+	"errors"
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
+	"exchange-wallet-service/fee"
+	"exchange-wallet-service/health"
+	"exchange-wallet-service/metrics"
 	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"exchange-wallet-service/ratelimit"
+	"exchange-wallet-service/risk"
 	"exchange-wallet-service/rpcclient"
 	"exchange-wallet-service/rpcclient/chainsunion"
+	"exchange-wallet-service/screening"
+	"exchange-wallet-service/tlsutil"
+	"exchange-wallet-service/tracing"
 	"fmt"
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"math/big"
 	"net"
+	"net/http"
 	"runtime/debug"
-	"strconv"
-	"strings"
 	"sync/atomic"
+	"time"
 )
 
 const MaxRecvMessageSize = 1024 * 1024 * 300
@@ -27,55 +38,178 @@ type WalletBusinessService struct {
 	WalletBusinessConfig *config.WalletBusinessConfig
 	chainUnionClient     *rpcclient.ChainsUnionRpcClient
 	db                   *database.DB
+	screener             *screening.Screener
+	scorer               risk.RiskScorer
+	feeStrategy          fee.Strategy
+	gasEstimator         rpcclient.GasEstimator
+	metricsServer        *metrics.Server
+	healthServer         *health.GRPCServer
+	tracer               *tracing.Provider
+	server               *http.Server
+	gatewayServer        *grpc.Server
+	limiter              *ratelimit.Limiter
+	apiCache             *apiCache
 	stopped              atomic.Bool
 }
 
 /*新建本地 rpc 服务*/
-func NewWalletBusinessService(config *config.WalletBusinessConfig, db *database.DB, rpcClient *rpcclient.ChainsUnionRpcClient) (*WalletBusinessService, error) {
+func NewWalletBusinessService(config *config.WalletBusinessConfig, db *database.DB, rpcClient *rpcclient.ChainsUnionRpcClient, screener *screening.Screener, scorer risk.RiskScorer,
+	feeStrategy fee.Strategy, gasEstimator rpcclient.GasEstimator) (*WalletBusinessService, error) {
 	log.Info("new WalletBusinessService success", "config", config, "db", db)
 	return &WalletBusinessService{
 		WalletBusinessConfig: config,
 		chainUnionClient:     rpcClient,
 		db:                   db,
+		screener:             screener,
+		scorer:               scorer,
+		feeStrategy:          feeStrategy,
+		gasEstimator:         gasEstimator,
+		apiCache:             newApiCache(config.ApiCacheEnable, config.ApiCache),
 	}, nil
 }
 
 /*cli.app的的生命周期管理管理，会自动启动 start */
 func (w *WalletBusinessService) Start(ctx context.Context) error {
+	tracer, err := tracing.NewProvider(ctx, w.WalletBusinessConfig.TracingEndpoint, w.WalletBusinessConfig.TracingSampleRatio, "exchange-wallet-rpc")
+	if err != nil {
+		log.Error("failed to start tracing", "err", err)
+		return err
+	}
+	w.tracer = tracer
+
+	/*健康检查：gRPC 健康协议与指标端口上的 HTTP 路由*/
+	checker := health.NewChecker()
+	checker.AddReadiness("database", w.db.Ping)
+	checker.AddReadiness("chainsunion", w.chainUnionClient.Ping)
+	w.healthServer = health.NewGRPCServer(checker, exchange_wallet_go.WalletBusinessServices_ServiceDesc.ServiceName)
+	w.healthServer.Start()
+
+	metricsServer, err := metrics.StartServer(w.WalletBusinessConfig.MetricsHostName, w.WalletBusinessConfig.MetricsPort, checker.Routes())
+	if err != nil {
+		log.Error("failed to start metrics server", "err", err)
+		return err
+	}
+	w.metricsServer = metricsServer
+
+	/*按项目方限流与每日配额，配置存于数据库*/
+	w.limiter = ratelimit.NewLimiter(w.db.RateLimits, w.db.BusinessQuotas)
+	if err := w.limiter.Start(w.WalletBusinessConfig.RateLimitRefreshInterval); err != nil {
+		log.Error("failed to load rate limits", "err", err)
+		return err
+	}
+
+	tlsConfig, err := tlsutil.ServerConfig(w.WalletBusinessConfig.TLS)
+	if err != nil {
+		log.Error("failed to load rpc server tls config", "err", err)
+		return err
+	}
+
+	gs := w.newGrpcServer(peerBusiness)
+	reflection.Register(gs)
+	w.healthServer.Register(gs)
+	exchange_wallet_go.RegisterWalletBusinessServicesServer(gs, w)
+
+	/*HTTP/JSON 网关与 gRPC 共用 RpcServer 端口，网关请求经进程内连接转发到网关专用的 gRPC 服务*/
+	w.gatewayServer = w.newGrpcServer(gatewayBusiness)
+	exchange_wallet_go.RegisterWalletBusinessServicesServer(w.gatewayServer, w)
+	gatewayListener := bufconn.Listen(gatewayBufferSize)
+	go func(w *WalletBusinessService) {
+		if err := w.gatewayServer.Serve(gatewayListener); err != nil {
+			log.Error("failed to serve gateway grpc server", "err", err)
+		}
+	}(w)
+	gateway, err := newGateway(ctx, gatewayListener)
+	if err != nil {
+		log.Error("failed to create gateway", "err", err)
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", w.WalletBusinessConfig.GrpcHostName, w.WalletBusinessConfig.GrpcPort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Error("failed to listen", "err", err)
+		return err
+	}
+	w.server = &http.Server{
+		Handler:           grpcOrGatewayHandler(gs, gateway),
+		Protocols:         serverProtocols(tlsConfig != nil),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 	go func(w *WalletBusinessService) {
-		addr := fmt.Sprintf("%s:%d", w.WalletBusinessConfig.GrpcHostName, w.WalletBusinessConfig.GrpcPort)
-		log.Info("starting grpc server", "host", addr, "port", w.WalletBusinessConfig.GrpcPort)
-		listener, err := net.Listen("tcp", addr)
-		if err != nil {
-			log.Error("failed to listen", "err", err)
+		log.Info("starting grpc and http gateway server", "host", addr, "tls", tlsConfig != nil, "mtls", w.WalletBusinessConfig.TLS.ClientCAFile != "")
+		var err error
+		if tlsConfig != nil {
+			err = w.server.ServeTLS(listener, "", "")
+		} else {
+			err = w.server.Serve(listener)
 		}
-		gs := grpc.NewServer(
-			grpc.MaxRecvMsgSize(MaxRecvMessageSize),
-			grpc.ChainUnaryInterceptor(
-				WrapPanicInterceptor,
-			),
-		)
-		reflection.Register(gs)
-		exchange_wallet_go.RegisterWalletBusinessServicesServer(gs, w)
-		log.Info("starting chainsunion grpc server", "host", addr)
-		if err := gs.Serve(listener); err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error("failed to serve", "err", err)
 		}
 	}(w)
 	return nil
 }
 
+/*gRPC 服务：统一的拦截器链，identify 为项目方鉴权取身份的方式*/
+func (w *WalletBusinessService) newGrpcServer(identify func(ctx context.Context) string) *grpc.Server {
+	return grpc.NewServer(
+		grpc.MaxRecvMsgSize(MaxRecvMessageSize),
+		tracing.ServerOption(),
+		grpc.ChainUnaryInterceptor(
+			WrapPanicInterceptor,
+			metrics.UnaryServerInterceptor,
+			w.businessAuthInterceptor(identify),
+			w.limiter.UnaryServerInterceptor(exchange_wallet_go.WalletBusinessServices_ServiceDesc.ServiceName),
+		),
+	)
+}
+
 func (w *WalletBusinessService) Stop(ctx context.Context) error {
 	w.stopped.Store(true)
-	return nil
+	if w.server != nil {
+		if err := w.server.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	if w.gatewayServer != nil {
+		w.gatewayServer.GracefulStop()
+	}
+	if err := w.limiter.Stop(); err != nil {
+		return err
+	}
+	if err := w.healthServer.Stop(); err != nil {
+		return err
+	}
+	if err := w.metricsServer.Stop(ctx); err != nil {
+		return err
+	}
+	return w.tracer.Shutdown(ctx)
 }
 
 func (w *WalletBusinessService) Stopped() bool {
 	return w.stopped.Load()
 }
 
-/*调用 chainunion 获取 fee*/
-func (w *WalletBusinessService) getFeeInfo(ctx context.Context, address string) (*FeeInfo, error) {
+/*绑定请求上下文的副本：数据库查询与 chains-union-rpc 调用归入请求链路*/
+func (w *WalletBusinessService) withContext(ctx context.Context) *WalletBusinessService {
+	return &WalletBusinessService{
+		WalletBusinessConfig: w.WalletBusinessConfig,
+		chainUnionClient:     w.chainUnionClient.WithContext(ctx),
+		db:                   w.db.WithContext(ctx),
+		screener:             w.screener,
+		scorer:               w.scorer,
+		feeStrategy:          w.feeStrategy,
+		gasEstimator:         w.gasEstimator,
+		limiter:              w.limiter,
+		apiCache:             w.apiCache,
+	}
+}
+
+/*调用 chainunion 获取链上费率建议，按请求档位定价并受项目方费率上限约束*/
+func (w *WalletBusinessService) getFeeInfo(ctx context.Context, requestId string, address string, feeRequest *fee.Request) (feeInfo *FeeInfo, err error) {
+	ctx, span := tracing.Start(ctx, "fee.quote", attribute.String("fee.level", string(feeRequest.Level)))
+	defer func() { tracing.End(span, err) }()
 	accountFeeReq := &chainsunion.FeeRequest{
 		Chain:   ChainName,
 		Network: Network,
@@ -86,85 +220,78 @@ func (w *WalletBusinessService) getFeeInfo(ctx context.Context, address string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get fee info: %w", err)
 	}
-	return ParseFastFee(feeResponse.FastFee)
+	quote, err := w.feeStrategy.Quote(&fee.Suggestion{
+		Slow:   feeResponse.SlowFee,
+		Normal: feeResponse.NormalFee,
+		Fast:   feeResponse.FastFee,
+	}, feeRequest)
+	if err != nil {
+		return nil, err
+	}
+	business, err := w.db.Business.QueryBusinessByUuid(requestId)
+	if err != nil {
+		return nil, fmt.Errorf("query business fail: %w", err)
+	}
+	if err := fee.ApplyCeiling(quote, business.FeeCeiling); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("fee.max_fee_per_gas", quote.MaxFeePerGas.String()))
+	return newFeeInfo(quote), nil
+}
+
+/*请求中的费率档位*/
+func feeRequest(request *exchange_wallet_go.UnSignTransactionRequest) (*fee.Request, error) {
+	level, err := fee.ParseLevel(request.FeeLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &fee.Request{
+		Level:                level,
+		MaxFeePerGas:         request.MaxFeePerGas,
+		MaxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+	}, nil
 }
 
 /*panic拦截器*/
 func WrapPanicInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-	log.Info("wrapped interceptor", "method", info.FullMethod, "req", req, "info", info)
+	logger := tracing.Logger(ctx)
+	logger.Info("wrapped interceptor", "method", info.FullMethod, "req", req, "info", info)
 	/*错误处理,防止出错全部程序崩溃*/
 	defer func() {
 		if e := recover(); e != nil {
-			log.Error("panic error", "msg", e)
-			log.Debug("panic", "stack", string(debug.Stack()))
+			logger.Error("panic error", "msg", e)
+			logger.Debug("panic", "stack", string(debug.Stack()))
 			err = status.Errorf(codes.Internal, "panic: %v", e)
 		}
 	}()
 	resp, err = handler(ctx, req)
-	log.Debug("wrapped interceptor", "resp", resp, "err", err)
+	logger.Debug("wrapped interceptor", "resp", resp, "err", err)
 	return resp, err
 }
 
-// FeeInfo 结构体用于存储解析后的费用信息
+// FeeInfo 结构体用于存储费率策略给出的费用信息
 type FeeInfo struct {
 	GasPrice       *big.Int // 基础 gas 价格
 	GasTipCap      *big.Int // 小费上限
 	Multiplier     int64    // 倍数
-	MultipliedTip  *big.Int // 小费 * 倍数
-	MaxPriorityFee *big.Int // 小费 * 倍数 * 2 (最大上限)
+	MultipliedTip  *big.Int // 矿工优先费（maxPriorityFeePerGas）
+	MaxPriorityFee *big.Int // 每单位 gas 最高价格（maxFeePerGas）
+	Legacy         bool     // legacy 定价，gas 价格即 MaxPriorityFee
 }
 
-// ParseFastFee 解析 FastFee 字符串并计算相关费用
-func ParseFastFee(fastFee string) (*FeeInfo, error) {
-	// 1. 按 "|" 分割字符串
-	parts := strings.Split(fastFee, "|")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("invalid fast fee format: %s", fastFee)
-	}
-
-	// 2. 解析 GasPrice (baseFee)
-	gasPrice := new(big.Int)
-	if _, ok := gasPrice.SetString(parts[0], 10); !ok {
-		return nil, fmt.Errorf("invalid gas price: %s", parts[0])
-	}
-
-	// 3. 解析 GasTipCap
-	gasTipCap := new(big.Int)
-	if _, ok := gasTipCap.SetString(parts[1], 10); !ok {
-		return nil, fmt.Errorf("invalid gas tip cap: %s", parts[1])
-	}
-
-	// 4. 解析倍数（去掉 "*" 前缀）
-	multiplierStr := strings.TrimPrefix(parts[2], "*")
-	multiplier, err := strconv.ParseInt(multiplierStr, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid multiplier: %s", parts[2])
-	}
-
-	// 5. 计算 MultipliedTip (小费 * 倍数)
-	multipliedTip := new(big.Int).Mul(
-		gasTipCap,
-		big.NewInt(multiplier),
-	)
-	// 设置最小小费阈值 (1 Gwei)
-	//minTipCap := big.NewInt(int64(Min1Gwei))
-	//if multipliedTip.Cmp(minTipCap) < 0 {
-	//	multipliedTip = minTipCap
-	//}
-
-	// 6. 计算 MaxPriorityFee (baseFee + 小费*倍数*2)
-	maxPriorityFee := new(big.Int).Mul(
-		multipliedTip,
-		big.NewInt(2),
-	)
-	// 加上 baseFee
-	maxPriorityFee.Add(maxPriorityFee, gasPrice)
+/*最高花费 gasLimit * maxFeePerGas（legacy 定价即 gasLimit * gasPrice）*/
+func maxGasCost(gasLimit uint64, feeInfo *FeeInfo) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), feeInfo.MaxPriorityFee)
+}
 
+/*费率策略结果转为费用信息*/
+func newFeeInfo(quote *fee.Quote) *FeeInfo {
 	return &FeeInfo{
-		GasPrice:       gasPrice,
-		GasTipCap:      gasTipCap,
-		Multiplier:     multiplier,
-		MultipliedTip:  multipliedTip,
-		MaxPriorityFee: maxPriorityFee,
-	}, nil
+		GasPrice:       quote.BaseFee,
+		GasTipCap:      quote.TipCap,
+		Multiplier:     quote.Multiplier,
+		MultipliedTip:  quote.MaxPriorityFeePerGas,
+		MaxPriorityFee: quote.MaxFeePerGas,
+		Legacy:         quote.Legacy,
+	}
 }
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"exchange-wallet-service/fee"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+/*费率策略结果转为费用信息：legacy 定价时 gasPrice 落在 MaxPriorityFee（maxFeePerGas）上*/
+func TestNewFeeInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		quote          *fee.Quote
+		maxPriorityFee int64
+		multipliedTip  int64
+		legacy         bool
+	}{
+		{
+			name:           "legacy",
+			quote:          &fee.Quote{BaseFee: big.NewInt(10), TipCap: big.NewInt(2), Multiplier: 3, MaxPriorityFeePerGas: big.NewInt(16), MaxFeePerGas: big.NewInt(16), Legacy: true},
+			maxPriorityFee: 16,
+			multipliedTip:  16,
+			legacy:         true,
+		},
+		{
+			name:           "dynamic",
+			quote:          &fee.Quote{BaseFee: big.NewInt(10), TipCap: big.NewInt(2), Multiplier: 3, MaxPriorityFeePerGas: big.NewInt(6), MaxFeePerGas: big.NewInt(22)},
+			maxPriorityFee: 22,
+			multipliedTip:  6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feeInfo := newFeeInfo(tt.quote)
+			require.Equal(t, tt.quote.BaseFee, feeInfo.GasPrice)
+			require.Equal(t, tt.quote.TipCap, feeInfo.GasTipCap)
+			require.Equal(t, tt.quote.Multiplier, feeInfo.Multiplier)
+			require.Equal(t, big.NewInt(tt.maxPriorityFee), feeInfo.MaxPriorityFee)
+			require.Equal(t, big.NewInt(tt.multipliedTip), feeInfo.MultipliedTip)
+			require.Equal(t, tt.legacy, feeInfo.Legacy)
+		})
+	}
+}
+
+/*legacy 定价本地构建 type-0 交易，gasPrice 取 MaxPriorityFee，不经 chains-union-rpc*/
+func TestBuildUnSignTxLegacy(t *testing.T) {
+	quote, err := fee.NewStrategy(true).Quote(&fee.Suggestion{Fast: "10|2|*3"}, &fee.Request{Level: fee.LevelFast})
+	require.NoError(t, err)
+	feeInfo := newFeeInfo(quote)
+	require.True(t, feeInfo.Legacy)
+
+	w := newTestService(&fakeChainsUnion{}, nil)
+	unSignTx, err := w.buildUnSignTx(context.Background(), "1", 7, userOne.Hex(), payee.Hex(), "1000", "0x00", EthGasLimit, feeInfo)
+	require.NoError(t, err)
+
+	expected := types.NewTx(&types.LegacyTx{
+		Nonce:    7,
+		GasPrice: feeInfo.MaxPriorityFee,
+		Gas:      EthGasLimit,
+		To:       &payee,
+		Value:    big.NewInt(1000),
+	})
+	require.Equal(t, types.LatestSignerForChainID(big.NewInt(1)).Hash(expected).Hex(), unSignTx)
+}
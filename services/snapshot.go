@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"exchange-wallet-service/database"
+	exchange_wallet_go "exchange-wallet-service/protobuf/exchange-wallet-go"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"math/big"
+)
+
+/*
+时间点余额查询：
+按区块号或时间戳（秒）返回每个地址、代币不晚于该时间点的最近一次快照，
+快照粒度由快照任务的区块间隔、每日配置决定
+*/
+func (w *WalletBusinessService) GetBalanceAt(ctx context.Context, request *exchange_wallet_go.GetBalanceAtRequest) (*exchange_wallet_go.GetBalanceAtResponse, error) {
+	w = w.withContext(ctx)
+	response := &exchange_wallet_go.GetBalanceAtResponse{
+		Code: exchange_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" {
+		response.Msg = "request id cannot be empty"
+		return response, nil
+	}
+
+	query := &database.BalanceAtQuery{Timestamp: request.Timestamp}
+	if request.BlockNumber != "" {
+		blockNumber, ok := new(big.Int).SetString(request.BlockNumber, 10)
+		if !ok || blockNumber.Sign() < 0 {
+			response.Msg = "invalid block number"
+			return response, nil
+		}
+		query.BlockNumber = blockNumber
+	}
+	if query.BlockNumber == nil && query.Timestamp == 0 {
+		response.Msg = "block number or timestamp is required"
+		return response, nil
+	}
+	if request.Address != "" {
+		if !common.IsHexAddress(request.Address) {
+			response.Msg = "invalid address"
+			return response, nil
+		}
+		address := common.HexToAddress(request.Address)
+		query.Address = &address
+	}
+	if request.TokenAddress != "" {
+		if !common.IsHexAddress(request.TokenAddress) {
+			response.Msg = "invalid token address"
+			return response, nil
+		}
+		tokenAddress := common.HexToAddress(request.TokenAddress)
+		query.TokenAddress = &tokenAddress
+	}
+
+	reader := w.db.Replica()
+	cacheKey := w.apiCache.key(reader, "getBalanceAt", request.RequestId, request)
+	if cached, ok := w.apiCache.get(detailCache, "getBalanceAt", cacheKey); ok {
+		return cached.(*exchange_wallet_go.GetBalanceAtResponse), nil
+	}
+	snapshots, err := reader.Snapshots.QueryBalanceAt(request.RequestId, query)
+	if err != nil {
+		log.Error("failed to query balance at", "requestId", request.RequestId, "blockNumber", request.BlockNumber, "timestamp", request.Timestamp, "err", err)
+		response.Msg = "query balance at fail"
+		return response, nil
+	}
+	for _, snapshot := range snapshots {
+		response.Balances = append(response.Balances, &exchange_wallet_go.BalanceSnapshot{
+			Address:      snapshot.Address.String(),
+			AddressType:  snapshot.AddressType.String(),
+			TokenAddress: snapshot.TokenAddress.String(),
+			Balance:      snapshot.Balance.String(),
+			LockBalance:  snapshot.LockBalance.String(),
+			BlockNumber:  snapshot.BlockNumber.String(),
+			SnapshotTime: snapshot.SnapshotTime,
+		})
+	}
+	response.Code = exchange_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query balance at success"
+	w.apiCache.add(detailCache, cacheKey, response)
+	return response, nil
+}
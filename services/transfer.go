@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+/*
+主币、ERC-20 legacy 转账交易。
+chains-union-rpc 只构建 EIP-1559 交易，legacy 定价时与 NFT 转账一样在本地构建 type-0 交易：
+未签名交易返回待签名哈希，签名后本地组装原始交易
+*/
+type legacyTransferTx struct {
+	ChainId         string
+	Nonce           uint64
+	FromAddress     string
+	ToAddress       string
+	ContractAddress string /*主币为 0x00 或零地址*/
+	Amount          string
+	GasLimit        uint64
+	GasPrice        string
+}
+
+/*组装交易：主币直接转账，ERC-20 调用合约 transfer，value 为 0*/
+func (t *legacyTransferTx) build() (*types.Transaction, types.Signer, error) {
+	chainId, ok := new(big.Int).SetString(t.ChainId, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid chain id: %s", t.ChainId)
+	}
+	amount, ok := new(big.Int).SetString(t.Amount, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid amount: %s", t.Amount)
+	}
+	gasPrice, ok := new(big.Int).SetString(t.GasPrice, 10)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid gas price: %s", t.GasPrice)
+	}
+	if !common.IsHexAddress(t.ToAddress) {
+		return nil, nil, fmt.Errorf("invalid to address: %s", t.ToAddress)
+	}
+	toAddress := common.HexToAddress(t.ToAddress)
+	contract := common.HexToAddress(t.ContractAddress)
+	var tx *types.Transaction
+	if contract == (common.Address{}) {
+		tx = newLocalTx(chainId, t.Nonce, &toAddress, amount, nil, t.GasLimit, gasPrice, gasPrice, true)
+	} else {
+		tx = newLocalTx(chainId, t.Nonce, &contract, big.NewInt(0), erc20TransferData(toAddress, amount), t.GasLimit, gasPrice, gasPrice, true)
+	}
+	return tx, types.LatestSignerForChainID(chainId), nil
+}
+
+/*未签名交易：返回待签名的交易哈希*/
+func (t *legacyTransferTx) UnSignTx() (string, error) {
+	tx, signer, err := t.build()
+	if err != nil {
+		return "", err
+	}
+	return signer.Hash(tx).Hex(), nil
+}
+
+/*已签名交易：返回可广播的原始交易*/
+func (t *legacyTransferTx) SignedTx(signature string) (string, error) {
+	tx, signer, err := t.build()
+	if err != nil {
+		return "", err
+	}
+	return applySignature(tx, signer, t.FromAddress, signature)
+}
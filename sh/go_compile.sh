@@ -16,14 +16,19 @@ function exit_if() {
 echo "🔧 Checking for required protoc plugins..."
 
 # 检查插件是否在 PATH 中
-if ! command -v protoc-gen-go >/dev/null 2>&1 || ! command -v protoc-gen-go-grpc >/dev/null 2>&1; then
+if ! command -v protoc-gen-go >/dev/null 2>&1 || ! command -v protoc-gen-go-grpc >/dev/null 2>&1 \
+    || ! command -v protoc-gen-grpc-gateway >/dev/null 2>&1 || ! command -v protoc-gen-openapiv2 >/dev/null 2>&1; then
     echo '❌ Missing protoc plugins for Go:' >&2
     echo '  - protoc-gen-go' >&2
     echo '  - protoc-gen-go-grpc' >&2
+    echo '  - protoc-gen-grpc-gateway' >&2
+    echo '  - protoc-gen-openapiv2' >&2
     echo '' >&2
     echo '👉 Install them with:' >&2
     echo '  go install google.golang.org/protobuf/cmd/protoc-gen-go@latest' >&2
     echo '  go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest' >&2
+    echo '  go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-grpc-gateway@v2.22.0' >&2
+    echo '  go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2@v2.22.0' >&2
     echo '' >&2
     echo '🔁 And make sure $GOBIN or $(go env GOBIN) is in your PATH' >&2
     exit 1
@@ -40,4 +45,13 @@ protoc \
 
 exit_if $? "❌ protoc compilation failed"
 
+# HTTP/JSON 网关与 OpenAPI 文档，路由见 protobuf/exchange-wallet-gateway.yaml
+protoc \
+    -I ./ \
+    --grpc-gateway_out=grpc_api_configuration=protobuf/exchange-wallet-gateway.yaml:./ \
+    --openapiv2_out=grpc_api_configuration=protobuf/exchange-wallet-gateway.yaml,json_names_for_fields=false,allow_merge=true,merge_file_name=protobuf/exchange-wallet-go/exchange-wallet:./ \
+    ./protobuf/exchange-wallet.proto
+
+exit_if $? "❌ protoc compilation failed"
+
 echo "✅ Done generating Go code from proto files"
\ No newline at end of file
@@ -0,0 +1,128 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+/*证书文件变更检查间隔：握手时按间隔检查修改时间，不额外起协程*/
+const ReloadCheckInterval = 10 * time.Second
+
+/*
+证书与 CA 文件热加载：文件修改时间变化时重新加载，
+加载失败保留旧证书继续服务，只记录错误
+*/
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	/*检查间隔，测试中可置 0*/
+	interval time.Duration
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	certMod   time.Time
+	keyMod    time.Time
+	caMod     time.Time
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+}
+
+/*新建热加载器并立即加载一次；certFile/keyFile 与 caFile 均可为空*/
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("tls cert file and key file must be set together")
+	}
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		interval: ReloadCheckInterval,
+	}
+	if err := r.reload(time.Now()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+/*当前证书，未配置证书时为 nil*/
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maybeReload()
+	return r.cert
+}
+
+/*当前 CA 证书池，未配置 CA 时为 nil*/
+func (r *Reloader) CertPool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maybeReload()
+	return r.pool
+}
+
+func (r *Reloader) maybeReload() {
+	now := time.Now()
+	if now.Sub(r.checkedAt) < r.interval {
+		return
+	}
+	if err := r.reload(now); err != nil {
+		log.Error("failed to reload tls files, keep previous ones", "cert", r.certFile, "ca", r.caFile, "err", err)
+	}
+}
+
+func (r *Reloader) reload(now time.Time) error {
+	r.checkedAt = now
+	if r.certFile != "" {
+		certMod, err := modTime(r.certFile)
+		if err != nil {
+			return err
+		}
+		keyMod, err := modTime(r.keyFile)
+		if err != nil {
+			return err
+		}
+		if r.cert == nil || !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod) {
+			cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+			if err != nil {
+				return fmt.Errorf("failed to load tls key pair: %w", err)
+			}
+			r.cert, r.certMod, r.keyMod = &cert, certMod, keyMod
+			log.Info("loaded tls certificate", "cert", r.certFile)
+		}
+	}
+	if r.caFile != "" {
+		caMod, err := modTime(r.caFile)
+		if err != nil {
+			return err
+		}
+		if r.pool == nil || !caMod.Equal(r.caMod) {
+			pem, err := os.ReadFile(r.caFile)
+			if err != nil {
+				return fmt.Errorf("failed to read tls ca file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("no certificate found in tls ca file: %s", r.caFile)
+			}
+			r.pool, r.caMod = pool, caMod
+			log.Info("loaded tls ca", "ca", r.caFile)
+		}
+	}
+	return nil
+}
+
+func modTime(file string) (time.Time, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to stat tls file: %w", err)
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,110 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"exchange-wallet-service/config"
+	"fmt"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+/*同一端口提供 gRPC（h2）与 HTTP/JSON 网关*/
+var serverNextProtos = []string{"h2", "http/1.1"}
+
+/*
+服务端 TLS 配置，未配置证书返回 nil（明文）；
+配置客户端 CA 时要求并校验客户端证书，证书与 CA 均按文件变更热加载
+*/
+func ServerConfig(cfg config.ServerTLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, errors.New("tls client ca file requires server cert file")
+		}
+		return nil, nil
+	}
+	reloader, err := NewReloader(cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return reloader.Certificate(), nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     serverNextProtos,
+		GetCertificate: getCertificate,
+	}
+	if cfg.ClientCAFile != "" {
+		/*每次握手取最新的客户端 CA*/
+		tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				NextProtos:     serverNextProtos,
+				GetCertificate: getCertificate,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				ClientCAs:      reloader.CertPool(),
+			}, nil
+		}
+	}
+	return tlsConfig, nil
+}
+
+/*
+gRPC 客户端传输凭证，未开启 TLS 为明文；
+配置 CA 时用该 CA 校验服务端证书（否则用系统根证书），配置证书时作为客户端证书出示
+*/
+func ClientCredentials(cfg config.ClientTLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enable {
+		return insecure.NewCredentials(), nil
+	}
+	reloader, err := NewReloader(cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CertFile != "" {
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.Certificate(), nil
+		}
+	}
+	if cfg.CAFile != "" {
+		/*CA 需热加载，跳过内置校验，握手时用当前 CA 校验服务端证书链与主机名*/
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyServer(state, reloader.CertPool())
+		}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func verifyServer(state tls.ConnectionState, roots *x509.CertPool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no server certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       state.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("failed to verify server certificate: %w", err)
+	}
+	return nil
+}
+
+/*客户端证书对应的项目方：已校验证书的 Subject CommonName 即项目方 request_id，未出示或未校验返回空*/
+func BusinessId(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
@@ -0,0 +1,195 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-wallet-service/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+/*签发证书，写入 dir/name.crt 与 dir/name.key*/
+func (ca *testCA) issue(t *testing.T, dir, name, commonName string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
+
+func (ca *testCA) write(t *testing.T, dir, name string) string {
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600))
+	return file
+}
+
+/*修改时间前移，保证文件变更可被检测到*/
+func touch(t *testing.T, files ...string) {
+	future := time.Now().Add(time.Minute)
+	for _, file := range files {
+		require.NoError(t, os.Chtimes(file, future, future))
+	}
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, dir, "server", "first", x509.ExtKeyUsageServerAuth)
+
+	reloader, err := NewReloader(certFile, keyFile, "")
+	require.NoError(t, err)
+	reloader.interval = 0
+	first := reloader.Certificate()
+	leaf, err := x509.ParseCertificate(first.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "first", leaf.Subject.CommonName)
+	assert.Nil(t, reloader.CertPool())
+
+	/*文件未变化不重新加载*/
+	assert.Same(t, first, reloader.Certificate())
+
+	ca.issue(t, dir, "server", "second", x509.ExtKeyUsageServerAuth)
+	touch(t, certFile, keyFile)
+	leaf, err = x509.ParseCertificate(reloader.Certificate().Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second", leaf.Subject.CommonName)
+
+	/*加载失败保留旧证书*/
+	second := reloader.Certificate()
+	require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0o600))
+	touch(t, certFile)
+	assert.Same(t, second, reloader.Certificate())
+
+	_, err = NewReloader(certFile, "", "")
+	assert.Error(t, err)
+}
+
+func TestServerConfig(t *testing.T) {
+	tlsConfig, err := ServerConfig(config.ServerTLSConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	_, err = ServerConfig(config.ServerTLSConfig{ClientCAFile: "ca.crt"})
+	assert.Error(t, err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir, "ca.crt")
+	serverCert, serverKey := ca.issue(t, dir, "server", "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, dir, "client", "business-1", x509.ExtKeyUsageClientAuth)
+
+	serverConfig, err := ServerConfig(config.ServerTLSConfig{CertFile: serverCert, KeyFile: serverKey, ClientCAFile: caFile})
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	businesses := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if err := tlsConn.Handshake(); err != nil {
+				businesses <- "handshake failed"
+			} else {
+				state := tlsConn.ConnectionState()
+				businesses <- BusinessId(&state)
+			}
+			conn.Close()
+		}
+	}()
+
+	handshake := func(cfg config.ClientTLSConfig) error {
+		creds, err := ClientCredentials(cfg)
+		require.NoError(t, err)
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tlsConn, _, err := creds.ClientHandshake(ctx, "localhost", conn)
+		if err != nil {
+			return err
+		}
+		/*TLS 1.3 客户端证书在首次读取时才被服务端校验*/
+		_, err = tlsConn.Read(make([]byte, 1))
+		return err
+	}
+
+	_ = handshake(config.ClientTLSConfig{Enable: true, CAFile: caFile, CertFile: clientCert, KeyFile: clientKey})
+	assert.Equal(t, "business-1", <-businesses)
+
+	/*未出示客户端证书被拒绝*/
+	_ = handshake(config.ClientTLSConfig{Enable: true, CAFile: caFile})
+	assert.Equal(t, "handshake failed", <-businesses)
+
+	/*服务端证书不受信任*/
+	otherCA := newTestCA(t)
+	otherCAFile := otherCA.write(t, dir, "other-ca.crt")
+	err = handshake(config.ClientTLSConfig{Enable: true, CAFile: otherCAFile, CertFile: clientCert, KeyFile: clientKey})
+	assert.Error(t, err)
+}
+
+func TestBusinessId(t *testing.T) {
+	assert.Empty(t, BusinessId(nil))
+	assert.Empty(t, BusinessId(&tls.ConnectionState{}))
+	state := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "business-1"}}}}}
+	assert.Equal(t, "business-1", BusinessId(state))
+}
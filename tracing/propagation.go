@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+)
+
+/*将当前链路写入 HTTP 头（traceparent），供通知回调的接收方关联*/
+func InjectHeader(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+/*gRPC 服务端：为每个请求开启 span，并提取上游链路*/
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+/*gRPC 客户端（chains-union-rpc）：为每次调用开启 span，并向下游透传链路*/
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
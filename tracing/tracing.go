@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "exchange-wallet-service"
+
+func init() {
+	/*未开启导出时也按 W3C traceparent 透传上游链路*/
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+/*链路追踪导出，经 OTLP gRPC 发往本地 collector*/
+type Provider struct {
+	provider *sdktrace.TracerProvider
+}
+
+/*
+新建并设置全局 TracerProvider，endpoint 为空时不导出返回 nil；
+sampleRatio 为根 span 采样比例，上游已采样的请求始终采样
+*/
+func NewProvider(ctx context.Context, endpoint string, sampleRatio float64, serviceName string) (*Provider, error) {
+	if endpoint == "" {
+		log.Info("tracing disabled")
+		return nil, nil
+	}
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Info("tracing enabled", "endpoint", endpoint, "sampleRatio", sampleRatio, "service", serviceName)
+	return &Provider{provider: provider}, nil
+}
+
+/*导出剩余 span 并关闭，未开启时直接返回*/
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	return p.provider.Shutdown(ctx)
+}
+
+/*开启子 span，需由调用方 End*/
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+/*结束 span，出错时记录错误*/
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+/*带 traceId、spanId 的日志，ctx 中无有效 span 时为根日志*/
+func Logger(ctx context.Context) log.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return log.Root()
+	}
+	return log.Root().With("traceId", spanContext.TraceID().String(), "spanId", spanContext.SpanID().String())
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestNewProviderDisabled(t *testing.T) {
+	provider, err := NewProvider(context.Background(), "", 1, "test")
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+	assert.NoError(t, provider.Shutdown(context.Background()))
+}
+
+func TestNewProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	/*collector 不可达时也能创建，导出失败只记录日志*/
+	provider, err := NewProvider(context.Background(), "127.0.0.1:4317", 0.5, "test")
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = provider.Shutdown(ctx)
+}
+
+func TestStartAndEnd(t *testing.T) {
+	recorder := newRecorder(t)
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, errors.New("boom"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestInjectHeader(t *testing.T) {
+	newRecorder(t)
+	ctx, span := Start(context.Background(), "notify")
+	defer span.End()
+
+	header := http.Header{}
+	InjectHeader(ctx, header)
+	assert.Contains(t, header.Get("traceparent"), span.SpanContext().TraceID().String())
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
+	"exchange-wallet-service/health"
+	"exchange-wallet-service/metrics"
+	"exchange-wallet-service/risk"
 	"exchange-wallet-service/rpcclient"
 	"exchange-wallet-service/rpcclient/chainsunion"
+	"exchange-wallet-service/screening"
+	"exchange-wallet-service/tlsutil"
+	"exchange-wallet-service/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"sync/atomic"
 )
 
@@ -26,18 +31,33 @@ type WorkerEntry struct {
 
 	Notifier *Notifier
 
+	Reconciler  *Reconciler
+	Snapshotter *Snapshotter
+
+	metricsConfig config.ServerConfig
+	metricsServer *metrics.Server
+	/*健康检查，与指标同端口*/
+	healthChecker *health.Checker
+	tracingConfig config.TracingConfig
+	tracer        *tracing.Provider
+
 	shutdown context.CancelCauseFunc
 	stopped  atomic.Bool
 }
 
 /*新建所有定时任务*/
 func NewAllWorker(ctx context.Context, cfg *config.Config, shutdown context.CancelCauseFunc) (*WorkerEntry, error) {
-	db, err := database.NewDB(ctx, cfg.MasterDB)
+	db, err := database.NewDBWithReplica(ctx, cfg)
 	if err != nil {
 		log.Error("failed to connect to master database", "err", err)
 		return nil, err
 	}
-	conn, err := grpc.NewClient(cfg.ChainsUnionRpc, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds, err := tlsutil.ClientCredentials(cfg.ChainsUnionTLS)
+	if err != nil {
+		log.Error("failed to load chains-union-rpc tls config", "err", err)
+		return nil, err
+	}
+	conn, err := grpc.NewClient(cfg.ChainsUnionRpc, grpc.WithTransportCredentials(creds), grpc.WithChainUnaryInterceptor(metrics.UnaryClientInterceptor), tracing.DialOption())
 	if err != nil {
 		log.Error("failed to connect to chains interance", "err", err)
 		return nil, err
@@ -49,14 +69,43 @@ func NewAllWorker(ctx context.Context, cfg *config.Config, shutdown context.Canc
 		return nil, err
 	}
 
+	/*开启事件日志解析或内部调用追踪时，直连链节点获取*/
+	var (
+		logSource   rpcclient.TransferLogSource
+		traceSource rpcclient.TraceSource
+	)
+	if cfg.ChainNode.TransferLogEnable || cfg.ChainNode.TraceEnable {
+		ethClient, err := rpcclient.NewEthClient(context.Background(), cfg.ChainNode.RpcUrl)
+		if err != nil {
+			log.Error("failed to connect to eth node", "err", err)
+			return nil, err
+		}
+		if cfg.ChainNode.TransferLogEnable {
+			logSource = ethClient
+		}
+		if cfg.ChainNode.TraceEnable {
+			traceSource = ethClient
+		}
+	}
+
 	/* 1. 新建区块同步器（生成者）*/
-	synchronizer, err := NewSynchronizer(cfg, db, rpcClient, shutdown)
+	synchronizer, err := NewSynchronizer(cfg, db, rpcClient, logSource, traceSource, shutdown)
 	if err != nil {
 		log.Error("failed to create synchronizer", "err", err)
 		return nil, err
 	}
 	/*2. 新建交易发现器（消费者）*/
-	finder, err := NewFinder(synchronizer, *cfg, shutdown)
+	screener, err := screening.NewScreener(cfg.Screening)
+	if err != nil {
+		log.Error("failed to create screener", "err", err)
+		return nil, err
+	}
+	scorer, err := risk.NewRiskScorer(cfg.Risk)
+	if err != nil {
+		log.Error("failed to create risk scorer", "err", err)
+		return nil, err
+	}
+	finder, err := NewFinder(synchronizer, *cfg, screener, scorer, shutdown)
 	if err != nil {
 		log.Error("failed to create finder", "err", err)
 		return nil, err
@@ -86,6 +135,20 @@ func NewAllWorker(ctx context.Context, cfg *config.Config, shutdown context.Canc
 		return nil, err
 	}
 
+	/* 7. 余额对账任务*/
+	reconciler, err := NewReconciler(cfg, db, rpcClient, shutdown)
+	if err != nil {
+		log.Error("failed to create reconciler", "err", err)
+		return nil, err
+	}
+
+	/* 8. 余额快照任务*/
+	snapshotter, err := NewSnapshotter(cfg, db, shutdown)
+	if err != nil {
+		log.Error("failed to create snapshotter", "err", err)
+		return nil, err
+	}
+
 	out := &WorkerEntry{
 		BaseSynchronizer: synchronizer,
 		Finder:           finder,
@@ -93,6 +156,11 @@ func NewAllWorker(ctx context.Context, cfg *config.Config, shutdown context.Canc
 		Internal:         internal,
 		Fallback:         fallback,
 		Notifier:         notifier,
+		Reconciler:       reconciler,
+		Snapshotter:      snapshotter,
+		metricsConfig:    cfg.MetricsServer,
+		healthChecker:    newHealthChecker(cfg, db, rpcClient, synchronizer),
+		tracingConfig:    cfg.Tracing,
 		shutdown:         shutdown,
 	}
 	return out, nil
@@ -100,8 +168,15 @@ func NewAllWorker(ctx context.Context, cfg *config.Config, shutdown context.Canc
 
 /*启动所有任务*/
 func (w *WorkerEntry) Start(ctx context.Context) error {
+	/*链路追踪导出先于各任务启动*/
+	tracer, err := tracing.NewProvider(ctx, w.tracingConfig.Endpoint, w.tracingConfig.SampleRatio, "exchange-wallet-work")
+	if err != nil {
+		log.Error("failed to start tracing", "err", err)
+		return err
+	}
+	w.tracer = tracer
 	/* 1. 启动同步器*/
-	err := w.BaseSynchronizer.Start()
+	err = w.BaseSynchronizer.Start()
 	if err != nil {
 		log.Error("failed to start base-synchronizer", "err", err)
 		return err
@@ -138,6 +213,26 @@ func (w *WorkerEntry) Start(ctx context.Context) error {
 		log.Error("failed to start notifier", "err", err)
 		return err
 	}
+
+	/* 8. 启动余额对账任务*/
+	err = w.Reconciler.Start()
+	if err != nil {
+		log.Error("failed to start reconciler", "err", err)
+		return err
+	}
+
+	/* 9. 启动余额快照任务*/
+	err = w.Snapshotter.Start()
+	if err != nil {
+		log.Error("failed to start snapshotter", "err", err)
+		return err
+	}
+	/* 10. 启动指标与健康检查服务*/
+	w.metricsServer, err = metrics.StartServer(w.metricsConfig.Host, w.metricsConfig.Port, w.healthChecker.Routes())
+	if err != nil {
+		log.Error("failed to start metrics server", "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -178,6 +273,30 @@ func (w *WorkerEntry) Stop(ctx context.Context) error {
 		log.Error("failed to stop notifier", "err", err)
 		return err
 	}
+	/* 8. 停止余额对账任务*/
+	err = w.Reconciler.Stop()
+	if err != nil {
+		log.Error("failed to stop reconciler", "err", err)
+		return err
+	}
+	/* 9. 停止余额快照任务*/
+	err = w.Snapshotter.Stop()
+	if err != nil {
+		log.Error("failed to stop snapshotter", "err", err)
+		return err
+	}
+	/* 10. 停止指标服务*/
+	err = w.metricsServer.Stop(ctx)
+	if err != nil {
+		log.Error("failed to stop metrics server", "err", err)
+		return err
+	}
+	/*最后关闭链路追踪，导出剩余 span*/
+	err = w.tracer.Shutdown(ctx)
+	if err != nil {
+		log.Error("failed to stop tracing", "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"exchange-wallet-service/common/tasks"
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
+	"exchange-wallet-service/metrics"
 	"exchange-wallet-service/rpcclient"
 	"fmt"
 	"github.com/ethereum/go-ethereum/log"
@@ -79,6 +80,7 @@ func (fb *Fallback) Start() error {
 					/*处理完回滚，取消回滚状态*/
 					fb.BaseSynchronizer.isFallback = false
 					fb.BaseSynchronizer.fallbackBlockHeader = nil
+					fb.BaseSynchronizer.fallbackSince.Store(0)
 				}
 			case <-fb.resourceCtx.Done():
 				log.Info("stop fallback.........")
@@ -104,6 +106,7 @@ func (fb *Fallback) onFallback(fallbackBlockHeader *rpcclient.BlockHeader) error
 	}
 
 	var fallbackBalances []*database.TokenBalance
+	fallbackNftTransfers := make(map[string][]*database.NftTransfer)
 	for _, business := range businessList {
 		log.Info("handle business", "businessUid", business.BusinessUid)
 		/*范围内的交易记录*/
@@ -113,12 +116,26 @@ func (fb *Fallback) onFallback(fallbackBlockHeader *rpcclient.BlockHeader) error
 			return err
 		}
 		for _, transaction := range transactionList {
+			/*NFT 交易回滚持有表，不回滚同质化余额*/
+			if isNftTokenType(transaction.TokenType) {
+				fallbackNftTransfers[business.BusinessUid] = append(fallbackNftTransfers[business.BusinessUid], &database.NftTransfer{
+					FromAddress:  transaction.FromAddress,
+					ToAddress:    transaction.ToAddress,
+					TokenAddress: transaction.TokenAddress,
+					TokenId:      transaction.TokenId,
+					TokenType:    transaction.TokenType,
+					Amount:       transaction.Amount,
+					TxType:       transaction.TxType,
+				})
+				continue
+			}
 			fallbackBalances = append(fallbackBalances, &database.TokenBalance{
 				FromAddress:  transaction.FromAddress,
 				ToAddress:    transaction.ToAddress,
 				TokenAddress: transaction.TokenAddress,
 				Balance:      transaction.Amount,
 				TxType:       transaction.TxType,
+				TxHash:       transaction.Hash,
 			})
 		}
 	}
@@ -165,11 +182,26 @@ func (fb *Fallback) onFallback(fallbackBlockHeader *rpcclient.BlockHeader) error
 						log.Error("failed to handle fallback transactions", "err", err)
 						return err
 					}
+					/*手续费回滚，退回付款地址主币余额*/
+					if err := tx.Fees.HandleFallBackFees(business.BusinessUid, entryBlockHeader.Number, fallbackBlockHeader.Number); err != nil {
+						log.Error("failed to handle fallback fees", "err", err)
+						return err
+					}
 					/*余额回滚*/
 					if err := tx.Balances.UpdateFallBackBalance(business.BusinessUid, fallbackBalances); err != nil {
 						log.Error("failed to update fallback balance", "err", err)
 						return err
 					}
+					/*NFT 持有回滚*/
+					if err := tx.NftHoldings.HandleFallBackNftHoldings(business.BusinessUid, fallbackNftTransfers[business.BusinessUid]); err != nil {
+						log.Error("failed to update fallback nft holdings", "err", err)
+						return err
+					}
+					/*查询接口缓存失效*/
+					if err := tx.CacheVersions.BumpCacheVersion(business.BusinessUid); err != nil {
+						log.Error("failed to bump cache version", "err", err)
+						return err
+					}
 				}
 			}
 			return nil
@@ -181,6 +213,7 @@ func (fb *Fallback) onFallback(fallbackBlockHeader *rpcclient.BlockHeader) error
 	}); err != nil {
 		return err
 	}
+	metrics.RecordReorg(len(reorgBlockHeaders))
 
 	return nil
 }
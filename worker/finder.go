@@ -8,12 +8,18 @@ import (
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
 	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/metrics"
+	"exchange-wallet-service/risk"
 	"exchange-wallet-service/rpcclient"
 	"exchange-wallet-service/rpcclient/chainsunion"
+	"exchange-wallet-service/screening"
+	"exchange-wallet-service/tracing"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
 	"time"
 )
@@ -27,6 +33,11 @@ type Finder struct {
 	/*同步器*/
 	BaseSynchronizer *BaseSynchronizer
 
+	/*地址筛查*/
+	screener *screening.Screener
+	/*风险评分*/
+	scorer risk.RiskScorer
+
 	/*确认位*/
 	confirms uint8
 	/*最新区块*/
@@ -39,10 +50,12 @@ type Finder struct {
 }
 
 /*新建交易发现器*/
-func NewFinder(synchronizer *BaseSynchronizer, cfg config.Config, shutdown context.CancelCauseFunc) (*Finder, error) {
+func NewFinder(synchronizer *BaseSynchronizer, cfg config.Config, screener *screening.Screener, scorer risk.RiskScorer, shutdown context.CancelCauseFunc) (*Finder, error) {
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &Finder{
 		BaseSynchronizer: synchronizer,
+		screener:         screener,
+		scorer:           scorer,
 		confirms:         uint8(cfg.ChainNode.Confirmations),
 		resourceCtx:      resCtx,
 		resourceCancel:   resCancel,
@@ -68,8 +81,11 @@ func (f *Finder) Start() error {
 				/*发现交易，处理*/
 				log.Info("deposit business channel", "batch length", len(batch))
 
-				if err := f.handleBatch(batch); err != nil {
-					log.Info("failed to handle batch, stopping L2 Synchronizer:", "err", err)
+				ctx, span := tracing.Start(f.resourceCtx, "finder.batch", attribute.Int("batch.businesses", len(batch)))
+				err := f.handleBatch(ctx, batch)
+				tracing.End(span, err)
+				if err != nil {
+					tracing.Logger(ctx).Info("failed to handle batch, stopping L2 Synchronizer:", "err", err)
 					return fmt.Errorf("failed to handle batch, stopping L2 Synchronizer: %w", err)
 				}
 			case <-f.ticker.C:
@@ -81,8 +97,6 @@ func (f *Finder) Start() error {
 				}
 			}
 		}
-
-		return nil
 	})
 
 	return nil
@@ -104,6 +118,12 @@ func (f *Finder) handleConfirmations() error {
 				return err
 			}
 
+			/*过确认位前风险评分，评分失败本轮不更新确认位，下轮重试*/
+			if err := f.scoreConfirmedDeposits(tx, business.BusinessUid, latestBlock.Number.Uint64()); err != nil {
+				log.Error("failed to score deposits", "business", business.BusinessUid, "err", err)
+				return err
+			}
+
 			if err := tx.Deposits.UpdateDepositsConfirms(business.BusinessUid, latestBlock.Number.Uint64(), uint64(f.confirms)); err != nil {
 				log.Error("failed to update confirms", "business", business.BusinessUid, "err", err)
 				return err
@@ -120,6 +140,105 @@ func (f *Finder) handleConfirmations() error {
 	return nil
 }
 
+/*
+充值风险评分（过确认位、标记 wallet_done 之前）：
+放行则记录评分；挂起或拒绝则状态改为 hold 并冲正已入账的余额（NFT 持有），
+流水同步改为 hold 不再参与回滚，通知时带上风险原因，等待人工处理（命令行 handle-hold）
+*/
+func (f *Finder) scoreConfirmedDeposits(tx *database.DB, businessId string, blockNumber uint64) error {
+	if f.scorer == nil {
+		return nil
+	}
+	depositList, err := tx.Deposits.QueryConfirmedDeposits(businessId, blockNumber, uint64(f.confirms))
+	if err != nil {
+		return err
+	}
+	held := false
+	for _, deposit := range depositList {
+		/*人工放行的挂起充值不再评分*/
+		if deposit.RiskAction == constant.RiskActionAllow {
+			continue
+		}
+		result, err := f.scorer.Score(f.resourceCtx, &risk.Request{
+			BusinessId:   businessId,
+			TxType:       constant.TxTypeDeposit,
+			TxHash:       deposit.TxHash.String(),
+			FromAddress:  deposit.FromAddress.String(),
+			ToAddress:    deposit.ToAddress.String(),
+			TokenType:    deposit.TokenType,
+			TokenAddress: deposit.TokenAddress.String(),
+			TokenId:      deposit.TokenId,
+			Amount:       deposit.Amount.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("score deposit %s fail: %w", deposit.TxHash, err)
+		}
+		deposit.RiskScore = result.Score
+		deposit.RiskAction = result.Action
+		deposit.RiskReason = joinRiskReason(deposit.RiskReason, result.Reason)
+		if result.Action != constant.RiskActionAllow {
+			log.Warn("deposit hit risk scoring, hold it", "txHash", deposit.TxHash, "score", result.Score, "action", result.Action, "reason", result.Reason)
+			deposit.Status = constant.TxStatusHold
+			if err := reverseDeposit(tx, businessId, deposit); err != nil {
+				return err
+			}
+			held = true
+		}
+		if err := tx.Deposits.UpdateDepositRiskById(businessId, deposit); err != nil {
+			return err
+		}
+	}
+	/*冲正改变了余额，查询接口缓存失效*/
+	if held {
+		return tx.CacheVersions.BumpCacheVersion(businessId)
+	}
+	return nil
+}
+
+/*冲正已入账的充值：扣回余额（NFT 持有），流水改为 hold*/
+func reverseDeposit(tx *database.DB, businessId string, deposit *database.Deposits) error {
+	if isNftTokenType(deposit.TokenType) {
+		if err := tx.NftHoldings.HandleFallBackNftHoldings(businessId, []*database.NftTransfer{{
+			FromAddress:  deposit.FromAddress,
+			ToAddress:    deposit.ToAddress,
+			TokenAddress: deposit.TokenAddress,
+			TokenId:      deposit.TokenId,
+			TokenType:    deposit.TokenType,
+			Amount:       deposit.Amount,
+			TxType:       constant.TxTypeDeposit,
+		}}); err != nil {
+			return err
+		}
+	} else {
+		if err := tx.Balances.UpdateFallBackBalance(businessId, []*database.TokenBalance{{
+			FromAddress:  deposit.FromAddress,
+			ToAddress:    deposit.ToAddress,
+			TokenAddress: deposit.TokenAddress,
+			Balance:      deposit.Amount,
+			TxType:       constant.TxTypeDeposit,
+			TxHash:       deposit.TxHash,
+		}}); err != nil {
+			return err
+		}
+	}
+	return tx.Transactions.UpdateDepositFlowStatus(businessId, deposit, constant.TxStatusHold)
+}
+
+/*合并筛查与评分的风险原因*/
+func joinRiskReason(reasons ...string) string {
+	var joined string
+	for _, reason := range reasons {
+		if reason == "" {
+			continue
+		}
+		if joined != "" {
+			joined += "; "
+		}
+		joined += reason
+	}
+	return joined
+}
+
 /*停止发现器*/
 func (f *Finder) Stop() error {
 	var result error
@@ -141,11 +260,14 @@ func (f *Finder) Stop() error {
 热转冷、冷转热：库中原来有记录（项目方提交的），更新状态为已发现
 交易流水：入库 transaction 表
 */
-func (f *Finder) handleBatch(batch map[string]*BatchTransactions) error {
+func (f *Finder) handleBatch(ctx context.Context, batch map[string]*BatchTransactions) error {
+	db := f.BaseSynchronizer.database.WithContext(ctx)
+	rpcClient := f.BaseSynchronizer.rpcClient.WithContext(ctx)
+	logger := tracing.Logger(ctx)
 	/*查出项目方列表*/
-	businessList, err := f.BaseSynchronizer.database.Business.QueryBusinessList()
+	businessList, err := db.Business.QueryBusinessList()
 	if err != nil {
-		log.Error("failed to query business list", "err", err)
+		logger.Error("failed to query business list", "err", err)
 		return err
 	}
 	if businessList == nil || len(businessList) <= 0 {
@@ -172,48 +294,123 @@ func (f *Finder) handleBatch(batch map[string]*BatchTransactions) error {
 			internals []*database.Internals
 			/*余额表*/
 			balances []*database.TokenBalance
+			/*NFT 持有表*/
+			nftTransfers []*database.NftTransfer
+			/*手续费表*/
+			fees []*database.Fees
+			/*已记手续费的交易哈希，批量代币出款一笔交易有多条转账*/
+			feeHashes = make(map[string]bool)
 		)
-		log.Info("handle business flow", "businessId", business.BusinessUid, "chainLatestBlock", batch[business.BusinessUid].BlockHeight, "txn", len(batch[business.BusinessUid].Transactions))
+		/*代币白名单*/
+		whitelist, err := f.tokenWhitelist(business.BusinessUid)
+		if err != nil {
+			return err
+		}
+		/*近期交易对手，供相似地址检测*/
+		counterparties, err := db.Transactions.QueryRecentCounterparties(business.BusinessUid, f.screener.CounterpartyLimit())
+		if err != nil {
+			logger.Error("failed to query recent counterparties", "businessId", business.BusinessUid, "err", err)
+			return err
+		}
+		logger.Info("handle business flow", "businessId", business.BusinessUid, "chainLatestBlock", batch[business.BusinessUid].BlockHeight, "txn", len(batch[business.BusinessUid].Transactions))
+		/*各交易类型数量，入库成功后计入指标*/
+		txTypeCounts := make(map[constant.TransactionType]int)
 		for _, tx := range batch[business.BusinessUid].Transactions {
 			/*每笔交易分别处理*/
-			log.Info("Request transaction from chain account", "txHash", tx.Hash, "fromAddress", tx.FromAddress)
-			txItem, err := f.BaseSynchronizer.rpcClient.GetTransactionByHash(tx.Hash)
+			logger.Info("Request transaction from chain account", "txHash", tx.Hash, "fromAddress", tx.FromAddress)
+			txItem, err := rpcClient.GetTransactionByHash(tx.Hash)
 			if err != nil {
-				log.Info("failed to get transaction by hash", "hash", tx.Hash, "err", err)
+				logger.Info("failed to get transaction by hash", "hash", tx.Hash, "err", err)
 				return err
 			}
 			if txItem == nil {
 				err := fmt.Errorf("GetTransactionByHash txItem is nil: TxHash = %s", tx.Hash)
 				return err
 			}
-			amountBigInt, _ := new(big.Int).SetString(txItem.Value, 10)
-			log.Info("transaction amount", "amountBigInt", amountBigInt, "FromAddress", tx.FromAddress, "toAddress", tx.ToAddress, "TokenAddress", tx.TokenAddress, "txType", tx.TxType)
-
-			/*代币余额，ETH 主币余额*/
-			balances = append(
-				balances,
-				&database.TokenBalance{
-					FromAddress:  common.HexToAddress(tx.FromAddress),
-					ToAddress:    common.HexToAddress(tx.ToAddress),
-					TokenAddress: common.HexToAddress(tx.TokenAddress),
-					Balance:      amountBigInt,
-					TxType:       tx.TxType,
-				},
-			)
-
-			log.Info("get transaction success", "txHash", txItem.Hash)
+			amountBigInt := transactionAmount(tx, txItem)
+			logger.Info("transaction amount", "amountBigInt", amountBigInt, "FromAddress", tx.FromAddress, "toAddress", tx.ToAddress, "TokenAddress", tx.TokenAddress, "txType", tx.TxType)
+
+			/*充值 from 地址筛查：命中禁止名单挂起，不入账、不记流水；相似地址正常入账并标记风险原因*/
+			var riskReason string
+			if tx.TxType == constant.TxTypeDeposit {
+				result := f.screener.Screen(common.HexToAddress(tx.FromAddress), counterparties)
+				if result.Denied {
+					logger.Warn("deposit from address on deny list, hold it", "txHash", tx.Hash, "fromAddress", tx.FromAddress, "reason", result.Reason)
+					depositItem, _ := f.HandleDeposit(tx, txItem)
+					depositItem.Status = constant.TxStatusHold
+					depositItem.RiskAction = constant.RiskActionHold
+					depositItem.RiskReason = result.Reason
+					depositList = append(depositList, depositItem)
+					continue
+				}
+				if result.Lookalike {
+					logger.Warn("deposit from lookalike address, flag it", "txHash", tx.Hash, "fromAddress", tx.FromAddress, "reason", result.Reason)
+				}
+				riskReason = result.Reason
+			}
+
+			/*非白名单代币充值：隔离，不入账、不记流水、不通知*/
+			token, whitelisted := whitelist[common.HexToAddress(tx.TokenAddress)]
+			if tx.TxType == constant.TxTypeDeposit && !whitelisted {
+				logger.Warn("deposit token not in whitelist, quarantine it", "txHash", tx.Hash, "tokenAddress", tx.TokenAddress, "toAddress", tx.ToAddress)
+				depositItem, _ := f.HandleDeposit(tx, txItem)
+				depositItem.Status = constant.TxStatusQuarantine
+				depositList = append(depositList, depositItem)
+				continue
+			}
+			/*低于最小充值金额：记为粉尘，不入账（不参与归集）、不记流水、不通知*/
+			if tx.TxType == constant.TxTypeDeposit && isDust(token, amountBigInt) {
+				logger.Warn("deposit amount below minimum, mark as dust", "txHash", tx.Hash, "tokenAddress", tx.TokenAddress, "amount", amountBigInt, "minDepositAmount", token.MinDepositAmount)
+				depositItem, _ := f.HandleDeposit(tx, txItem)
+				depositItem.Status = constant.TxStatusDust
+				depositList = append(depositList, depositItem)
+				continue
+			}
+
+			if isNftTokenType(transactionTokenType(tx)) {
+				/*NFT 持有，不计入同质化余额*/
+				nftTransfers = append(
+					nftTransfers,
+					&database.NftTransfer{
+						FromAddress:  common.HexToAddress(tx.FromAddress),
+						ToAddress:    common.HexToAddress(tx.ToAddress),
+						TokenAddress: common.HexToAddress(tx.TokenAddress),
+						TokenId:      transactionTokenId(tx),
+						TokenType:    transactionTokenType(tx),
+						Amount:       amountBigInt,
+						TxType:       tx.TxType,
+					},
+				)
+			} else {
+				/*代币余额，ETH 主币余额*/
+				balances = append(
+					balances,
+					&database.TokenBalance{
+						FromAddress:  common.HexToAddress(tx.FromAddress),
+						ToAddress:    common.HexToAddress(tx.ToAddress),
+						TokenAddress: common.HexToAddress(tx.TokenAddress),
+						Balance:      amountBigInt,
+						TxType:       tx.TxType,
+						TxHash:       common.HexToHash(tx.Hash),
+					},
+				)
+			}
+
+			logger.Info("get transaction success", "txHash", txItem.Hash)
 			transactionFlow, err := f.BuildTransaction(tx, txItem)
 			if err != nil {
-				log.Info("handle  transaction fail", "err", err)
+				logger.Info("handle  transaction fail", "err", err)
 				return err
 			}
 			/*放入交易流水列表，等待入库*/
 			transactionFlowList = append(transactionFlowList, transactionFlow)
 
+			txTypeCounts[tx.TxType]++
 			switch tx.TxType {
 			/*充值*/
 			case constant.TxTypeDeposit:
 				depositItem, _ := f.HandleDeposit(tx, txItem)
+				depositItem.RiskReason = riskReason
 				depositList = append(depositList, depositItem)
 				break
 			/*提现*/
@@ -221,41 +418,69 @@ func (f *Finder) handleBatch(batch map[string]*BatchTransactions) error {
 				withdrawItem, _ := f.HandleWithdraw(tx, txItem)
 				withdrawList = append(withdrawList, withdrawItem)
 				break
-			/*内部（归集、转冷、转热）*/
-			case constant.TxTypeCollection, constant.TxTypeCold2Hot, constant.TxTypeHot2Cold:
+			/*内部（归集、转冷、转热、gas 补充）*/
+			case constant.TxTypeCollection, constant.TxTypeCold2Hot, constant.TxTypeHot2Cold, constant.TxTypeGasFunding:
 				internelItem, _ := f.HandleInternalTx(tx, txItem)
 				internals = append(internals, internelItem)
 				break
 			default:
 				break
 			}
+
+			/*钱包发出的交易记录手续费*/
+			if tx.TxType != constant.TxTypeDeposit && !feeHashes[tx.Hash] {
+				feeItem, err := f.HandleFee(tx, txItem)
+				if err != nil {
+					logger.Error("handle transaction fee fail", "txHash", tx.Hash, "err", err)
+					return err
+				}
+				if feeItem != nil {
+					fees = append(fees, feeItem)
+					feeHashes[tx.Hash] = true
+				}
+			}
 		}
 		/*数据库重试策略*/
 		retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
 		/*重试*/
 		if _, err := retry.Do[interface{}](f.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
 			/*事务*/
-			if err := f.BaseSynchronizer.database.Transaction(func(tx *database.DB) error {
+			if err := db.Transaction(func(tx *database.DB) error {
 				/* 1. 充值业务处理*/
 				if len(depositList) > 0 {
-					log.Info("Store deposit transaction success", "totalTx", len(depositList))
+					logger.Info("Store deposit transaction success", "totalTx", len(depositList))
 					if err := tx.Deposits.StoreDeposits(business.BusinessUid, depositList); err != nil {
 						return err
 					}
 				}
 				/* 3. 余额处理*/
 				if len(balances) > 0 {
-					log.Info("Handle balances transaction success", "totalTx", len(balances))
+					logger.Info("Handle balances transaction success", "totalTx", len(balances))
 					if err := tx.Balances.UpdateOrCreate(business.BusinessUid, balances); err != nil {
 						return err
 					}
 				}
+				/* 3.1 NFT 持有处理*/
+				if len(nftTransfers) > 0 {
+					logger.Info("Handle nft holdings success", "totalTx", len(nftTransfers))
+					if err := tx.NftHoldings.UpdateNftHoldings(business.BusinessUid, nftTransfers); err != nil {
+						return err
+					}
+				}
 				/* 4. 提现状态处理*/
 				/*todo 还需保存区块号、区块 hash，不然回滚会有 bug*/
 				if len(withdrawList) > 0 {
 					if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(business.BusinessUid, constant.TxStatusWalletDone, withdrawList); err != nil {
 						return err
 					}
+					/*批量提现批次与批次内提现共用交易哈希*/
+					var withdrawHashes []common.Hash
+					for _, withdraw := range withdrawList {
+						withdrawHashes = append(withdrawHashes, withdraw.TxHash)
+					}
+					if err := tx.WithdrawBatches.UpdateBatchStatusByTxHash(business.BusinessUid, constant.TxStatusWalletDone, withdrawHashes); err != nil {
+						return err
+					}
 				}
 
 				/* 5. 内部交易状态处理*/
@@ -272,25 +497,59 @@ func (f *Finder) handleBatch(batch map[string]*BatchTransactions) error {
 						return err
 					}
 				}
-				return nil
+
+				/* 7. 手续费入库，扣减付款地址主币余额*/
+				if len(fees) > 0 {
+					logger.Info("Store fees success", "totalTx", len(fees))
+					if err := tx.Fees.StoreFees(business.BusinessUid, fees); err != nil {
+						return err
+					}
+				}
+				/* 8. 查询接口缓存失效*/
+				return tx.CacheVersions.BumpCacheVersion(business.BusinessUid)
 			}); err != nil {
-				log.Error("unable to persist batch", "err", err)
+				logger.Error("unable to persist batch", "err", err)
 				return nil, err
 			}
 			return nil, nil
 		}); err != nil {
 			return err
 		}
+		for txType, count := range txTypeCounts {
+			metrics.AddTransactions(string(txType), count)
+		}
 
 	}
 	return nil
 
 }
 
+/*项目方代币白名单（合约地址 -> 代币配置），主币始终在白名单内，未配置时配置为空*/
+func (f *Finder) tokenWhitelist(businessId string) (map[common.Address]*database.Tokens, error) {
+	tokenList, err := f.BaseSynchronizer.database.Tokens.QueryTokensList(businessId)
+	if err != nil {
+		log.Error("failed to query token whitelist", "businessId", businessId, "err", err)
+		return nil, err
+	}
+	whitelist := map[common.Address]*database.Tokens{{}: nil}
+	for _, token := range tokenList {
+		whitelist[token.TokenAddress] = token
+	}
+	return whitelist, nil
+}
+
+/*是否低于代币最小充值金额*/
+func isDust(token *database.Tokens, amount *big.Int) bool {
+	if token == nil || token.MinDepositAmount == nil || amount == nil {
+		return false
+	}
+	return amount.Cmp(token.MinDepositAmount) < 0
+}
+
 /*构建交易流水记录*/
 func (f *Finder) BuildTransaction(tx *Transaction, txMsg *chainsunion.TxMessage) (*database.Transactions, error) {
 	txFee, _ := new(big.Int).SetString(txMsg.Fee, 10)
-	txAmount, _ := new(big.Int).SetString(txMsg.Value, 10)
+	txAmount := transactionAmount(tx, txMsg)
 	transationTx := &database.Transactions{
 		GUID:         uuid.New(),
 		BlockHash:    common.Hash{},
@@ -298,8 +557,9 @@ func (f *Finder) BuildTransaction(tx *Transaction, txMsg *chainsunion.TxMessage)
 		Hash:         common.HexToHash(tx.Hash),
 		FromAddress:  common.HexToAddress(tx.FromAddress),
 		ToAddress:    common.HexToAddress(tx.ToAddress),
+		TokenType:    transactionTokenType(tx),
 		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
+		TokenId:      transactionTokenId(tx),
 		TokenMeta:    "0x00",
 		Fee:          txFee,
 		Status:       constant.TxStatusSuccess, /* 充值扫到交易后则为成功*/
@@ -312,8 +572,11 @@ func (f *Finder) BuildTransaction(tx *Transaction, txMsg *chainsunion.TxMessage)
 
 /*充值记录构建*/
 func (f *Finder) HandleDeposit(tx *Transaction, txMsg *chainsunion.TxMessage) (*database.Deposits, error) {
-	//txFee, _ := new(big.Int).SetString(txMsg.Fee, 10)
-	txAmount, _ := new(big.Int).SetString(txMsg.Value, 10)
+	txFee, ok := new(big.Int).SetString(txMsg.Fee, 10)
+	if !ok {
+		txFee = big.NewInt(0)
+	}
+	txAmount := transactionAmount(tx, txMsg)
 	depositTx := &database.Deposits{
 		GUID:         uuid.New(),
 		BlockHash:    common.Hash{},
@@ -321,10 +584,11 @@ func (f *Finder) HandleDeposit(tx *Transaction, txMsg *chainsunion.TxMessage) (*
 		TxHash:       common.HexToHash(tx.Hash),
 		FromAddress:  common.HexToAddress(tx.FromAddress),
 		ToAddress:    common.HexToAddress(tx.ToAddress),
+		TokenType:    transactionTokenType(tx),
 		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
+		TokenId:      transactionTokenId(tx),
 		TokenMeta:    "0x00",
-		MaxFeePerGas: txMsg.Fee,
+		Fee:          txFee,
 		Amount:       txAmount,
 		Status:       constant.TxStatusSuccess, /* 充值扫到交易后则为成功*/
 		Timestamp:    uint64(time.Now().Unix()),
@@ -334,7 +598,7 @@ func (f *Finder) HandleDeposit(tx *Transaction, txMsg *chainsunion.TxMessage) (*
 
 func (f *Finder) HandleWithdraw(tx *Transaction, txMsg *chainsunion.TxMessage) (*database.Withdraws, error) {
 	//txFee, _ := new(big.Int).SetString(txMsg.Fee, 10)
-	txAmount, _ := new(big.Int).SetString(txMsg.Value, 10)
+	txAmount := transactionAmount(tx, txMsg)
 	withdrawTx := &database.Withdraws{
 		GUID:         uuid.New(),
 		BlockHash:    common.Hash{},
@@ -342,8 +606,9 @@ func (f *Finder) HandleWithdraw(tx *Transaction, txMsg *chainsunion.TxMessage) (
 		TxHash:       common.HexToHash(tx.Hash),
 		FromAddress:  common.HexToAddress(tx.FromAddress),
 		ToAddress:    common.HexToAddress(tx.ToAddress),
+		TokenType:    transactionTokenType(tx),
 		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
+		TokenId:      transactionTokenId(tx),
 		TokenMeta:    "0x00",
 		MaxFeePerGas: txMsg.Fee,
 		Amount:       txAmount,
@@ -355,7 +620,7 @@ func (f *Finder) HandleWithdraw(tx *Transaction, txMsg *chainsunion.TxMessage) (
 
 func (f *Finder) HandleInternalTx(tx *Transaction, txMsg *chainsunion.TxMessage) (*database.Internals, error) {
 	//txFee, _ := new(big.Int).SetString(txMsg.Fee, 10)
-	txAmount, _ := new(big.Int).SetString(txMsg.Value, 10)
+	txAmount := transactionAmount(tx, txMsg)
 	internalTx := &database.Internals{
 		GUID:         uuid.New(),
 		BlockHash:    common.Hash{},
@@ -363,8 +628,9 @@ func (f *Finder) HandleInternalTx(tx *Transaction, txMsg *chainsunion.TxMessage)
 		TxHash:       common.HexToHash(tx.Hash),
 		FromAddress:  common.HexToAddress(tx.FromAddress),
 		ToAddress:    common.HexToAddress(tx.ToAddress),
+		TokenType:    transactionTokenType(tx),
 		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
+		TokenId:      transactionTokenId(tx),
 		TokenMeta:    "0x00",
 		MaxFeePerGas: txMsg.Fee,
 		Amount:       txAmount,
@@ -373,3 +639,60 @@ func (f *Finder) HandleInternalTx(tx *Transaction, txMsg *chainsunion.TxMessage)
 	}
 	return internalTx, nil
 }
+
+/*手续费记录构建，链上未返回手续费时不记录*/
+func (f *Finder) HandleFee(tx *Transaction, txMsg *chainsunion.TxMessage) (*database.Fees, error) {
+	txFee, ok := new(big.Int).SetString(txMsg.Fee, 10)
+	if !ok || txFee.Sign() <= 0 {
+		log.Warn("transaction fee is empty, skip it", "txHash", tx.Hash, "fee", txMsg.Fee)
+		return nil, nil
+	}
+	payerType, err := database.FeePayerType(tx.TxType)
+	if err != nil {
+		return nil, err
+	}
+	return &database.Fees{
+		GUID:         uuid.New(),
+		TxHash:       common.HexToHash(tx.Hash),
+		TxType:       tx.TxType,
+		Address:      common.HexToAddress(tx.FromAddress),
+		AddressType:  payerType,
+		TokenAddress: common.HexToAddress(tx.TokenAddress),
+		Fee:          txFee,
+		BlockNumber:  tx.BlockNumber,
+		Status:       constant.TxStatusSuccess,
+		Timestamp:    uint64(time.Now().Unix()),
+	}, nil
+}
+
+/*交易金额：事件日志解析出的代币转账以日志金额为准，否则取链上交易 value*/
+func transactionAmount(tx *Transaction, txMsg *chainsunion.TxMessage) *big.Int {
+	if tx.Amount != nil {
+		return tx.Amount
+	}
+	txAmount, _ := new(big.Int).SetString(txMsg.Value, 10)
+	return txAmount
+}
+
+/*代币类型：事件日志解析出的以日志为准，否则按合约地址区分主币与 ERC-20*/
+func transactionTokenType(tx *Transaction) constant.TokenType {
+	if tx.TokenType != "" {
+		return tx.TokenType
+	}
+	if common.HexToAddress(tx.TokenAddress) == (common.Address{}) {
+		return constant.TokenTypeETH
+	}
+	return constant.TokenTypeERC20
+}
+
+/*token id：NFT 取日志中的 id，同质化代币沿用 0x00*/
+func transactionTokenId(tx *Transaction) string {
+	if tx.TokenId != nil {
+		return hexutil.EncodeBig(tx.TokenId)
+	}
+	return "0x00"
+}
+
+func isNftTokenType(tokenType constant.TokenType) bool {
+	return tokenType == constant.TokenTypeERC721 || tokenType == constant.TokenTypeERC1155
+}
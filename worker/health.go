@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"exchange-wallet-service/config"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/health"
+	"exchange-wallet-service/rpcclient"
+	"fmt"
+	"time"
+)
+
+/*
+扫链进程健康检查：
+* 存活：同步器超过 SyncStaleTimeout 没有进展视为卡死
+* 就绪：数据库、chains-union-rpc 可用，且不在回滚中
+*/
+func newHealthChecker(cfg *config.Config, db *database.DB, rpcClient *rpcclient.ChainsUnionRpcClient, synchronizer *BaseSynchronizer) *health.Checker {
+	checker := health.NewChecker()
+	checker.AddLiveness("synchronizer", func(_ context.Context) error {
+		return synchronizer.checkProgress(cfg.Health.SyncStaleTimeout)
+	})
+	checker.AddReadiness("database", db.Ping)
+	checker.AddReadiness("chainsunion", rpcClient.Ping)
+	checker.AddReadiness("fallback", func(_ context.Context) error {
+		return synchronizer.checkFallback()
+	})
+	return checker
+}
+
+/*同步器进展检查，timeout 为 0 不检查*/
+func (syncer *BaseSynchronizer) checkProgress(timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	lastProgress := time.Unix(0, syncer.lastProgress.Load())
+	if stale := time.Since(lastProgress); stale > timeout {
+		return fmt.Errorf("synchronizer made no progress for %s, last progress at %s", stale.Truncate(time.Second), lastProgress.Format(time.RFC3339))
+	}
+	return nil
+}
+
+/*回滚检查，回滚中数据不稳定，不就绪*/
+func (syncer *BaseSynchronizer) checkFallback() error {
+	since := syncer.fallbackSince.Load()
+	if since == 0 {
+		return nil
+	}
+	return fmt.Errorf("fallback reorg in progress since %s", time.Unix(0, since).Format(time.RFC3339))
+}
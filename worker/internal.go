@@ -8,6 +8,7 @@ import (
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
 	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/metrics"
 	"exchange-wallet-service/rpcclient"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
@@ -77,8 +78,21 @@ func (in *Internal) Start() error {
 					var balanceList []*database.Balances
 
 					for _, unSendTransaction := range unSendTransactionList {
+						/*代币归集等待 gas 补充交易上链后再发送*/
+						if unSendTransaction.GasFundingGuid != "" {
+							ready, err := in.gasFundingReady(business.BusinessUid, unSendTransaction.GasFundingGuid)
+							if err != nil {
+								log.Error("failed to check gas funding", "guid", unSendTransaction.GUID, "gasFundingGuid", unSendTransaction.GasFundingGuid, "err", err)
+								continue
+							}
+							if !ready {
+								log.Info("collection waiting for gas funding", "guid", unSendTransaction.GUID, "gasFundingGuid", unSendTransaction.GasFundingGuid)
+								continue
+							}
+						}
 						/*分单笔交易发送*/
 						txHash, err := in.rpcClient.SendTx(unSendTransaction.TxSignHex)
+						metrics.RecordBroadcast("internal", err)
 						if err != nil {
 							log.Error("failed to send internal transaction", "err", err)
 							continue
@@ -88,10 +102,15 @@ func (in *Internal) Start() error {
 								TokenAddress: unSendTransaction.TokenAddress,
 								Address:      unSendTransaction.FromAddress,
 								LockBalance:  unSendTransaction.Amount,
+								TxType:       unSendTransaction.TxType,
+								TxHash:       common.HexToHash(txHash),
 							}
 							/*todo 缺少 to 地址的余额处理？*/
 
-							balanceList = append(balanceList, balanceItem)
+							/*NFT 不占用同质化余额*/
+							if !isNftTokenType(unSendTransaction.TokenType) {
+								balanceList = append(balanceList, balanceItem)
+							}
 
 							unSendTransaction.TxHash = common.HexToHash(txHash)
 							unSendTransaction.Status = constant.TxStatusBroadcasted
@@ -117,7 +136,8 @@ func (in *Internal) Start() error {
 									return err
 								}
 							}
-							return nil
+							/*查询接口缓存失效*/
+							return tx.CacheVersions.BumpCacheVersion(business.BusinessUid)
 						}); err != nil {
 							log.Error("unable to persist batch", "err", err)
 							return nil, err
@@ -137,6 +157,23 @@ func (in *Internal) Start() error {
 	return nil
 }
 
+/*gas 补充交易是否已上链（发现器扫到后状态为 walletDone，之后为通知中、成功）*/
+func (in *Internal) gasFundingReady(businessId string, gasFundingGuid string) (bool, error) {
+	gasFunding, err := in.db.Internals.QueryInternalsById(businessId, gasFundingGuid)
+	if err != nil {
+		return false, err
+	}
+	if gasFunding == nil {
+		return false, fmt.Errorf("gas funding transaction %s not found", gasFundingGuid)
+	}
+	switch gasFunding.Status {
+	case constant.TxStatusWalletDone, constant.TxStatusNotified, constant.TxStatusSuccess:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
 func (in *Internal) Stop() error {
 	var result error
 	in.resourceCancel()
@@ -8,8 +8,12 @@ import (
 	"exchange-wallet-service/database"
 	"exchange-wallet-service/database/constant"
 	"exchange-wallet-service/httpclient"
+	"exchange-wallet-service/metrics"
+	"exchange-wallet-service/tracing"
 	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
 	"sync/atomic"
 	"time"
 )
@@ -79,6 +83,7 @@ func (nf *Notifier) Start() error {
 				for _, businessId := range nf.businessIds {
 					log.Info("start notifier business", "business", businessId, "txn", txn)
 
+					/*待通知交易必须从主库查询：通知后按交易哈希在主库更新状态，从库落后会重复通知*/
 					/*查出应通知的充值交易*/
 					needNotifyDeposits, err := nf.db.Deposits.QueryNotifyDeposits(businessId)
 					if err != nil {
@@ -96,25 +101,35 @@ func (nf *Notifier) Start() error {
 					}
 
 					/*构建通知请求体*/
-					notifyRequest, err := nf.BuildNotifyTransaction(needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
+					notifyRequest, err := nf.BuildNotifyTransaction(businessId, needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
 					if err != nil {
 						log.Error("Build notify transaction fail", "err", err)
+						continue
 					}
 					if notifyRequest.Txn == nil || len(notifyRequest.Txn) == 0 {
 						log.Warn("no notify transaction to notify, wait for notify")
 						continue
 					}
 
-					/*发送通知*/
-					notify, err := nf.notifier[businessId].BusinessNotify(notifyRequest)
+					/*发送通知，链路头随通知发给项目方*/
+					ctx, span := tracing.Start(nf.resourceCtx, "notifier.deliver",
+						attribute.String("business.id", businessId),
+						attribute.Int("notify.txn", len(notifyRequest.Txn)),
+					)
+					logger := tracing.Logger(ctx)
+					notifyStart := time.Now()
+					notify, err := nf.notifier[businessId].BusinessNotify(ctx, notifyRequest)
+					metrics.RecordNotify(notifyStart, err == nil && notify)
 					if err != nil {
-						log.Error("notify business platform fail", "err", err)
+						logger.Error("notify business platform fail", "err", err)
 					}
-					log.Info("After notify", "business", businessId, "notifyStatus", notify, "deposits", needNotifyDeposits, "err", err)
+					logger.Info("After notify", "business", businessId, "notifyStatus", notify, "deposits", needNotifyDeposits, "err", err)
+					span.SetAttributes(attribute.Bool("notify.success", notify))
 					err = nf.AfterNotify(businessId, notify, needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
 					if err != nil {
-						log.Error("change notified status fail", "err", err)
+						logger.Error("change notified status fail", "err", err)
 					}
+					tracing.End(span, err)
 
 				}
 			case <-nf.resourceCtx.Done():
@@ -136,24 +151,48 @@ func (nf *Notifier) AfterNotify(businessId string, notifySuccess bool, deposits
 	withdrawNotifyStatus := constant.TxStatusNotified
 	internalNotifyStatus := constant.TxStatusNotified
 
-	// 过滤状态为 0 的交易
-	var updateStutusDepositTxn []*database.Deposits
+	// 过滤状态为 0 的交易，筛查挂起的交易单独按 id 更新
+	var (
+		updateStutusDepositTxn []*database.Deposits
+		holdDeposits           []*database.Deposits
+		notifiedWithdraws      []*database.Withdraws
+		holdWithdraws          []*database.Withdraws
+	)
 	for _, deposit := range deposits {
-		if deposit.Status != constant.TxStatusCreateUnsigned {
+		if deposit.Status == constant.TxStatusHold {
+			holdDeposits = append(holdDeposits, deposit)
+		} else if deposit.Status != constant.TxStatusCreateUnsigned {
 			updateStutusDepositTxn = append(updateStutusDepositTxn, deposit)
 		}
 	}
+	for _, withdraw := range withdraws {
+		if withdraw.Status == constant.TxStatusHold {
+			holdWithdraws = append(holdWithdraws, withdraw)
+		} else {
+			notifiedWithdraws = append(notifiedWithdraws, withdraw)
+		}
+	}
 	/*更新通知前状态（待通知）*/
 	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
 	if _, err := retry.Do[interface{}](nf.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
 		if err := nf.db.Transaction(func(tx *database.DB) error {
-			if len(deposits) > 0 {
+			if len(updateStutusDepositTxn) > 0 {
 				if err := tx.Deposits.UpdateDepositsStatusByTxHash(businessId, depositsNotifyStatus, updateStutusDepositTxn); err != nil {
 					return err
 				}
 			}
-			if len(withdraws) > 0 {
-				if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(businessId, withdrawNotifyStatus, withdraws); err != nil {
+			for _, deposit := range holdDeposits {
+				if err := tx.Deposits.UpdateDepositStatusById(businessId, deposit.GUID.String(), constant.TxStatusHoldNotified); err != nil {
+					return err
+				}
+			}
+			if len(notifiedWithdraws) > 0 {
+				if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(businessId, withdrawNotifyStatus, notifiedWithdraws); err != nil {
+					return err
+				}
+			}
+			for _, withdraw := range holdWithdraws {
+				if err := tx.Withdraws.UpdateWithdrawStatusById(businessId, withdraw.GUID.String(), constant.TxStatusHoldNotified); err != nil {
 					return err
 				}
 			}
@@ -234,8 +273,26 @@ func (nf *Notifier) BeforeAfterNotify(businessId string, isBefore bool, notifySu
 	return nil
 }
 
-/*构建充值、提现、内部交易的通知请求*/
-func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdraws []*database.Withdraws, internals []*database.Internals) (*httpclient.NotifyRequest, error) {
+/*构建充值、提现、内部交易的通知请求，提现、内部交易的手续费取链上实际支付的手续费*/
+func (nf *Notifier) BuildNotifyTransaction(businessId string, deposits []*database.Deposits, withdraws []*database.Withdraws, internals []*database.Internals) (*httpclient.NotifyRequest, error) {
+	var txHashes []common.Hash
+	for _, withdraw := range withdraws {
+		txHashes = append(txHashes, withdraw.TxHash)
+	}
+	for _, internal := range internals {
+		txHashes = append(txHashes, internal.TxHash)
+	}
+	feeMap, err := nf.db.Fees.QueryFeesByTxHashes(businessId, txHashes)
+	if err != nil {
+		return nil, err
+	}
+	paidFee := func(txHash common.Hash, maxFeePerGas string) string {
+		if fee, ok := feeMap[txHash]; ok {
+			return fee.Fee.String()
+		}
+		return maxFeePerGas
+	}
+
 	var notifyTransactions []*httpclient.Transaction
 	for _, deposit := range deposits {
 		txItem := &httpclient.Transaction{
@@ -245,12 +302,14 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			FromAddress:  deposit.FromAddress.String(),
 			ToAddress:    deposit.ToAddress.String(),
 			Value:        deposit.Amount.String(),
-			Fee:          deposit.MaxFeePerGas,
+			Fee:          deposit.Fee.String(),
 			TxType:       deposit.TxType,
 			Confirms:     deposit.Confirms,
 			TokenAddress: deposit.TokenAddress.String(),
 			TokenId:      deposit.TokenId,
 			TokenMeta:    deposit.TokenMeta,
+			Hold:         deposit.Status == constant.TxStatusHold,
+			RiskReason:   deposit.RiskReason,
 		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
@@ -263,12 +322,14 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			FromAddress:  withdraw.FromAddress.String(),
 			ToAddress:    withdraw.ToAddress.String(),
 			Value:        withdraw.Amount.String(),
-			Fee:          withdraw.MaxFeePerGas,
+			Fee:          paidFee(withdraw.TxHash, withdraw.MaxFeePerGas),
 			TxType:       withdraw.TxType,
 			Confirms:     0,
 			TokenAddress: withdraw.TokenAddress.String(),
 			TokenId:      withdraw.TokenId,
 			TokenMeta:    withdraw.TokenMeta,
+			Hold:         withdraw.Status == constant.TxStatusHold,
+			RiskReason:   withdraw.RiskReason,
 		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
@@ -281,7 +342,7 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			FromAddress:  internal.FromAddress.String(),
 			ToAddress:    internal.ToAddress.String(),
 			Value:        internal.Amount.String(),
-			Fee:          internal.MaxFeePerGas,
+			Fee:          paidFee(internal.TxHash, internal.MaxFeePerGas),
 			TxType:       internal.TxType,
 			Confirms:     0,
 			TokenAddress: internal.TokenAddress.String(),
@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"exchange-wallet-service/common/tasks"
+	"exchange-wallet-service/config"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/httpclient"
+	"exchange-wallet-service/rpcclient"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"math/big"
+	"time"
+)
+
+/*
+余额对账任务：
+余额表完全靠增量维护，会与链上产生偏差，
+定时通过 chains-union-rpc 获取热、冷、用户地址的链上主币与代币余额，
+与库内总余额（balance + lock_balance）比对，差异写入对账表，可选通知项目方告警；
+同时校验余额表与账本推导的可用、锁定余额是否一致
+*/
+type Reconciler struct {
+	db          *database.DB
+	rpcClient   *rpcclient.ChainsUnionRpcClient
+	interval    time.Duration
+	alertEnable bool
+
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+}
+
+/*单个项目方一轮对账结果*/
+type ReconcileReport struct {
+	BusinessId string
+	RoundId    string
+	/*已比对的余额条数*/
+	Checked int
+	/*链上余额获取失败条数*/
+	Failed int
+	/*余额表与账本不一致条数*/
+	LedgerMismatches int
+	Discrepancies    []*database.Reconciliations
+}
+
+/*新建对账任务*/
+func NewReconciler(cfg *config.Config, db *database.DB, rpcClient *rpcclient.ChainsUnionRpcClient, shutdown context.CancelCauseFunc) (*Reconciler, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Reconciler{
+		db:             db,
+		rpcClient:      rpcClient,
+		interval:       cfg.Reconcile.Interval,
+		alertEnable:    cfg.Reconcile.AlertEnable,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in reconciler: %w", err))
+		}},
+	}, nil
+}
+
+/*启动定时对账*/
+func (r *Reconciler) Start() error {
+	if r.interval <= 0 {
+		log.Info("reconciler disabled")
+		return nil
+	}
+	log.Info("starting reconciler....", "interval", r.interval)
+	ticker := time.NewTicker(r.interval)
+	r.tasks.Go(func() error {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := r.ReconcileAll(); err != nil {
+					log.Error("failed to reconcile balances", "err", err)
+				}
+			case <-r.resourceCtx.Done():
+				log.Info("reconciler shutting down")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+/*停止对账任务*/
+func (r *Reconciler) Stop() error {
+	r.resourceCancel()
+	if err := r.tasks.Wait(); err != nil {
+		return fmt.Errorf("failed to await reconciler: %w", err)
+	}
+	log.Info("stop reconciler success")
+	return nil
+}
+
+/*所有项目方对账一轮，单个项目方失败不影响其他项目方*/
+func (r *Reconciler) ReconcileAll() ([]*ReconcileReport, error) {
+	businessList, err := r.db.Replica().Business.QueryBusinessList()
+	if err != nil {
+		log.Error("failed to query business list", "err", err)
+		return nil, err
+	}
+	var (
+		reports []*ReconcileReport
+		result  error
+	)
+	for _, business := range businessList {
+		report, err := r.reconcileBusiness(business)
+		if err != nil {
+			log.Error("failed to reconcile business", "businessId", business.BusinessUid, "err", err)
+			result = errors.Join(result, fmt.Errorf("reconcile business %s: %w", business.BusinessUid, err))
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, result
+}
+
+/*单个项目方对账*/
+func (r *Reconciler) reconcileBusiness(business *database.Business) (*ReconcileReport, error) {
+	latestBlock, err := r.rpcClient.GetBlockHeader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("get latest block fail: %w", err)
+	}
+	if latestBlock == nil {
+		return nil, errors.New("latest block is nil")
+	}
+	balanceList, err := r.db.Replica().Balances.QueryBalanceList(business.BusinessUid)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{
+		BusinessId: business.BusinessUid,
+		RoundId:    uuid.New().String(),
+	}
+	for _, balance := range balanceList {
+		if !r.verifyLedger(business.BusinessUid, balance) {
+			report.LedgerMismatches++
+		}
+
+		contractAddress := "0x00"
+		if balance.TokenAddress != (common.Address{}) {
+			contractAddress = balance.TokenAddress.String()
+		}
+		chainBalance, err := r.rpcClient.GetAccountBalance(balance.Address.String(), contractAddress)
+		if err != nil {
+			log.Warn("failed to get chain balance, skip", "address", balance.Address, "tokenAddress", balance.TokenAddress, "err", err)
+			report.Failed++
+			continue
+		}
+		report.Checked++
+
+		dbBalance := totalBalance(balance)
+		if dbBalance.Cmp(chainBalance) == 0 {
+			continue
+		}
+		difference := new(big.Int).Sub(chainBalance, dbBalance)
+		log.Warn("balance discrepancy found", "businessId", business.BusinessUid, "address", balance.Address, "tokenAddress", balance.TokenAddress, "dbBalance", dbBalance, "chainBalance", chainBalance, "difference", difference)
+		report.Discrepancies = append(report.Discrepancies, &database.Reconciliations{
+			GUID:         uuid.New(),
+			RoundId:      report.RoundId,
+			Address:      balance.Address,
+			AddressType:  balance.AddressType,
+			TokenAddress: balance.TokenAddress,
+			DbBalance:    dbBalance,
+			ChainBalance: chainBalance,
+			Difference:   difference.String(),
+			BlockNumber:  latestBlock.Number,
+			Timestamp:    uint64(time.Now().Unix()),
+		})
+	}
+
+	if err := r.db.Reconciliations.StoreReconciliations(business.BusinessUid, report.Discrepancies); err != nil {
+		return nil, fmt.Errorf("store reconciliations fail: %w", err)
+	}
+	log.Info("reconcile business done", "businessId", business.BusinessUid, "roundId", report.RoundId, "checked", report.Checked, "failed", report.Failed, "ledgerMismatches", report.LedgerMismatches, "discrepancies", len(report.Discrepancies))
+
+	if r.alertEnable && len(report.Discrepancies) > 0 {
+		r.alert(business, report)
+	}
+	return report, nil
+}
+
+/*余额表与账本推导余额比对，查询失败不计为不一致*/
+func (r *Reconciler) verifyLedger(businessId string, balance *database.Balances) bool {
+	ledgerBalance, err := r.db.Replica().Ledger.QueryLedgerBalance(businessId, balance.Address, balance.TokenAddress)
+	if err != nil {
+		log.Warn("failed to query ledger balance, skip", "address", balance.Address, "tokenAddress", balance.TokenAddress, "err", err)
+		return true
+	}
+	available := balance.Balance
+	if available == nil {
+		available = big.NewInt(0)
+	}
+	locked := balance.LockBalance
+	if locked == nil {
+		locked = big.NewInt(0)
+	}
+	if ledgerBalance.Available.Cmp(available) == 0 && ledgerBalance.Locked.Cmp(locked) == 0 {
+		return true
+	}
+	log.Warn("balance mismatch with ledger", "businessId", businessId, "address", balance.Address, "tokenAddress", balance.TokenAddress,
+		"balance", available, "ledgerAvailable", ledgerBalance.Available, "lockBalance", locked, "ledgerLocked", ledgerBalance.Locked)
+	return false
+}
+
+/*对账差异告警，失败只记日志*/
+func (r *Reconciler) alert(business *database.Business, report *ReconcileReport) {
+	client, err := httpclient.NewNotifyClient(business.NotifyUrl)
+	if err != nil {
+		log.Error("create alert client fail", "businessId", business.BusinessUid, "err", err)
+		return
+	}
+	alertRequest := &httpclient.AlertRequest{
+		Type:    "reconciliation",
+		RoundId: report.RoundId,
+	}
+	for _, discrepancy := range report.Discrepancies {
+		alertRequest.Discrepancies = append(alertRequest.Discrepancies, &httpclient.Discrepancy{
+			Address:      discrepancy.Address.String(),
+			AddressType:  discrepancy.AddressType.String(),
+			TokenAddress: discrepancy.TokenAddress.String(),
+			DbBalance:    discrepancy.DbBalance.String(),
+			ChainBalance: discrepancy.ChainBalance.String(),
+			Difference:   discrepancy.Difference,
+		})
+	}
+	if _, err := client.BusinessAlert(alertRequest); err != nil {
+		log.Error("alert business platform fail", "businessId", business.BusinessUid, "err", err)
+	}
+}
+
+/*库内总余额 = 可用余额 + 锁定余额*/
+func totalBalance(balance *database.Balances) *big.Int {
+	total := new(big.Int)
+	if balance.Balance != nil {
+		total.Add(total, balance.Balance)
+	}
+	if balance.LockBalance != nil {
+		total.Add(total, balance.LockBalance)
+	}
+	return total
+}
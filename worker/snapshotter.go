@@ -0,0 +1,184 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"exchange-wallet-service/common/tasks"
+	"exchange-wallet-service/config"
+	"exchange-wallet-service/database"
+	"exchange-wallet-service/rpcclient"
+	"fmt"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"math/big"
+	"time"
+)
+
+/*
+余额快照任务：
+余额表只保存当前余额，按区块间隔或每日（UTC）把各地址各代币的余额复制一份到快照表，
+记录快照时同步器已处理的最新区块及其时间，供时间点余额查询（如财务日终持仓证明）
+*/
+type Snapshotter struct {
+	db            *database.DB
+	interval      time.Duration
+	blockInterval uint64
+	daily         bool
+
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+}
+
+/*新建快照任务*/
+func NewSnapshotter(cfg *config.Config, db *database.DB, shutdown context.CancelCauseFunc) (*Snapshotter, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Snapshotter{
+		db:             db,
+		interval:       cfg.ChainNode.WorkerInterval,
+		blockInterval:  cfg.Snapshot.BlockInterval,
+		daily:          cfg.Snapshot.Daily,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in snapshotter: %w", err))
+		}},
+	}, nil
+}
+
+/*启动定时快照*/
+func (s *Snapshotter) Start() error {
+	if s.blockInterval == 0 && !s.daily {
+		log.Info("snapshotter disabled")
+		return nil
+	}
+	log.Info("starting snapshotter....", "blockInterval", s.blockInterval, "daily", s.daily)
+	ticker := time.NewTicker(s.interval)
+	s.tasks.Go(func() error {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.snapshotAll(); err != nil {
+					log.Error("failed to snapshot balances", "err", err)
+				}
+			case <-s.resourceCtx.Done():
+				log.Info("snapshotter shutting down")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+/*停止快照任务*/
+func (s *Snapshotter) Stop() error {
+	s.resourceCancel()
+	if err := s.tasks.Wait(); err != nil {
+		return fmt.Errorf("failed to await snapshotter: %w", err)
+	}
+	log.Info("stop snapshotter success")
+	return nil
+}
+
+/*所有项目方检查一轮，到期的项目方做快照*/
+func (s *Snapshotter) snapshotAll() error {
+	/*余额表只反映同步器已处理的区块，快照取已同步的最新区块而不是链上最新区块*/
+	latestBlock, err := s.db.Blocks.LatestBlocks()
+	if err != nil {
+		return fmt.Errorf("query latest synced block fail: %w", err)
+	}
+	if latestBlock == nil {
+		log.Info("no synced block yet, skip snapshot")
+		return nil
+	}
+	businessList, err := s.db.Business.QueryBusinessList()
+	if err != nil {
+		log.Error("failed to query business list", "err", err)
+		return err
+	}
+	var result error
+	for _, business := range businessList {
+		if err := s.snapshotBusiness(business.BusinessUid, latestBlock); err != nil {
+			log.Error("failed to snapshot business", "businessId", business.BusinessUid, "err", err)
+			result = errors.Join(result, fmt.Errorf("snapshot business %s: %w", business.BusinessUid, err))
+		}
+	}
+	return result
+}
+
+/*单个项目方快照*/
+func (s *Snapshotter) snapshotBusiness(businessId string, latestBlock *rpcclient.BlockHeader) error {
+	lastSnapshot, err := s.db.Snapshots.QueryLatestSnapshot(businessId)
+	if err != nil {
+		return err
+	}
+	if !s.snapshotDue(lastSnapshot, latestBlock) {
+		return nil
+	}
+	balanceList, err := s.db.Balances.QueryBalanceList(businessId)
+	if err != nil {
+		return err
+	}
+
+	timestamp := uint64(time.Now().Unix())
+	snapshots := make([]*database.BalanceSnapshots, 0, len(balanceList))
+	for _, balance := range balanceList {
+		snapshots = append(snapshots, &database.BalanceSnapshots{
+			GUID:         uuid.New(),
+			BlockNumber:  latestBlock.Number,
+			SnapshotTime: latestBlock.Timestamp,
+			Address:      balance.Address,
+			AddressType:  balance.AddressType,
+			TokenAddress: balance.TokenAddress,
+			Balance:      nonNil(balance.Balance),
+			LockBalance:  nonNil(balance.LockBalance),
+			Timestamp:    timestamp,
+		})
+	}
+	/*新快照与查询接口缓存失效在同一事务*/
+	if err := s.db.Transaction(func(tx *database.DB) error {
+		if err := tx.Snapshots.StoreSnapshots(businessId, snapshots); err != nil {
+			return err
+		}
+		return tx.CacheVersions.BumpCacheVersion(businessId)
+	}); err != nil {
+		return fmt.Errorf("store balance snapshots fail: %w", err)
+	}
+	log.Info("snapshot business balances done", "businessId", businessId, "blockNumber", latestBlock.Number, "count", len(snapshots))
+	return nil
+}
+
+/*
+是否需要快照：
+* 从未快照过
+* 最新区块距上次快照达到区块间隔
+* 开启每日快照且最新区块与上次快照不在同一 UTC 日
+*/
+func (s *Snapshotter) snapshotDue(lastSnapshot *database.BalanceSnapshots, latestBlock *rpcclient.BlockHeader) bool {
+	if lastSnapshot == nil {
+		return true
+	}
+	if latestBlock.Number.Cmp(lastSnapshot.BlockNumber) <= 0 {
+		return false
+	}
+	if s.blockInterval > 0 {
+		next := new(big.Int).Add(lastSnapshot.BlockNumber, new(big.Int).SetUint64(s.blockInterval))
+		if latestBlock.Number.Cmp(next) >= 0 {
+			return true
+		}
+	}
+	if s.daily {
+		lastDay := time.Unix(int64(lastSnapshot.SnapshotTime), 0).UTC().Format(time.DateOnly)
+		latestDay := time.Unix(int64(latestBlock.Timestamp), 0).UTC().Format(time.DateOnly)
+		return lastDay != latestDay
+	}
+	return false
+}
+
+func nonNil(value *big.Int) *big.Int {
+	if value == nil {
+		return big.NewInt(0)
+	}
+	return value
+}
@@ -7,10 +7,14 @@ import (
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
 	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/metrics"
 	"exchange-wallet-service/rpcclient"
+	"exchange-wallet-service/tracing"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +26,10 @@ type BaseSynchronizer struct {
 	businessChannels chan map[string]*BatchTransactions
 
 	rpcClient *rpcclient.ChainsUnionRpcClient
+	/*Transfer 事件日志来源，为空则只按交易顶层 from/to 识别*/
+	logSource rpcclient.TransferLogSource
+	/*内部调用追踪来源，为空则不识别内部调用转入的主币*/
+	traceSource rpcclient.TraceSource
 	/*批量扫块工具*/
 	blockBatch *rpcclient.BatchBlock
 	database   *database.DB
@@ -32,6 +40,11 @@ type BaseSynchronizer struct {
 	fallbackBlockHeader *rpcclient.BlockHeader
 	worker              *clock.LoopFn
 	isFallback          bool
+
+	/*最近一次同步有进展（处理完一批区块或已在链头）的时间，UnixNano*/
+	lastProgress atomic.Int64
+	/*开始回滚的时间，UnixNano，未回滚为 0*/
+	fallbackSince atomic.Int64
 }
 
 /*单个交易*/
@@ -44,6 +57,12 @@ type Transaction struct {
 	TokenAddress   string
 	ContractWallet string
 	TxType         constant.TransactionType
+	/*事件日志解析出的金额与日志序号，顶层交易为空*/
+	Amount   *big.Int
+	LogIndex uint
+	/*事件日志解析出的代币类型与 NFT token id，顶层交易为空*/
+	TokenType constant.TokenType
+	TokenId   *big.Int
 }
 
 /*一批交易*/
@@ -54,7 +73,7 @@ type BatchTransactions struct {
 }
 
 /*新建同步器*/
-func NewSynchronizer(cfg *config.Config, db *database.DB, rpcClient *rpcclient.ChainsUnionRpcClient, shutdown context.CancelCauseFunc) (*BaseSynchronizer, error) {
+func NewSynchronizer(cfg *config.Config, db *database.DB, rpcClient *rpcclient.ChainsUnionRpcClient, logSource rpcclient.TransferLogSource, traceSource rpcclient.TraceSource, shutdown context.CancelCauseFunc) (*BaseSynchronizer, error) {
 	/*获取数据库中最新区块*/
 	dbLatestBlockHeader, err := db.Blocks.LatestBlocks()
 	if err != nil {
@@ -91,11 +110,14 @@ func NewSynchronizer(cfg *config.Config, db *database.DB, rpcClient *rpcclient.C
 		headerBufferSize:    cfg.ChainNode.BlocksStep,
 		businessChannels:    make(chan map[string]*BatchTransactions),
 		rpcClient:           rpcClient,
+		logSource:           logSource,
+		traceSource:         traceSource,
 		blockBatch:          rpcclient.NewBatchBlock(rpcClient, fromHeader, big.NewInt(int64(cfg.ChainNode.Confirmations))),
 		database:            db,
 		isFallback:          false,
 		fallbackBlockHeader: nil,
 	}
+	baseSynchronizer.lastProgress.Store(time.Now().UnixNano())
 	return baseSynchronizer, nil
 }
 
@@ -122,7 +144,9 @@ func (syncer *BaseSynchronizer) Stop() error {
 }
 
 /*同步任务*/
-func (syncer *BaseSynchronizer) tick(_ context.Context) {
+func (syncer *BaseSynchronizer) tick(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "synchronizer.tick")
+	var fetchErr error
 	/*本次任务还在处理，跳过获取，直接处理区块*/
 	if len(syncer.headers) > 0 {
 		log.Info("retrying previous batch")
@@ -130,6 +154,7 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 		/*  调用 NextHeaders 获取新的 headers 批次*/
 		newHeaders, fallBackHeader, isReorg, err := syncer.blockBatch.NextHeaders(syncer.headerBufferSize)
 		if err != nil {
+			fetchErr = err
 			/*链重组(发生回滚)或者 fallback 回滚标记*/
 			if isReorg && errors.Is(err, rpcclient.ErrBlockFallBack) {
 				/*非回滚状态（第一次发生回滚状态），则标记回滚状态和回头区块*/
@@ -137,6 +162,7 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 					log.Warn("found block fallback, start fallback task")
 					syncer.isFallback = true
 					syncer.fallbackBlockHeader = fallBackHeader
+					syncer.fallbackSince.Store(time.Now().UnixNano())
 				} else {
 					log.Warn("the block fallback, fallback task handling it now")
 				}
@@ -154,11 +180,29 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 	}
 
 	/*处理这一批次区块*/
-	err := syncer.processBatch(syncer.headers)
+	blockCount := len(syncer.headers)
+	span.SetAttributes(attribute.Int("sync.blocks", blockCount))
+	err := syncer.processBatch(ctx, syncer.headers)
+	tracing.End(span, errors.Join(fetchErr, err))
 	/*成功则清空 headers，进入到下一轮*/
 	if err == nil {
 		syncer.headers = nil
+		metrics.AddBlocksProcessed(blockCount)
+		if fetchErr == nil {
+			syncer.lastProgress.Store(time.Now().UnixNano())
+		}
+	}
+	syncer.recordSyncLag()
+}
+
+/*记录同步落后区块数：链上最新高度 - 已遍历高度*/
+func (syncer *BaseSynchronizer) recordSyncLag() {
+	latestHeader := syncer.blockBatch.LatestHeader()
+	lastTraversedHeader := syncer.blockBatch.LastTraversedHeader()
+	if latestHeader == nil || lastTraversedHeader == nil {
+		return
 	}
+	metrics.SetSyncLag(latestHeader.Number.Uint64(), lastTraversedHeader.Number.Uint64())
 }
 
 /*
@@ -166,11 +210,13 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 根据区块头获取区块内的交易，
 按项目方 id 进行分类，打上标记，放入 BusinessChannel 中
 */
-func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) error {
+func (syncer *BaseSynchronizer) processBatch(ctx context.Context, headers []rpcclient.BlockHeader) error {
 	/*无数据，无须处理*/
 	if len(headers) == 0 {
 		return nil
 	}
+	db := syncer.database.WithContext(ctx)
+	rpcClient := syncer.rpcClient.WithContext(ctx)
 	/*项目方的 map*/
 	businessTxsMap := make(map[string]*BatchTransactions)
 	/*存库用*/
@@ -186,13 +232,32 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 			Timestamp:  header.Timestamp,
 		}
 		/*获取此块交易*/
-		txList, err := syncer.rpcClient.GetBlockInfo(header.Number)
+		txList, err := rpcClient.GetBlockInfo(header.Number)
 		if err != nil {
 			log.Error("get block info fail", "err", err)
 			return err
 		}
+		/*获取此块 Transfer 事件日志*/
+		var transfers []*rpcclient.TokenTransfer
+		if syncer.logSource != nil {
+			logs, err := syncer.logSource.TransferLogs(header.Number)
+			if err != nil {
+				log.Error("get transfer logs fail", "err", err)
+				return err
+			}
+			transfers = rpcclient.DecodeTransferLogs(logs)
+		}
+		/*获取此块内部调用转账*/
+		var internalTransfers []*rpcclient.InternalTransfer
+		if syncer.traceSource != nil {
+			internalTransfers, err = syncer.traceSource.InternalTransfers(header.Number)
+			if err != nil {
+				log.Error("get internal transfers fail", "err", err)
+				return err
+			}
+		}
 		/*数据库中查询项目方列表*/
-		businessList, err := syncer.database.Business.QueryBusinessList()
+		businessList, err := db.Business.QueryBusinessList()
 		if err != nil {
 			log.Error("get business list fail", "err", err)
 			return err
@@ -204,18 +269,14 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 			var businessTransactions []*Transaction
 			/*每个项目方，遍历这个交易中的全量交易*/
 			for _, tx := range txList {
-				toAddress := common.HexToAddress(tx.To)
-				fromAddress := common.HexToAddress(tx.From)
-				/*库中是否存在 to 地址和 to 地址类型*/
-				existToAddress, toAddressType := syncer.database.Address.AddressExist(business.BusinessUid, &toAddress)
-				/*库中是否存在 from 地址和 from 地址类型*/
-				existFromAddress, FromAddressType := syncer.database.Address.AddressExist(business.BusinessUid, &fromAddress)
-
-				/*都不存在，与本项目方无关，跳过*/
-				if !existToAddress && !existFromAddress {
+				/*开启事件日志解析时，代币转账以日志为准，顶层交易只处理主币*/
+				if syncer.logSource != nil && common.HexToAddress(tx.TokenAddress) != (common.Address{}) {
+					continue
+				}
+				txType, ok := syncer.classifyTransaction(business.BusinessUid, tx.Hash, common.HexToAddress(tx.From), common.HexToAddress(tx.To))
+				if !ok {
 					continue
 				}
-				log.Info("found transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress, "fromAddressType", FromAddressType, "toAddressType", toAddressType)
 
 				/*组装交易*/
 				txItem := &Transaction{
@@ -226,43 +287,49 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 					Hash:           tx.Hash,
 					TokenAddress:   tx.TokenAddress,
 					ContractWallet: tx.ContractWallet,
-					TxType:         constant.TxTypeUnKnow, // 暂设未知
+					TxType:         txType,
 				}
 
-				/*
-				* 充值：from 地址为外部地址，to 地址为用户地址
-				* 提现：from 地址为热钱包地址，to 地址为外部地址
-				* 归集：from 地址为用户地址，to 地址为热钱包地址（默认热钱包地址为归集地址）
-				* 热转冷：from 地址为热钱包地址，to 地址为冷钱包地址
-				* 冷转热：from 地址为冷钱包地址，to 地址为热钱包地址
-				 */
-
-				if !existFromAddress && (existToAddress && toAddressType == constant.AddressTypeUser) {
-					/* 1.充值*/
-					log.Info("Found deposit transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = constant.TxTypeDeposit
-				} else if (existFromAddress && FromAddressType == constant.AddressTypeHot) && !existToAddress {
-					/* 2.提现*/
-					log.Info("Found withdraw transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = constant.TxTypeWithdraw
-				} else if (existFromAddress && FromAddressType == constant.AddressTypeUser) && (existToAddress && toAddressType == constant.AddressTypeHot) {
-					/* 3.归集*/
-					log.Info("Found collection transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = constant.TxTypeCollection
-				} else if (existFromAddress && FromAddressType == constant.AddressTypeHot) && (existToAddress && toAddressType == constant.AddressTypeCold) {
-					/* 4.热转冷*/
-					log.Info("Found hot2cold transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = constant.TxTypeHot2Cold
-				} else if (existFromAddress && FromAddressType == constant.AddressTypeCold) && (existToAddress && toAddressType == constant.AddressTypeHot) {
-					/* 5.冷转热*/
-					log.Info("Found cold2hot transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = constant.TxTypeCold2Hot
-				} else {
-					/*都不命中不处理*/
+				/*项目方的交易列表*/
+				businessTransactions = append(businessTransactions, txItem)
+			}
+			/*每个项目方，遍历事件日志中的代币转账（一条日志一笔交易）*/
+			for _, transfer := range transfers {
+				txType, ok := syncer.classifyTransaction(business.BusinessUid, transfer.TxHash.String(), transfer.From, transfer.To)
+				if !ok {
 					continue
 				}
-
-				/*项目方的交易列表*/
+				txItem := &Transaction{
+					BusinessId:   business.BusinessUid,
+					BlockNumber:  headers[i].Number,
+					FromAddress:  transfer.From.String(),
+					ToAddress:    transfer.To.String(),
+					Hash:         transfer.TxHash.String(),
+					TokenAddress: transfer.TokenAddress.String(),
+					TxType:       txType,
+					Amount:       transfer.Amount,
+					LogIndex:     transfer.LogIndex,
+					TokenType:    transfer.TokenType,
+					TokenId:      transfer.TokenId,
+				}
+				businessTransactions = append(businessTransactions, txItem)
+			}
+			/*每个项目方，遍历内部调用转账，只作为充值处理（转入用户地址的主币）*/
+			for _, transfer := range internalTransfers {
+				txType, ok := syncer.classifyTransaction(business.BusinessUid, transfer.TxHash.String(), transfer.From, transfer.To)
+				if !ok || txType != constant.TxTypeDeposit {
+					continue
+				}
+				log.Info("Found internal deposit transaction", "txHash", transfer.TxHash, "from", transfer.From, "to", transfer.To, "depth", transfer.Depth)
+				txItem := &Transaction{
+					BusinessId:  business.BusinessUid,
+					BlockNumber: headers[i].Number,
+					FromAddress: transfer.From.String(),
+					ToAddress:   transfer.To.String(),
+					Hash:        transfer.TxHash.String(),
+					TxType:      txType,
+					Amount:      transfer.Value,
+				}
 				businessTransactions = append(businessTransactions, txItem)
 			}
 			if len(businessTransactions) > 0 {
@@ -283,7 +350,7 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 	/*将区块存储到表中*/
 	if len(blockHeaders) > 0 {
 		log.Info("Store block headers success", "totalBlockHeader size", len(blockHeaders))
-		if err := syncer.database.Blocks.StoreBlocks(blockHeaders); err != nil {
+		if err := db.Blocks.StoreBlocks(blockHeaders); err != nil {
 			return err
 		}
 	}
@@ -294,3 +361,53 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 	}
 	return nil
 }
+
+/*
+根据 from/to 地址判断交易类型，与本项目方无关返回 false
+* 充值：from 地址为外部地址，to 地址为用户地址
+* 提现：from 地址为热钱包地址，to 地址为外部地址
+* 归集：from 地址为用户地址，to 地址为热钱包地址（默认热钱包地址为归集地址）
+* 热转冷：from 地址为热钱包地址，to 地址为冷钱包地址
+* 冷转热：from 地址为冷钱包地址，to 地址为热钱包地址
+* gas 补充：from 地址为热钱包地址，to 地址为用户地址
+*/
+func (syncer *BaseSynchronizer) classifyTransaction(businessId string, txHash string, fromAddress, toAddress common.Address) (constant.TransactionType, bool) {
+	/*库中是否存在 to 地址和 to 地址类型*/
+	existToAddress, toAddressType := syncer.database.Address.AddressExist(businessId, &toAddress)
+	/*库中是否存在 from 地址和 from 地址类型*/
+	existFromAddress, FromAddressType := syncer.database.Address.AddressExist(businessId, &fromAddress)
+
+	/*都不存在，与本项目方无关，跳过*/
+	if !existToAddress && !existFromAddress {
+		return constant.TxTypeUnKnow, false
+	}
+	log.Info("found transaction", "txHash", txHash, "from", fromAddress, "to", toAddress, "fromAddressType", FromAddressType, "toAddressType", toAddressType)
+
+	if !existFromAddress && (existToAddress && toAddressType == constant.AddressTypeUser) {
+		/* 1.充值*/
+		log.Info("Found deposit transaction", "txHash", txHash, "from", fromAddress, "to", toAddress)
+		return constant.TxTypeDeposit, true
+	} else if (existFromAddress && FromAddressType == constant.AddressTypeHot) && !existToAddress {
+		/* 2.提现*/
+		log.Info("Found withdraw transaction", "txHash", txHash, "from", fromAddress, "to", toAddress)
+		return constant.TxTypeWithdraw, true
+	} else if (existFromAddress && FromAddressType == constant.AddressTypeUser) && (existToAddress && toAddressType == constant.AddressTypeHot) {
+		/* 3.归集*/
+		log.Info("Found collection transaction", "txHash", txHash, "from", fromAddress, "to", toAddress)
+		return constant.TxTypeCollection, true
+	} else if (existFromAddress && FromAddressType == constant.AddressTypeHot) && (existToAddress && toAddressType == constant.AddressTypeCold) {
+		/* 4.热转冷*/
+		log.Info("Found hot2cold transaction", "txHash", txHash, "from", fromAddress, "to", toAddress)
+		return constant.TxTypeHot2Cold, true
+	} else if (existFromAddress && FromAddressType == constant.AddressTypeCold) && (existToAddress && toAddressType == constant.AddressTypeHot) {
+		/* 5.冷转热*/
+		log.Info("Found cold2hot transaction", "txHash", txHash, "from", fromAddress, "to", toAddress)
+		return constant.TxTypeCold2Hot, true
+	} else if (existFromAddress && FromAddressType == constant.AddressTypeHot) && (existToAddress && toAddressType == constant.AddressTypeUser) {
+		/* 6.gas 补充*/
+		log.Info("Found gas funding transaction", "txHash", txHash, "from", fromAddress, "to", toAddress)
+		return constant.TxTypeGasFunding, true
+	}
+	/*都不命中不处理*/
+	return constant.TxTypeUnKnow, false
+}
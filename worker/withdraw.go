@@ -8,6 +8,7 @@ import (
 	"exchange-wallet-service/config"
 	"exchange-wallet-service/database"
 	"exchange-wallet-service/database/constant"
+	"exchange-wallet-service/metrics"
 	"exchange-wallet-service/rpcclient"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
@@ -39,6 +40,10 @@ func (w *Withdraw) Start() error {
 					continue
 				}
 				for _, business := range businessList {
+					/*批量提现批次交易*/
+					if err := w.sendBatches(business.BusinessUid); err != nil {
+						return err
+					}
 					/*每个项目方处理已签名但未发出的交易*/
 					unSendTransactionList, err := w.db.Withdraws.UnSendWithdrawsList(business.BusinessUid)
 					if err != nil {
@@ -55,18 +60,23 @@ func (w *Withdraw) Start() error {
 					for _, unSendTransaction := range unSendTransactionList {
 						/*每一笔提现交易发出去*/
 						txHash, err := w.rpcClient.SendTx(unSendTransaction.TxSignHex)
+						metrics.RecordBroadcast("withdraw", err)
 						if err != nil {
 							log.Error("failed to send transaction", "err", err)
 							continue
 						} else {
-							/*成功更新余额*/
-							balanceItem := &database.Balances{
-								TokenAddress: unSendTransaction.TokenAddress,
-								Address:      unSendTransaction.FromAddress,
-								/*发出提现，balance-，lockBalance+，*/
-								LockBalance: unSendTransaction.Amount,
+							/*成功更新余额，NFT 不占用同质化余额*/
+							if !isNftTokenType(unSendTransaction.TokenType) {
+								balanceItem := &database.Balances{
+									TokenAddress: unSendTransaction.TokenAddress,
+									Address:      unSendTransaction.FromAddress,
+									/*发出提现，balance-，lockBalance+，*/
+									LockBalance: unSendTransaction.Amount,
+									TxType:      unSendTransaction.TxType,
+									TxHash:      common.HexToHash(txHash),
+								}
+								balanceList = append(balanceList, balanceItem)
 							}
-							balanceList = append(balanceList, balanceItem)
 							unSendTransaction.TxHash = common.HexToHash(txHash)
 							/*已广播，未确认*/
 							unSendTransaction.Status = constant.TxStatusBroadcasted
@@ -95,7 +105,8 @@ func (w *Withdraw) Start() error {
 									return err
 								}
 							}
-							return nil
+							/*查询接口缓存失效*/
+							return tx.CacheVersions.BumpCacheVersion(business.BusinessUid)
 						}); err != nil {
 							return err, nil
 						}
@@ -114,6 +125,53 @@ func (w *Withdraw) Start() error {
 	return nil
 }
 
+/*发送已签名的批量提现交易：热钱包锁定批次总额，批次与批次内提现记录交易哈希*/
+func (w *Withdraw) sendBatches(businessId string) error {
+	batches, err := w.db.WithdrawBatches.UnSendBatchList(businessId)
+	if err != nil {
+		log.Error("failed to query unsend withdraw batches", "businessId", businessId, "err", err)
+		return fmt.Errorf("query unsend withdraw batches failed: %w", err)
+	}
+	for _, batch := range batches {
+		txHash, err := w.rpcClient.SendTx(batch.TxSignHex)
+		metrics.RecordBroadcast("withdraw_batch", err)
+		if err != nil {
+			log.Error("failed to send batch transaction", "batchId", batch.GUID, "err", err)
+			continue
+		}
+		batch.TxHash = common.HexToHash(txHash)
+		balanceList := []*database.Balances{
+			{
+				TokenAddress: batch.TokenAddress,
+				Address:      batch.FromAddress,
+				LockBalance:  batch.TotalAmount,
+				TxType:       constant.TxTypeWithdraw,
+				TxHash:       batch.TxHash,
+			},
+		}
+		retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+		if _, err := retry.Do[interface{}](w.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+			if err := w.db.Transaction(func(tx *database.DB) error {
+				if err := tx.Balances.UpdateBalanceListByTwoAddress(businessId, balanceList); err != nil {
+					log.Error("failed to update batch withdraw balance", "err", err)
+					return err
+				}
+				if err := tx.WithdrawBatches.UpdateBatchBroadcasted(businessId, batch); err != nil {
+					return err
+				}
+				return tx.CacheVersions.BumpCacheVersion(businessId)
+			}); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}); err != nil {
+			log.Error("failed to store broadcasted withdraw batch", "batchId", batch.GUID, "txHash", batch.TxHash, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
 /*停止提现任务*/
 func (w *Withdraw) Stop() error {
 	var result error